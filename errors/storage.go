@@ -42,4 +42,22 @@ var (
 
 	// ErrInvalidFetchResult is an error returned when fetch result is invalid.
 	ErrInvalidFetchResult = errors.New("invalid fetch result")
+
+	// ErrInvalidStepSize is returned when fetching blocks without a positive step size.
+	ErrInvalidStepSize = errors.New("step size must be positive to fetch blocks")
+
+	// ErrBlockBoundsMismatch is returned when merging blocks whose steps do not line up.
+	ErrBlockBoundsMismatch = errors.New("blocks must share steps to be merged")
+
+	// ErrRemoteBlockBounds is returned when a remote store returns a block whose bounds differ from the query.
+	ErrRemoteBlockBounds = errors.New("remote block bounds do not match the query")
+
+	// ErrNoNamespaces is returned when creating local storage without namespaces.
+	ErrNoNamespaces = errors.New("no namespaces configured for local storage")
+
+	// ErrNoUnaggregatedNamespace is returned when creating local storage without a namespace to write to.
+	ErrNoUnaggregatedNamespace = errors.New("no unaggregated namespace configured for local storage")
+
+	// ErrInvalidTransform is returned when a pushed down transform does not hold exactly one transform.
+	ErrInvalidTransform = errors.New("pushed down transform must hold exactly one transform")
 )
@@ -23,6 +23,9 @@ package executor
 import (
 	"context"
 
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 )
 
@@ -82,6 +85,85 @@ func (e *Engine) Execute(ctx context.Context, query *storage.FetchQuery, opts *E
 	results <- &storage.QueryResult{FetchResult: result}
 }
 
+// ExecuteRaw streams the raw series of the query from the store, the query is tracked like the queries
+// the engine executes so that it is killed once closing fires
+func (e *Engine) ExecuteRaw(ctx context.Context, raw storage.RawQuerier, query *storage.FetchQuery, closing <-chan bool, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	task, err := e.tracker.Track(query, closing)
+	if err != nil {
+		return storage.RawResult{}, err
+	}
+
+	defer e.tracker.DetachQuery(task.qid)
+
+	return raw.FetchRaw(ctx, query, &storage.FetchOptions{
+		KillChan: task.closing,
+	}, fn)
+}
+
+// Query is the result after execution
+type Query struct {
+	Err    error
+	Result Result
+	// Warnings are the stores left out of the result
+	Warnings []storage.Warning
+}
+
+// ExecuteExpr runs the query DAG and closes the results channel once done
+func (e *Engine) ExecuteExpr(ctx context.Context, p parser.Parser, opts *EngineOptions, params models.RequestParams, closing <-chan bool, results chan Query) {
+	defer close(results)
+	task, err := e.tracker.Track(p, closing)
+	if err != nil {
+		select {
+		case results <- Query{Err: err}:
+		case <-opts.AbortCh:
+		}
+		return
+	}
+
+	defer e.tracker.DetachQuery(task.qid)
+
+	state, err := e.generateExecutionState(p, params, task.closing)
+	if err != nil {
+		select {
+		case results <- Query{Err: err}:
+		case <-opts.AbortCh:
+		}
+		return
+	}
+
+	if err := state.Execute(ctx); err != nil {
+		select {
+		case results <- Query{Err: err}:
+		case <-opts.AbortCh:
+		}
+		return
+	}
+
+	select {
+	case results <- Query{Result: state.Result(), Warnings: state.Warnings()}:
+	case <-opts.AbortCh:
+	}
+}
+
+func (e *Engine) generateExecutionState(p parser.Parser, params models.RequestParams, killChan chan struct{}) (*ExecutionState, error) {
+	nodes, edges, err := p.DAG()
+	if err != nil {
+		return nil, err
+	}
+
+	lp, err := plan.NewLogicalPlan(nodes, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	pp, err := plan.NewPhysicalPlan(lp, e.store, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return GenerateExecutionState(pp, e.store, killChan)
+}
+
 // Close kills all running queries and prevents new queries from being attached.
 func (e *Engine) Close() error {
 	return e.tracker.Close()
@@ -24,13 +24,18 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser/promql"
 	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
 	"github.com/m3db/m3coordinator/test/local"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExecute(t *testing.T) {
@@ -48,3 +53,64 @@ func TestExecute(t *testing.T) {
 	<-results
 	assert.Equal(t, len(engine.tracker.queries), 1)
 }
+
+func TestExecuteExpr(t *testing.T) {
+	parser, err := promql.Parse("foo")
+	require.NoError(t, err)
+
+	results := make(chan Query)
+	closing := make(chan bool)
+	engine := NewEngine(mock.NewMockStorage())
+	go engine.ExecuteExpr(context.TODO(), parser, &EngineOptions{}, models.RequestParams{
+		Start: time.Now().Add(-2 * time.Minute),
+		End:   time.Now(),
+		Now:   time.Now(),
+		Step:  time.Minute,
+	}, closing, results)
+
+	result := <-results
+	require.NoError(t, result.Err)
+	assert.Empty(t, result.Result.Blocks())
+	_, open := <-results
+	assert.False(t, open)
+}
+
+// killedStorage streams no series and records whether the query was killed once kill is called
+type killedStorage struct {
+	storage.Storage
+	kill   func()
+	killed bool
+}
+
+func (s *killedStorage) FetchRaw(ctx context.Context, _ *storage.FetchQuery, options *storage.FetchOptions, _ storage.RawSeriesFn) (storage.RawResult, error) {
+	if s.kill != nil {
+		s.kill()
+	}
+
+	select {
+	case <-options.KillChan:
+		s.killed = true
+	default:
+	}
+
+	return storage.RawResult{}, nil
+}
+
+func TestExecuteRaw(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &killedStorage{Storage: mock.NewMockStorage()}
+	engine := NewEngine(store)
+	_, err := engine.ExecuteRaw(context.TODO(), store, &storage.FetchQuery{}, make(chan bool), nil)
+	require.NoError(t, err)
+	assert.False(t, store.killed)
+	assert.Empty(t, engine.tracker.queries)
+
+	store.kill = func() {
+		for qid := range engine.tracker.queries {
+			require.NoError(t, engine.tracker.KillQuery(qid))
+		}
+	}
+	_, err = engine.ExecuteRaw(context.TODO(), store, &storage.FetchQuery{}, make(chan bool), nil)
+	require.NoError(t, err)
+	assert.True(t, store.killed)
+}
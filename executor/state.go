@@ -65,8 +65,9 @@ type SourceParams interface {
 	Node(controller *transform.Controller, storage storage.Storage, options transform.Options) parser.Source
 }
 
-// GenerateExecutionState creates an execution state from the physical plan
-func GenerateExecutionState(pplan plan.PhysicalPlan, storage storage.Storage) (*ExecutionState, error) {
+// GenerateExecutionState creates an execution state from the physical plan, the fetches of its sources are
+// killed once killChan is closed
+func GenerateExecutionState(pplan plan.PhysicalPlan, storage storage.Storage, killChan chan struct{}) (*ExecutionState, error) {
 	result := pplan.ResultStep
 	state := &ExecutionState{
 		plan:    pplan,
@@ -79,7 +80,8 @@ func GenerateExecutionState(pplan plan.PhysicalPlan, storage storage.Storage) (*
 	}
 
 	options := transform.Options{
-		Now: pplan.Now,
+		TimeSpec: pplan.TimeSpec,
+		KillChan: killChan,
 	}
 	controller, err := state.createNode(step, options)
 	if err != nil {
@@ -90,7 +92,7 @@ func GenerateExecutionState(pplan plan.PhysicalPlan, storage storage.Storage) (*
 		return nil, errors.New("empty sources for the execution state")
 	}
 
-	rNode := ResultNode{}
+	rNode := &ResultNode{}
 	state.resultNode = rNode
 	controller.AddTransform(rNode)
 
@@ -141,6 +143,28 @@ func (s *ExecutionState) Execute(ctx context.Context) error {
 	return execution.ExecuteParallel(ctx, requests)
 }
 
+// Result provides the results of the execution, it is only complete once Execute has returned
+func (s *ExecutionState) Result() Result {
+	return s.resultNode
+}
+
+// warningSource is implemented by sources whose fetches can leave stores out of their results
+type warningSource interface {
+	Warnings() []storage.Warning
+}
+
+// Warnings returns the stores left out of the results of the sources, it is only complete once Execute has returned
+func (s *ExecutionState) Warnings() []storage.Warning {
+	var warnings []storage.Warning
+	for _, source := range s.sources {
+		if ws, ok := source.(warningSource); ok {
+			warnings = append(warnings, ws.Warnings()...)
+		}
+	}
+
+	return warnings
+}
+
 // String representation of the state
 func (s *ExecutionState) String() string {
 	return fmt.Sprintf("plan: %s\nsources: %s\nresult: %s", s.plan, s.sources, s.resultNode)
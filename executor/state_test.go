@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser"
 	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage/mock"
@@ -36,7 +37,7 @@ import (
 
 func TestValidState(t *testing.T) {
 	fetchTransform := parser.NewTransformFromOperation(functions.FetchOp{}, 1)
-	countTransform := parser.NewTransformFromOperation(functions.CountOp{}, 2)
+	countTransform := parser.NewTransformFromOperation(functions.AggregationOp{OperatorType: functions.CountType}, 2)
 	transforms := parser.Nodes{fetchTransform, countTransform}
 	edges := parser.Edges{
 		parser.Edge{
@@ -48,9 +49,9 @@ func TestValidState(t *testing.T) {
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
 	store := mock.NewMockStorage()
-	p, err := plan.NewPhysicalPlan(lp, store, time.Now())
+	p, err := plan.NewPhysicalPlan(lp, store, models.RequestParams{Now: time.Now()})
 	require.NoError(t, err)
-	state, err := GenerateExecutionState(p, store)
+	state, err := GenerateExecutionState(p, store, nil)
 	require.NoError(t, err)
 	require.Len(t, state.sources, 1)
 	err = state.Execute(context.Background())
@@ -58,14 +59,14 @@ func TestValidState(t *testing.T) {
 }
 
 func TestWithoutSources(t *testing.T) {
-	countTransform := parser.NewTransformFromOperation(functions.CountOp{}, 2)
+	countTransform := parser.NewTransformFromOperation(functions.AggregationOp{OperatorType: functions.CountType}, 2)
 	transforms := parser.Nodes{countTransform}
 	edges := parser.Edges{}
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, nil, time.Now())
+	p, err := plan.NewPhysicalPlan(lp, nil, models.RequestParams{Now: time.Now()})
 	require.NoError(t, err)
-	_, err = GenerateExecutionState(p, nil)
+	_, err = GenerateExecutionState(p, nil, nil)
 	assert.Error(t, err)
 }
 
@@ -75,16 +76,16 @@ func TestOnlySources(t *testing.T) {
 	edges := parser.Edges{}
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, nil, time.Now())
+	p, err := plan.NewPhysicalPlan(lp, nil, models.RequestParams{Now: time.Now()})
 	require.NoError(t, err)
-	state, err := GenerateExecutionState(p, nil)
+	state, err := GenerateExecutionState(p, nil, nil)
 	assert.NoError(t, err)
 	require.Len(t, state.sources, 1)
 }
 
 func TestMultipleSources(t *testing.T) {
 	fetchTransform1 := parser.NewTransformFromOperation(functions.FetchOp{}, 1)
-	countTransform := parser.NewTransformFromOperation(functions.CountOp{}, 2)
+	countTransform := parser.NewTransformFromOperation(functions.AggregationOp{OperatorType: functions.CountType}, 2)
 	fetchTransform2 := parser.NewTransformFromOperation(functions.FetchOp{}, 3)
 	transforms := parser.Nodes{fetchTransform1, fetchTransform2, countTransform}
 	edges := parser.Edges{
@@ -100,9 +101,9 @@ func TestMultipleSources(t *testing.T) {
 
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, nil, time.Now())
+	p, err := plan.NewPhysicalPlan(lp, nil, models.RequestParams{Now: time.Now()})
 	require.NoError(t, err)
-	state, err := GenerateExecutionState(p, nil)
+	state, err := GenerateExecutionState(p, nil, nil)
 	assert.NoError(t, err)
 	require.Len(t, state.sources, 2)
 	assert.Contains(t, state.String(), "sources")
@@ -0,0 +1,72 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/m3db/m3coordinator/plan"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+// ExecuteSubPlan executes a sub plan against the storage and returns the blocks of its result, storages which
+// execute sub plans themselves, such as remote coordinators, are handed the sub plan as is
+func ExecuteSubPlan(
+	ctx context.Context,
+	store storage.Storage,
+	subPlan plan.SubPlan,
+	options *storage.FetchOptions,
+) (storage.BlockResult, error) {
+	if executor, ok := store.(plan.SubPlanExecutor); ok {
+		return executor.ExecuteSubPlan(ctx, subPlan, options)
+	}
+
+	lp, err := subPlan.LogicalPlan()
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	pp, err := plan.NewPhysicalPlan(lp, store, subPlan.RequestParams())
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	var killChan chan struct{}
+	if options != nil {
+		killChan = options.KillChan
+	}
+
+	state, err := GenerateExecutionState(pp, store, killChan)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	if err := state.Execute(ctx); err != nil {
+		// Blocks produced before the failure are no longer needed
+		for _, block := range state.Result().Blocks() {
+			block.Close()
+		}
+
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: state.Result().Blocks(), Warnings: state.Warnings()}, nil
+}
@@ -26,7 +26,6 @@ import (
 	"time"
 
 	"github.com/m3db/m3coordinator/errors"
-	"github.com/m3db/m3coordinator/storage"
 )
 
 const (
@@ -101,7 +100,7 @@ func NewTracker() *Tracker {
 }
 
 // Track is used to add a new query to tracker
-func (t *Tracker) Track(query storage.Query, connClosed <-chan bool) (*QueryTask, error) {
+func (t *Tracker) Track(query fmt.Stringer, connClosed <-chan bool) (*QueryTask, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	qid := t.nextID
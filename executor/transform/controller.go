@@ -21,7 +21,6 @@
 package transform
 
 import (
-	"github.com/m3db/m3coordinator/errors"
 	"github.com/m3db/m3coordinator/parser"
 	"github.com/m3db/m3coordinator/storage"
 )
@@ -50,13 +49,6 @@ func (t *Controller) Process(block storage.Block) error {
 }
 
 // BlockBuilder returns a BlockBuilder instance with associated metadata
-// nolint: unparam
-func (t *Controller) BlockBuilder(blockMeta storage.BlockMetadata) (BlockBuilder, error) {
-	return nil, errors.ErrNotImplemented
-}
-
-// BlockBuilder builds a new block
-type BlockBuilder interface {
-	AppendValue(index int, value float64)
-	Build() storage.Block
+func (t *Controller) BlockBuilder(blockMeta storage.BlockMetadata, seriesMeta []storage.SeriesMeta) (storage.BlockBuilder, error) {
+	return storage.NewColumnBlockBuilder(blockMeta, seriesMeta), nil
 }
@@ -9,7 +9,18 @@ import (
 
 // Options to create transform nodes
 type Options struct {
-	Now time.Time
+	TimeSpec TimeSpec
+	// KillChan is closed once the query is killed, sources pass it on to their fetches
+	KillChan chan struct{}
+}
+
+// TimeSpec defines the time bounds for the query execution. End is inclusive
+type TimeSpec struct {
+	Start time.Time
+	End   time.Time
+	// Now captures the current time and fixes it throughout the request
+	Now  time.Time
+	Step time.Duration
 }
 
 // OpNode represents the execution node
@@ -0,0 +1,460 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// SumType adds all non nan elements in the vector
+	SumType = "sum"
+	// MinType takes the minimum of all non nan elements in the vector
+	MinType = "min"
+	// MaxType takes the maximum of all non nan elements in the vector
+	MaxType = "max"
+	// AvgType averages all non nan elements in the vector
+	AvgType = "avg"
+	// StdDevType takes the population standard deviation of all non nan elements in the vector
+	StdDevType = "stddev"
+	// StdVarType takes the population standard variance of all non nan elements in the vector
+	StdVarType = "stdvar"
+	// CountType counts the number of non nan elements in the vector
+	CountType = "count"
+	// TopKType takes the largest k elements in the vector
+	TopKType = "topk"
+	// BottomKType takes the smallest k elements in the vector
+	BottomKType = "bottomk"
+	// QuantileType takes the φ-quantile of all non nan elements in the vector
+	QuantileType = "quantile"
+	// CountValuesType counts the number of elements with the same value
+	CountValuesType = "count_values"
+)
+
+// aggregationFn reduces the non nan values of a group, which are never empty, to a single value
+type aggregationFn func(values []float64, param float64) float64
+
+var aggregationFns = map[string]aggregationFn{
+	SumType:      sumFn,
+	MinType:      minFn,
+	MaxType:      maxFn,
+	AvgType:      avgFn,
+	StdDevType:   stdDevFn,
+	StdVarType:   stdVarFn,
+	CountType:    countFn,
+	QuantileType: quantileFn,
+}
+
+// AggregationParams are the parameters common to all aggregations
+type AggregationParams struct {
+	// MatchingTags are the tags to group by, or to exclude from grouping when Without is set
+	MatchingTags []string
+	Without      bool
+	// Parameter is the numeric parameter for topk, bottomk and quantile
+	Parameter float64
+	// StringParameter is the label name for count_values
+	StringParameter string
+}
+
+// AggregationOp stores required properties for aggregations
+type AggregationOp struct {
+	OperatorType string
+	Params       AggregationParams
+}
+
+// OpType for the operator
+func (o AggregationOp) OpType() string {
+	return o.OperatorType
+}
+
+// String representation
+func (o AggregationOp) String() string {
+	return fmt.Sprintf("type: %s, params: %+v", o.OpType(), o.Params)
+}
+
+// Node creates an execution node
+func (o AggregationOp) Node(controller *transform.Controller) transform.OpNode {
+	return &AggregationNode{op: o, controller: controller}
+}
+
+// AggregationNode is an execution node
+type AggregationNode struct {
+	op         AggregationOp
+	controller *transform.Controller
+}
+
+// Process the block
+func (n *AggregationNode) Process(ID parser.NodeID, block storage.Block) error {
+	switch n.op.OperatorType {
+	case TopKType, BottomKType:
+		return n.processTake(block)
+	case CountValuesType:
+		return n.processCountValues(block)
+	}
+
+	fn, ok := aggregationFns[n.op.OperatorType]
+	if !ok {
+		return fmt.Errorf("unknown aggregation type: %s", n.op.OperatorType)
+	}
+
+	groups, seriesMeta := groupSeries(block, n.op.Params)
+	builder, err := n.controller.BlockBuilder(outputMeta(block), seriesMeta)
+	if err != nil {
+		return err
+	}
+
+	var buffer []float64
+	stepIter := block.StepIter()
+	for index := 0; stepIter.Next(); index++ {
+		values := stepIter.Current().Values()
+		for _, group := range groups {
+			buffer = buffer[:0]
+			for _, idx := range group {
+				if !math.IsNaN(values[idx]) {
+					buffer = append(buffer, values[idx])
+				}
+			}
+
+			value := math.NaN()
+			if len(buffer) > 0 {
+				value = fn(buffer, n.op.Params.Parameter)
+			}
+
+			if err := builder.AppendValue(index, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
+
+// processTake keeps the values of the k largest, or smallest, series in each group and sets the rest to NaN
+func (n *AggregationNode) processTake(block storage.Block) error {
+	groups, _ := groupSeries(block, n.op.Params)
+	seriesMeta := allSeriesMeta(block)
+	builder, err := n.controller.BlockBuilder(outputMeta(block), seriesMeta)
+	if err != nil {
+		return err
+	}
+
+	k := int(n.op.Params.Parameter)
+	taken := make([]float64, len(seriesMeta))
+	var indices []int
+	stepIter := block.StepIter()
+	for index := 0; stepIter.Next(); index++ {
+		values := stepIter.Current().Values()
+		for i := range taken {
+			taken[i] = math.NaN()
+		}
+
+		for _, group := range groups {
+			indices = indices[:0]
+			for _, idx := range group {
+				if !math.IsNaN(values[idx]) {
+					indices = append(indices, idx)
+				}
+			}
+
+			sort.SliceStable(indices, func(i, j int) bool {
+				if n.op.OperatorType == TopKType {
+					return values[indices[i]] > values[indices[j]]
+				}
+
+				return values[indices[i]] < values[indices[j]]
+			})
+
+			for i := 0; i < k && i < len(indices); i++ {
+				taken[indices[i]] = values[indices[i]]
+			}
+		}
+
+		if err := builder.AppendValues(index, taken); err != nil {
+			return err
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
+
+// processCountValues creates a series for every distinct value in each group, with the value as a tag,
+// counting the series with that value at each step
+func (n *AggregationNode) processCountValues(block storage.Block) error {
+	label := n.op.Params.StringParameter
+	if !model.LabelName(label).IsValid() {
+		return fmt.Errorf("invalid label name for count_values: %q", label)
+	}
+
+	groups, groupMeta := groupSeries(block, n.op.Params)
+
+	// The output series are only known once every step has been seen
+	var seriesMeta []storage.SeriesMeta
+	outputIndices := make([]map[float64]int, len(groups))
+	for i := range outputIndices {
+		outputIndices[i] = make(map[float64]int)
+	}
+
+	stepIter := block.StepIter()
+	for stepIter.Next() {
+		values := stepIter.Current().Values()
+		for i, group := range groups {
+			for _, idx := range group {
+				value := values[idx]
+				if math.IsNaN(value) {
+					continue
+				}
+
+				if _, ok := outputIndices[i][value]; ok {
+					continue
+				}
+
+				tags := make(models.Tags, len(groupMeta[i].Tags)+1)
+				for k, v := range groupMeta[i].Tags {
+					tags[k] = v
+				}
+
+				tags[label] = strconv.FormatFloat(value, 'f', -1, 64)
+				outputIndices[i][value] = len(seriesMeta)
+				seriesMeta = append(seriesMeta, storage.SeriesMeta{Tags: tags, Name: tags.ID()})
+			}
+		}
+	}
+
+	builder, err := n.controller.BlockBuilder(outputMeta(block), seriesMeta)
+	if err != nil {
+		return err
+	}
+
+	counts := make([]float64, len(seriesMeta))
+	stepIter = block.StepIter()
+	for index := 0; stepIter.Next(); index++ {
+		values := stepIter.Current().Values()
+		for i := range counts {
+			counts[i] = 0
+		}
+
+		for i, group := range groups {
+			for _, idx := range group {
+				if !math.IsNaN(values[idx]) {
+					counts[outputIndices[i][values[idx]]]++
+				}
+			}
+		}
+
+		for i := range counts {
+			if counts[i] == 0 {
+				counts[i] = math.NaN()
+			}
+		}
+
+		if err := builder.AppendValues(index, counts); err != nil {
+			return err
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
+
+// outputMeta is the metadata for an aggregated block, common tags are part of the series tags instead
+func outputMeta(block storage.Block) storage.BlockMetadata {
+	return storage.BlockMetadata{Bounds: block.Meta().Bounds}
+}
+
+// allSeriesMeta returns the metadata of every series in the block including the common tags
+func allSeriesMeta(block storage.Block) []storage.SeriesMeta {
+	common := block.Meta().Tags
+	blockMeta := block.SeriesMeta()
+	seriesMeta := make([]storage.SeriesMeta, len(blockMeta))
+	for i, meta := range blockMeta {
+		tags := make(models.Tags, len(common)+len(meta.Tags))
+		for k, v := range common {
+			tags[k] = v
+		}
+
+		for k, v := range meta.Tags {
+			tags[k] = v
+		}
+
+		seriesMeta[i] = storage.SeriesMeta{Tags: tags, Name: meta.Name}
+	}
+
+	return seriesMeta
+}
+
+// groupSeries splits the series of a block into groups based on the grouping tags,
+// returning the series indices of each group along with the group metadata, in order of first appearance
+func groupSeries(block storage.Block, params AggregationParams) ([][]int, []storage.SeriesMeta) {
+	var (
+		groups     [][]int
+		groupMeta  []storage.SeriesMeta
+		groupIndex = make(map[string]int)
+	)
+
+	for i, meta := range allSeriesMeta(block) {
+		tags := groupTags(meta.Tags, params)
+		id := tags.ID()
+		idx, ok := groupIndex[id]
+		if !ok {
+			idx = len(groups)
+			groupIndex[id] = idx
+			groups = append(groups, nil)
+			groupMeta = append(groupMeta, storage.SeriesMeta{Tags: tags, Name: id})
+		}
+
+		groups[idx] = append(groups[idx], i)
+	}
+
+	return groups, groupMeta
+}
+
+// groupTags returns the tags which identify the group of a series, the metric name is always dropped
+// unless explicitly grouped by
+func groupTags(tags models.Tags, params AggregationParams) models.Tags {
+	grouped := make(models.Tags)
+	if params.Without {
+		for k, v := range tags {
+			grouped[k] = v
+		}
+
+		delete(grouped, models.MetricName)
+		for _, name := range params.MatchingTags {
+			delete(grouped, name)
+		}
+
+		return grouped
+	}
+
+	for _, name := range params.MatchingTags {
+		if v, ok := tags[name]; ok {
+			grouped[name] = v
+		}
+	}
+
+	return grouped
+}
+
+func sumFn(values []float64, _ float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum
+}
+
+func minFn(values []float64, _ float64) float64 {
+	min := values[0]
+	for _, v := range values[1:] {
+		min = math.Min(min, v)
+	}
+
+	return min
+}
+
+func maxFn(values []float64, _ float64) float64 {
+	max := values[0]
+	for _, v := range values[1:] {
+		max = math.Max(max, v)
+	}
+
+	return max
+}
+
+func avgFn(values []float64, _ float64) float64 {
+	return sumFn(values, 0) / float64(len(values))
+}
+
+func countFn(values []float64, _ float64) float64 {
+	return float64(len(values))
+}
+
+func stdVarFn(values []float64, _ float64) float64 {
+	mean := avgFn(values, 0)
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return variance / float64(len(values))
+}
+
+func stdDevFn(values []float64, _ float64) float64 {
+	return math.Sqrt(stdVarFn(values, 0))
+}
+
+// quantileFn interpolates linearly between the closest ranks, matching Prometheus
+func quantileFn(values []float64, q float64) float64 {
+	if q < 0 {
+		return math.Inf(-1)
+	}
+
+	if q > 1 {
+		return math.Inf(1)
+	}
+
+	sort.Float64s(values)
+	n := float64(len(values))
+	rank := q * (n - 1)
+	lowerIndex := math.Max(0, math.Floor(rank))
+	upperIndex := math.Min(n-1, lowerIndex+1)
+	weight := rank - math.Floor(rank)
+	return values[int(lowerIndex)]*(1-weight) + values[int(upperIndex)]*weight
+}
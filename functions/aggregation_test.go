@@ -0,0 +1,237 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sinkNode records the blocks it processes
+type sinkNode struct {
+	blocks []storage.Block
+}
+
+func (s *sinkNode) Process(ID parser.NodeID, block storage.Block) error {
+	s.blocks = append(s.blocks, block)
+	return nil
+}
+
+// closingBlock records whether the block is closed
+type closingBlock struct {
+	storage.Block
+	closed bool
+}
+
+func (b *closingBlock) Close() error {
+	b.closed = true
+	return b.Block.Close()
+}
+
+func newSink() (*transform.Controller, *sinkNode) {
+	sink := &sinkNode{}
+	controller := &transform.Controller{ID: parser.NodeID("1")}
+	controller.AddTransform(sink)
+	return controller, sink
+}
+
+// stepValues returns the values at every step of the block
+func stepValues(block storage.Block) [][]float64 {
+	var values [][]float64
+	iter := block.StepIter()
+	for iter.Next() {
+		values = append(values, append([]float64(nil), iter.Current().Values()...))
+	}
+
+	return values
+}
+
+// testStart is shared by the test blocks so that blocks built separately have the same bounds
+var testStart = time.Now().Truncate(time.Minute)
+
+func newTestBlock(t *testing.T, tags []models.Tags, values [][]float64) storage.Block {
+	return newTestBlockAt(t, testStart, tags, values)
+}
+
+func newTestBlockAt(t *testing.T, start time.Time, tags []models.Tags, values [][]float64) storage.Block {
+	bounds := storage.Bounds{Start: start, End: start.Add(time.Duration(len(values[0])-1) * time.Minute), StepSize: time.Minute}
+	seriesMeta := make([]storage.SeriesMeta, len(tags))
+	for i, tag := range tags {
+		seriesMeta[i] = storage.SeriesMeta{Tags: tag, Name: tag.ID()}
+	}
+
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, values)
+	require.NoError(t, err)
+	return block
+}
+
+var (
+	nan     = math.NaN()
+	aggTags = []models.Tags{
+		{models.MetricName: "up", "job": "api", "instance": "a"},
+		{models.MetricName: "up", "job": "api", "instance": "b"},
+		{models.MetricName: "up", "job": "db", "instance": "c"},
+	}
+	aggValues = [][]float64{
+		{1, 2, nan},
+		{3, nan, nan},
+		{5, 6, nan},
+	}
+)
+
+func TestAggregations(t *testing.T) {
+	tests := []struct {
+		opType   string
+		param    float64
+		expected [][]float64
+	}{
+		{SumType, 0, [][]float64{{4, 5}, {2, 6}, {nan, nan}}},
+		{MinType, 0, [][]float64{{1, 5}, {2, 6}, {nan, nan}}},
+		{MaxType, 0, [][]float64{{3, 5}, {2, 6}, {nan, nan}}},
+		{AvgType, 0, [][]float64{{2, 5}, {2, 6}, {nan, nan}}},
+		{CountType, 0, [][]float64{{2, 1}, {1, 1}, {nan, nan}}},
+		{StdVarType, 0, [][]float64{{1, 0}, {0, 0}, {nan, nan}}},
+		{StdDevType, 0, [][]float64{{1, 0}, {0, 0}, {nan, nan}}},
+		{QuantileType, 0.5, [][]float64{{2, 5}, {2, 6}, {nan, nan}}},
+		{QuantileType, 2, [][]float64{{math.Inf(1), math.Inf(1)}, {math.Inf(1), math.Inf(1)}, {nan, nan}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			controller, sink := newSink()
+			op := AggregationOp{OperatorType: tt.opType, Params: AggregationParams{MatchingTags: []string{"job"}, Parameter: tt.param}}
+			require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+			require.Len(t, sink.blocks, 1)
+
+			block := sink.blocks[0]
+			assert.Equal(t, []storage.SeriesMeta{
+				{Tags: models.Tags{"job": "api"}, Name: models.Tags{"job": "api"}.ID()},
+				{Tags: models.Tags{"job": "db"}, Name: models.Tags{"job": "db"}.ID()},
+			}, block.SeriesMeta())
+			assertValuesEqual(t, tt.expected, stepValues(block))
+		})
+	}
+}
+
+func TestAggregationClosesInput(t *testing.T) {
+	for _, opType := range []string{SumType, TopKType, CountValuesType} {
+		t.Run(opType, func(t *testing.T) {
+			controller, sink := newSink()
+			op := AggregationOp{OperatorType: opType, Params: AggregationParams{Parameter: 1, StringParameter: "value"}}
+			block := &closingBlock{Block: newTestBlock(t, aggTags, aggValues)}
+			require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), block))
+			require.Len(t, sink.blocks, 1)
+			assert.True(t, block.closed)
+		})
+	}
+}
+
+func TestAggregationWithout(t *testing.T) {
+	controller, sink := newSink()
+	op := AggregationOp{OperatorType: SumType, Params: AggregationParams{MatchingTags: []string{"instance"}, Without: true}}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+	require.Len(t, sink.blocks, 1)
+
+	// The metric name is dropped along with the excluded tags
+	meta := sink.blocks[0].SeriesMeta()
+	require.Len(t, meta, 2)
+	assert.Equal(t, models.Tags{"job": "api"}, meta[0].Tags)
+	assert.Equal(t, models.Tags{"job": "db"}, meta[1].Tags)
+}
+
+func TestAggregationNoGrouping(t *testing.T) {
+	controller, sink := newSink()
+	op := AggregationOp{OperatorType: CountType}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+	require.Len(t, sink.blocks, 1)
+	assert.Equal(t, []storage.SeriesMeta{{Tags: models.Tags{}, Name: models.Tags{}.ID()}}, sink.blocks[0].SeriesMeta())
+	assertValuesEqual(t, [][]float64{{3}, {2}, {nan}}, stepValues(sink.blocks[0]))
+}
+
+func TestTopKBottomK(t *testing.T) {
+	controller, sink := newSink()
+	op := AggregationOp{OperatorType: TopKType, Params: AggregationParams{MatchingTags: []string{"job"}, Parameter: 1}}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+	require.Len(t, sink.blocks, 1)
+	assert.Len(t, sink.blocks[0].SeriesMeta(), 3)
+	assert.Equal(t, aggTags[0], sink.blocks[0].SeriesMeta()[0].Tags)
+	assertValuesEqual(t, [][]float64{{nan, 3, 5}, {2, nan, 6}, {nan, nan, nan}}, stepValues(sink.blocks[0]))
+
+	controller, sink = newSink()
+	op = AggregationOp{OperatorType: BottomKType, Params: AggregationParams{Parameter: 2}}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+	require.Len(t, sink.blocks, 1)
+	assertValuesEqual(t, [][]float64{{1, 3, nan}, {2, nan, 6}, {nan, nan, nan}}, stepValues(sink.blocks[0]))
+}
+
+func TestCountValues(t *testing.T) {
+	controller, sink := newSink()
+	op := AggregationOp{OperatorType: CountValuesType, Params: AggregationParams{StringParameter: "value"}}
+	values := [][]float64{
+		{1, 2},
+		{1, 1.5},
+		{2, nan},
+	}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, values)))
+	require.Len(t, sink.blocks, 1)
+
+	meta := sink.blocks[0].SeriesMeta()
+	require.Len(t, meta, 3)
+	assert.Equal(t, models.Tags{"value": "1"}, meta[0].Tags)
+	assert.Equal(t, models.Tags{"value": "2"}, meta[1].Tags)
+	assert.Equal(t, models.Tags{"value": "1.5"}, meta[2].Tags)
+	assertValuesEqual(t, [][]float64{{2, 1, nan}, {nan, 1, 1}}, stepValues(sink.blocks[0]))
+
+	controller, _ = newSink()
+	op = AggregationOp{OperatorType: CountValuesType, Params: AggregationParams{StringParameter: "invalid-label"}}
+	assert.Error(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, values)))
+}
+
+func TestUnknownAggregation(t *testing.T) {
+	controller, _ := newSink()
+	op := AggregationOp{OperatorType: "unknown"}
+	assert.Error(t, op.Node(controller).Process(parser.NodeID("0"), newTestBlock(t, aggTags, aggValues)))
+}
+
+// assertValuesEqual compares values treating NaNs as equal
+func assertValuesEqual(t *testing.T, expected, actual [][]float64) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.Len(t, actual[i], len(expected[i]), "step %d", i)
+		for j := range expected[i] {
+			if math.IsNaN(expected[i][j]) {
+				assert.True(t, math.IsNaN(actual[i][j]), "step %d, series %d: expected NaN, got %v", i, j, actual[i][j])
+				continue
+			}
+
+			assert.InDelta(t, expected[i][j], actual[i][j], 1e-9, "step %d, series %d", i, j)
+		}
+	}
+}
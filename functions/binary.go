@@ -0,0 +1,655 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+const (
+	// PlusType adds datapoints in both series
+	PlusType = "+"
+	// MinusType subtracts rhs from lhs datapoints
+	MinusType = "-"
+	// MultiplyType multiplies datapoints by series
+	MultiplyType = "*"
+	// DivType divides datapoints by series
+	DivType = "/"
+	// ModType takes the modulo of lhs by rhs datapoints
+	ModType = "%"
+	// PowType raises lhs to the power of rhs
+	PowType = "^"
+
+	// EqType checks that lhs is equal to rhs
+	EqType = "=="
+	// NotEqType checks that lhs is not equal to rhs
+	NotEqType = "!="
+	// GreaterType checks that lhs is greater than rhs
+	GreaterType = ">"
+	// LesserType checks that lhs is less than rhs
+	LesserType = "<"
+	// GreaterEqType checks that lhs is greater than or equal to rhs
+	GreaterEqType = ">="
+	// LesserEqType checks that lhs is less than or equal to rhs
+	LesserEqType = "<="
+
+	// AndType uses values from lhs for which there is a value in rhs
+	AndType = "and"
+	// OrType uses all values from lhs, and values from rhs with no match in lhs
+	OrType = "or"
+	// UnlessType uses values from lhs for which there is no value in rhs
+	UnlessType = "unless"
+)
+
+// VectorMatchCardinality describes the cardinality relationship
+// of two vectors in a binary operation
+type VectorMatchCardinality int
+
+const (
+	// CardOneToOne is used for one-one relationship
+	CardOneToOne VectorMatchCardinality = iota
+	// CardManyToOne is used for many-one relationship
+	CardManyToOne
+	// CardOneToMany is used for one-many relationship
+	CardOneToMany
+	// CardManyToMany is used for many-many relationship
+	CardManyToMany
+)
+
+// VectorMatching describes how elements from two vectors in a binary
+// operation are supposed to be matched
+type VectorMatching struct {
+	// Card is the cardinality of the two vectors
+	Card VectorMatchCardinality
+	// MatchingLabels contains the labels which define equality of a pair of
+	// elements from the vectors
+	MatchingLabels []string
+	// On includes the given label names from matching, rather than excluding them
+	On bool
+	// Include contains additional labels that should be included in
+	// the result from the side with the lower cardinality
+	Include []string
+}
+
+// BinaryParams are the parameters of a binary operation
+type BinaryParams struct {
+	LNode          parser.NodeID
+	RNode          parser.NodeID
+	LIsScalar      bool
+	RIsScalar      bool
+	ReturnBool     bool
+	VectorMatching *VectorMatching
+}
+
+// BinaryOp stores required properties for binary operations
+type BinaryOp struct {
+	OperatorType string
+	Params       BinaryParams
+}
+
+// OpType for the operator
+func (o BinaryOp) OpType() string {
+	return o.OperatorType
+}
+
+// String representation
+func (o BinaryOp) String() string {
+	return fmt.Sprintf("type: %s, lhs: %s, rhs: %s", o.OpType(), o.Params.LNode, o.Params.RNode)
+}
+
+// Node creates an execution node
+func (o BinaryOp) Node(controller *transform.Controller) transform.OpNode {
+	return &BinaryNode{op: o, controller: controller}
+}
+
+// BinaryNode is an execution node with two parents, it waits for a block from each side
+// before emitting the combined block. Blocks from each side are paired by their start time
+// so that blocks of the same range are combined whichever order they arrive in.
+type BinaryNode struct {
+	op         BinaryOp
+	controller *transform.Controller
+
+	mu        sync.Mutex
+	lhsBlocks []storage.Block
+	rhsBlocks []storage.Block
+}
+
+// Process the block
+func (n *BinaryNode) Process(ID parser.NodeID, block storage.Block) error {
+	lhs, rhs, ok, err := n.pair(ID, block)
+	if err != nil || !ok {
+		return err
+	}
+
+	result, err := n.process(lhs, rhs)
+	if err != nil {
+		return err
+	}
+
+	// Both inputs are no longer read once the output is built
+	if err := lhs.Close(); err != nil {
+		return err
+	}
+
+	if err := rhs.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(result)
+}
+
+// pair returns the block along with the queued block of the other side starting at the same time,
+// the block is queued until the other side has one
+func (n *BinaryNode) pair(ID parser.NodeID, block storage.Block) (storage.Block, storage.Block, bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var queued, other *[]storage.Block
+	isLHS := false
+	switch ID {
+	case n.op.Params.LNode:
+		queued, other, isLHS = &n.lhsBlocks, &n.rhsBlocks, true
+	case n.op.Params.RNode:
+		queued, other = &n.rhsBlocks, &n.lhsBlocks
+	default:
+		return nil, nil, false, fmt.Errorf("block from unknown parent %s for binary operation %s", ID, n.op)
+	}
+
+	start := block.Meta().Bounds.Start
+	for i, candidate := range *other {
+		if !candidate.Meta().Bounds.Start.Equal(start) {
+			continue
+		}
+
+		*other = append((*other)[:i], (*other)[i+1:]...)
+		if isLHS {
+			return block, candidate, true, nil
+		}
+
+		return candidate, block, true, nil
+	}
+
+	*queued = append(*queued, block)
+	return nil, nil, false, nil
+}
+
+func (n *BinaryNode) process(lhsBlock, rhsBlock storage.Block) (storage.Block, error) {
+	fn, ok := binaryFns[n.op.OperatorType]
+	if !ok && !isSetOperator(n.op.OperatorType) {
+		return nil, fmt.Errorf("unknown binary operation: %s", n.op.OperatorType)
+	}
+
+	lhs, err := newBinarySide(lhsBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	rhs, err := newBinarySide(rhsBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if lhs.steps != rhs.steps {
+		return nil, fmt.Errorf("mismatched steps for binary operation, %d - %d", lhs.steps, rhs.steps)
+	}
+
+	// Scalars are generated for the whole query so the vector side defines the bounds
+	bounds := lhsBlock.Meta().Bounds
+	if n.op.Params.LIsScalar {
+		bounds = rhsBlock.Meta().Bounds
+	}
+
+	out := newSeriesCollector(lhs.steps)
+	params := n.op.Params
+	switch {
+	case params.LIsScalar && params.RIsScalar:
+		err = n.scalarScalar(fn, lhs, rhs, out)
+	case params.LIsScalar:
+		err = n.vectorScalar(fn, rhs, lhs, true, out)
+	case params.RIsScalar:
+		err = n.vectorScalar(fn, lhs, rhs, false, out)
+	case isSetOperator(n.op.OperatorType):
+		err = n.setOperation(lhs, rhs, out)
+	default:
+		err = n.vectorVector(fn, lhs, rhs, out)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := n.controller.BlockBuilder(storage.BlockMetadata{Bounds: bounds}, out.meta)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, values := range out.values {
+		for idx, value := range values {
+			if err := builder.AppendValue(idx, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return builder.Build()
+}
+
+func (n *BinaryNode) scalarScalar(fn binaryFn, lhs, rhs *binarySide, out *seriesCollector) error {
+	if len(lhs.values) != 1 || len(rhs.values) != 1 {
+		return fmt.Errorf("scalar operands must have a single series, got %d and %d", len(lhs.values), len(rhs.values))
+	}
+
+	for step := 0; step < lhs.steps; step++ {
+		l, r := lhs.values[0][step], rhs.values[0][step]
+		value, keep := fn(l, r)
+		if n.op.Params.ReturnBool {
+			value = boolToFloat(keep)
+		}
+
+		if err := out.add(models.Tags{}, step, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vectorScalar applies the operation to every series of the vector, swapped is set when the scalar is the lhs
+func (n *BinaryNode) vectorScalar(fn binaryFn, vector, scalar *binarySide, swapped bool, out *seriesCollector) error {
+	if len(scalar.values) != 1 {
+		return fmt.Errorf("scalar operand must have a single series, got %d", len(scalar.values))
+	}
+
+	opType := n.op.OperatorType
+	for i, values := range vector.values {
+		tags := resultTags(vector.meta[i].Tags, nil, opType, n.op.Params.ReturnBool, nil)
+		for step, v := range values {
+			// NB: a NaN scalar is still applied, e.g. NaN ^ 0 is 1
+			s := scalar.values[0][step]
+			if math.IsNaN(v) {
+				continue
+			}
+
+			l, r := v, s
+			if swapped {
+				l, r = s, v
+			}
+
+			value, keep := fn(l, r)
+			if isComparison(opType) {
+				// Comparisons always keep the value of the vector, even when it is the rhs
+				value = v
+			}
+
+			if n.op.Params.ReturnBool {
+				value, keep = boolToFloat(keep), true
+			}
+
+			if !keep {
+				continue
+			}
+
+			if err := out.add(tags, step, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// vectorVector matches series from both sides by their matching tags at every step, one side
+// must have a single series per signature, and for one to one matching both sides must
+func (n *BinaryNode) vectorVector(fn binaryFn, lhs, rhs *binarySide, out *seriesCollector) error {
+	matching := vectorMatching(n.op.Params.VectorMatching)
+	lhsSigs, rhsSigs := signatures(lhs.meta, matching), signatures(rhs.meta, matching)
+
+	// The "one" side is looked up by signature for every series of the "many" side
+	many, one := lhs, rhs
+	manySigs, oneSigs := lhsSigs, rhsSigs
+	swapped := matching.Card == CardOneToMany
+	if swapped {
+		many, one = rhs, lhs
+		manySigs, oneSigs = rhsSigs, lhsSigs
+	}
+
+	for step := 0; step < lhs.steps; step++ {
+		oneBySig := make(map[string]int)
+		for j, values := range one.values {
+			if math.IsNaN(values[step]) {
+				continue
+			}
+
+			if _, ok := oneBySig[oneSigs[j]]; ok {
+				return fmt.Errorf("many-to-many matching not allowed: matching labels must be unique on one side")
+			}
+
+			oneBySig[oneSigs[j]] = j
+		}
+
+		matched := make(map[string]struct{})
+		for i, values := range many.values {
+			v := values[step]
+			if math.IsNaN(v) {
+				continue
+			}
+
+			j, ok := oneBySig[manySigs[i]]
+			if !ok {
+				continue
+			}
+
+			if matching.Card == CardOneToOne {
+				if _, ok := matched[manySigs[i]]; ok {
+					return fmt.Errorf("multiple matches for labels: many-to-one matching must be explicit (group_left/group_right)")
+				}
+
+				matched[manySigs[i]] = struct{}{}
+			}
+
+			l, r := v, one.values[j][step]
+			if swapped {
+				l, r = r, l
+			}
+
+			value, keep := fn(l, r)
+			if n.op.Params.ReturnBool {
+				value, keep = boolToFloat(keep), true
+			}
+
+			if !keep {
+				continue
+			}
+
+			tags := resultTags(many.meta[i].Tags, one.meta[j].Tags, n.op.OperatorType, n.op.Params.ReturnBool, matching)
+			if err := out.add(tags, step, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setOperation handles and, or and unless, which match many to many and keep the tags of the series
+func (n *BinaryNode) setOperation(lhs, rhs *binarySide, out *seriesCollector) error {
+	matching := vectorMatching(n.op.Params.VectorMatching)
+	lhsSigs, rhsSigs := signatures(lhs.meta, matching), signatures(rhs.meta, matching)
+
+	for step := 0; step < lhs.steps; step++ {
+		present := func(side *binarySide, sigs []string) map[string]struct{} {
+			result := make(map[string]struct{})
+			for i, values := range side.values {
+				if !math.IsNaN(values[step]) {
+					result[sigs[i]] = struct{}{}
+				}
+			}
+
+			return result
+		}
+
+		var (
+			rhsPresent map[string]struct{}
+			keepLHS    func(sig string) bool
+		)
+
+		switch n.op.OperatorType {
+		case AndType:
+			rhsPresent = present(rhs, rhsSigs)
+			keepLHS = func(sig string) bool { _, ok := rhsPresent[sig]; return ok }
+		case UnlessType:
+			rhsPresent = present(rhs, rhsSigs)
+			keepLHS = func(sig string) bool { _, ok := rhsPresent[sig]; return !ok }
+		default:
+			keepLHS = func(string) bool { return true }
+		}
+
+		for i, values := range lhs.values {
+			if math.IsNaN(values[step]) || !keepLHS(lhsSigs[i]) {
+				continue
+			}
+
+			if err := out.add(lhs.meta[i].Tags, step, values[step]); err != nil {
+				return err
+			}
+		}
+
+		if n.op.OperatorType != OrType {
+			continue
+		}
+
+		lhsPresent := present(lhs, lhsSigs)
+		for i, values := range rhs.values {
+			if math.IsNaN(values[step]) {
+				continue
+			}
+
+			if _, ok := lhsPresent[rhsSigs[i]]; ok {
+				continue
+			}
+
+			if err := out.add(rhs.meta[i].Tags, step, values[step]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// binarySide holds the series of one side of a binary operation
+type binarySide struct {
+	meta   []storage.SeriesMeta
+	values [][]float64
+	steps  int
+}
+
+// newBinarySide reads the series of the block, every series must have a value per step of the bounds
+func newBinarySide(block storage.Block) (*binarySide, error) {
+	side := &binarySide{
+		meta:  allSeriesMeta(block),
+		steps: block.Meta().Bounds.Steps(),
+	}
+
+	iter := block.SeriesIter()
+	for iter.Next() {
+		series := iter.Current()
+		if series.Len() != side.steps {
+			return nil, fmt.Errorf("series %d has %d values but bounds have %d steps", len(side.values), series.Len(), side.steps)
+		}
+
+		values := make([]float64, series.Len())
+		for i := range values {
+			values[i] = series.ValueAt(i)
+		}
+
+		side.values = append(side.values, values)
+	}
+
+	return side, nil
+}
+
+// seriesCollector collects output values by series tags, creating series as they are first seen
+type seriesCollector struct {
+	steps  int
+	index  map[string]int
+	meta   []storage.SeriesMeta
+	values [][]float64
+}
+
+func newSeriesCollector(steps int) *seriesCollector {
+	return &seriesCollector{steps: steps, index: make(map[string]int)}
+}
+
+func (c *seriesCollector) add(tags models.Tags, step int, value float64) error {
+	id := tags.ID()
+	idx, ok := c.index[id]
+	if !ok {
+		values := make([]float64, c.steps)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+
+		idx = len(c.values)
+		c.index[id] = idx
+		c.meta = append(c.meta, storage.SeriesMeta{Tags: tags, Name: id})
+		c.values = append(c.values, values)
+	}
+
+	if !math.IsNaN(c.values[idx][step]) {
+		return fmt.Errorf("duplicate series in binary operation result: %s", id)
+	}
+
+	c.values[idx][step] = value
+	return nil
+}
+
+func vectorMatching(matching *VectorMatching) *VectorMatching {
+	if matching == nil {
+		return &VectorMatching{Card: CardOneToOne}
+	}
+
+	return matching
+}
+
+// signatures are the IDs of the tags used to match series, either only the matching labels when
+// matching on them, or every tag except the matching labels and the metric name
+func signatures(seriesMeta []storage.SeriesMeta, matching *VectorMatching) []string {
+	sigs := make([]string, len(seriesMeta))
+	for i, meta := range seriesMeta {
+		tags := make(models.Tags)
+		if matching.On {
+			for _, name := range matching.MatchingLabels {
+				if v, ok := meta.Tags[name]; ok {
+					tags[name] = v
+				}
+			}
+		} else {
+			for k, v := range meta.Tags {
+				tags[k] = v
+			}
+
+			delete(tags, models.MetricName)
+			for _, name := range matching.MatchingLabels {
+				delete(tags, name)
+			}
+		}
+
+		sigs[i] = tags.ID()
+	}
+
+	return sigs
+}
+
+// resultTags are the tags of an output series, matching and the tags of the one side are only set for vector matching
+func resultTags(tags, oneTags models.Tags, opType string, returnBool bool, matching *VectorMatching) models.Tags {
+	result := make(models.Tags, len(tags))
+	for k, v := range tags {
+		result[k] = v
+	}
+
+	if returnBool || !isComparison(opType) {
+		delete(result, models.MetricName)
+	}
+
+	if matching == nil {
+		return result
+	}
+
+	if matching.Card == CardOneToOne {
+		if matching.On {
+			kept := make(models.Tags, len(matching.MatchingLabels))
+			for _, name := range matching.MatchingLabels {
+				if v, ok := result[name]; ok {
+					kept[name] = v
+				}
+			}
+
+			result = kept
+		} else {
+			for _, name := range matching.MatchingLabels {
+				delete(result, name)
+			}
+		}
+	}
+
+	for _, name := range matching.Include {
+		if v, ok := oneTags[name]; ok && v != "" {
+			result[name] = v
+		} else {
+			delete(result, name)
+		}
+	}
+
+	return result
+}
+
+// binaryFn returns the result for a pair of values and whether it is kept, which is only false for failed comparisons
+type binaryFn func(lhs, rhs float64) (float64, bool)
+
+var binaryFns = map[string]binaryFn{
+	PlusType:     func(l, r float64) (float64, bool) { return l + r, true },
+	MinusType:    func(l, r float64) (float64, bool) { return l - r, true },
+	MultiplyType: func(l, r float64) (float64, bool) { return l * r, true },
+	DivType:      func(l, r float64) (float64, bool) { return l / r, true },
+	ModType:      func(l, r float64) (float64, bool) { return math.Mod(l, r), true },
+	PowType:      func(l, r float64) (float64, bool) { return math.Pow(l, r), true },
+
+	EqType:        func(l, r float64) (float64, bool) { return l, l == r },
+	NotEqType:     func(l, r float64) (float64, bool) { return l, l != r },
+	GreaterType:   func(l, r float64) (float64, bool) { return l, l > r },
+	LesserType:    func(l, r float64) (float64, bool) { return l, l < r },
+	GreaterEqType: func(l, r float64) (float64, bool) { return l, l >= r },
+	LesserEqType:  func(l, r float64) (float64, bool) { return l, l <= r },
+}
+
+func isComparison(opType string) bool {
+	switch opType {
+	case EqType, NotEqType, GreaterType, LesserType, GreaterEqType, LesserEqType:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSetOperator(opType string) bool {
+	switch opType {
+	case AndType, OrType, UnlessType:
+		return true
+	default:
+		return false
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lhsID = parser.NodeID("0")
+	rhsID = parser.NodeID("1")
+)
+
+func processBinary(t *testing.T, opType string, params BinaryParams, lhs, rhs storage.Block) (storage.Block, error) {
+	params.LNode, params.RNode = lhsID, rhsID
+	controller, sink := newSink()
+	node := BinaryOp{OperatorType: opType, Params: params}.Node(controller)
+
+	// Nothing is emitted until both sides have a block
+	require.NoError(t, node.Process(lhsID, lhs))
+	require.Empty(t, sink.blocks)
+	if err := node.Process(rhsID, rhs); err != nil {
+		return nil, err
+	}
+
+	require.Len(t, sink.blocks, 1)
+	return sink.blocks[0], nil
+}
+
+func seriesTags(block storage.Block) []models.Tags {
+	var tags []models.Tags
+	for _, meta := range block.SeriesMeta() {
+		tags = append(tags, meta.Tags)
+	}
+
+	return tags
+}
+
+func TestBinaryScalarScalar(t *testing.T) {
+	lhs := newTestBlock(t, []models.Tags{{}}, [][]float64{{1, 1}})
+	rhs := newTestBlock(t, []models.Tags{{}}, [][]float64{{2, 2}})
+	block, err := processBinary(t, PlusType, BinaryParams{LIsScalar: true, RIsScalar: true}, lhs, rhs)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{3}, {3}}, stepValues(block))
+}
+
+func TestBinaryVectorScalar(t *testing.T) {
+	vectorTags := []models.Tags{
+		{models.MetricName: "foo", "job": "api"},
+		{models.MetricName: "foo", "job": "db"},
+	}
+	vector := [][]float64{{1, nan}, {3, 4}}
+
+	block, err := processBinary(t, MultiplyType, BinaryParams{RIsScalar: true},
+		newTestBlock(t, vectorTags, vector), newTestBlock(t, []models.Tags{{}}, [][]float64{{2, 2}}))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{"job": "api"}, {"job": "db"}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{2, 6}, {nan, 8}}, stepValues(block))
+
+	// Comparisons filter the vector and keep its value and name, even when the scalar is the lhs
+	block, err = processBinary(t, LesserType, BinaryParams{LIsScalar: true},
+		newTestBlock(t, []models.Tags{{}}, [][]float64{{2, 2}}), newTestBlock(t, vectorTags, vector))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{vectorTags[1]}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{3}, {4}}, stepValues(block))
+
+	block, err = processBinary(t, LesserType, BinaryParams{LIsScalar: true, ReturnBool: true},
+		newTestBlock(t, []models.Tags{{}}, [][]float64{{2, 2}}), newTestBlock(t, vectorTags, vector))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{"job": "api"}, {"job": "db"}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{0, 1}, {nan, 1}}, stepValues(block))
+}
+
+func TestBinaryVectorNaNScalar(t *testing.T) {
+	vectorTags := []models.Tags{{models.MetricName: "foo", "job": "api"}}
+	scalar := newTestBlock(t, []models.Tags{{}}, [][]float64{{nan, nan}})
+
+	// The operator is applied to NaN scalars, NaN ^ 0 is 1
+	block, err := processBinary(t, PowType, BinaryParams{LIsScalar: true},
+		scalar, newTestBlock(t, vectorTags, [][]float64{{0, 1}}))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{"job": "api"}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{1}, {nan}}, stepValues(block))
+
+	block, err = processBinary(t, GreaterType, BinaryParams{RIsScalar: true, ReturnBool: true},
+		newTestBlock(t, vectorTags, [][]float64{{1, 2}}), scalar)
+	require.NoError(t, err)
+	assertValuesEqual(t, [][]float64{{0}, {0}}, stepValues(block))
+}
+
+func TestBinaryVectorVector(t *testing.T) {
+	lhs := newTestBlock(t, []models.Tags{
+		{models.MetricName: "errors", "job": "api"},
+		{models.MetricName: "errors", "job": "db"},
+	}, [][]float64{{1, 2}, {3, nan}})
+	rhs := newTestBlock(t, []models.Tags{
+		{models.MetricName: "requests", "job": "api"},
+		{models.MetricName: "requests", "job": "db"},
+		{models.MetricName: "requests", "job": "web"},
+	}, [][]float64{{10, 10}, {30, 30}, {5, 5}})
+
+	block, err := processBinary(t, DivType, BinaryParams{VectorMatching: &VectorMatching{}}, lhs, rhs)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{"job": "api"}, {"job": "db"}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{0.1, 0.1}, {0.2, nan}}, stepValues(block))
+}
+
+func TestBinaryGroupLeft(t *testing.T) {
+	lhs := newTestBlock(t, []models.Tags{
+		{models.MetricName: "memory", "job": "api", "instance": "a"},
+		{models.MetricName: "memory", "job": "api", "instance": "b"},
+	}, [][]float64{{1, 1}, {2, 2}})
+	rhs := newTestBlock(t, []models.Tags{
+		{models.MetricName: "info", "job": "api", "version": "v1"},
+	}, [][]float64{{1, 1}})
+
+	matching := &VectorMatching{Card: CardManyToOne, MatchingLabels: []string{"job"}, On: true, Include: []string{"version"}}
+	block, err := processBinary(t, MultiplyType, BinaryParams{VectorMatching: matching}, lhs, rhs)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{
+		{"job": "api", "instance": "a", "version": "v1"},
+		{"job": "api", "instance": "b", "version": "v1"},
+	}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{1, 2}, {1, 2}}, stepValues(block))
+
+	// Both sides have a single series per signature for one to one matching
+	matching = &VectorMatching{Card: CardOneToOne, MatchingLabels: []string{"job"}, On: true}
+	_, err = processBinary(t, MultiplyType, BinaryParams{VectorMatching: matching}, lhs, rhs)
+	assert.Error(t, err)
+
+	// The one side of a group_right is the lhs
+	matching = &VectorMatching{Card: CardOneToMany, MatchingLabels: []string{"job"}, On: true}
+	_, err = processBinary(t, MultiplyType, BinaryParams{VectorMatching: matching}, lhs, rhs)
+	assert.Error(t, err)
+}
+
+func TestBinarySetOperations(t *testing.T) {
+	lhsTags := []models.Tags{
+		{models.MetricName: "a", "job": "api"},
+		{models.MetricName: "a", "job": "db"},
+	}
+	rhsTags := []models.Tags{
+		{models.MetricName: "b", "job": "api"},
+		{models.MetricName: "b", "job": "web"},
+	}
+	lhsValues := [][]float64{{1, nan}, {2, 2}}
+	rhsValues := [][]float64{{5, 5}, {6, nan}}
+
+	tests := []struct {
+		opType   string
+		tags     []models.Tags
+		expected [][]float64
+	}{
+		{AndType, lhsTags[:1], [][]float64{{1}, {nan}}},
+		{UnlessType, lhsTags[1:], [][]float64{{2}, {2}}},
+		{OrType, []models.Tags{lhsTags[0], lhsTags[1], rhsTags[1], rhsTags[0]}, [][]float64{{1, 2, 6, nan}, {nan, 2, nan, 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			block, err := processBinary(t, tt.opType, BinaryParams{VectorMatching: &VectorMatching{Card: CardManyToMany}},
+				newTestBlock(t, lhsTags, lhsValues), newTestBlock(t, rhsTags, rhsValues))
+			require.NoError(t, err)
+			assert.Equal(t, tt.tags, seriesTags(block))
+			assertValuesEqual(t, tt.expected, stepValues(block))
+		})
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	controller, _ := newSink()
+	node := BinaryOp{OperatorType: PlusType, Params: BinaryParams{LNode: lhsID, RNode: rhsID}}.Node(controller)
+	assert.Error(t, node.Process(parser.NodeID("2"), newTestBlock(t, []models.Tags{{}}, [][]float64{{1}})))
+
+	_, err := processBinary(t, PlusType, BinaryParams{},
+		newTestBlock(t, []models.Tags{{}}, [][]float64{{1}}), newTestBlock(t, []models.Tags{{}}, [][]float64{{1, 2}}))
+	assert.Error(t, err)
+
+	_, err = processBinary(t, "unknown", BinaryParams{},
+		newTestBlock(t, []models.Tags{{}}, [][]float64{{1}}), newTestBlock(t, []models.Tags{{}}, [][]float64{{1}}))
+	assert.Error(t, err)
+}
+
+// boundsBlock reports bounds which do not match its series
+type boundsBlock struct {
+	storage.Block
+	bounds storage.Bounds
+}
+
+func (b *boundsBlock) Meta() storage.BlockMetadata {
+	return storage.BlockMetadata{Bounds: b.bounds}
+}
+
+func TestBinarySeriesLengthMismatch(t *testing.T) {
+	block := newTestBlock(t, []models.Tags{{"a": "1"}}, [][]float64{{1, 2}})
+	bounds := block.Meta().Bounds
+	bounds.End = bounds.End.Add(bounds.StepSize)
+	lhs := &boundsBlock{Block: block, bounds: bounds}
+	rhs := newTestBlock(t, []models.Tags{{"a": "1"}}, [][]float64{{1, 2, 3}})
+
+	_, err := processBinary(t, PlusType, BinaryParams{VectorMatching: &VectorMatching{Card: CardOneToOne}}, lhs, rhs)
+	assert.Error(t, err)
+}
+
+func TestBinaryPairsBlocksByStart(t *testing.T) {
+	controller, sink := newSink()
+	params := BinaryParams{LNode: lhsID, RNode: rhsID, VectorMatching: &VectorMatching{Card: CardOneToOne}}
+	node := BinaryOp{OperatorType: MinusType, Params: params}.Node(controller)
+
+	tags := []models.Tags{{"a": "1"}}
+	later := testStart.Add(time.Hour)
+	require.NoError(t, node.Process(lhsID, newTestBlock(t, tags, [][]float64{{10}})))
+	require.NoError(t, node.Process(lhsID, newTestBlockAt(t, later, tags, [][]float64{{20}})))
+	require.Empty(t, sink.blocks)
+
+	// The rhs block of the later range is paired with the second lhs block
+	require.NoError(t, node.Process(rhsID, newTestBlockAt(t, later, tags, [][]float64{{2}})))
+	require.Len(t, sink.blocks, 1)
+	assert.True(t, sink.blocks[0].Meta().Bounds.Start.Equal(later))
+	assertValuesEqual(t, [][]float64{{18}}, stepValues(sink.blocks[0]))
+
+	require.NoError(t, node.Process(rhsID, newTestBlock(t, tags, [][]float64{{1}})))
+	require.Len(t, sink.blocks, 2)
+	assert.True(t, sink.blocks[1].Meta().Bounds.Start.Equal(testStart))
+	assertValuesEqual(t, [][]float64{{9}}, stepValues(sink.blocks[1]))
+}
+
+func TestBinaryClosesInputs(t *testing.T) {
+	lhs := &closingBlock{Block: newTestBlock(t, []models.Tags{{"a": "1"}}, [][]float64{{1}})}
+	rhs := &closingBlock{Block: newTestBlock(t, []models.Tags{{"a": "1"}}, [][]float64{{2}})}
+	_, err := processBinary(t, PlusType, BinaryParams{VectorMatching: &VectorMatching{Card: CardOneToOne}}, lhs, rhs)
+	require.NoError(t, err)
+	assert.True(t, lhs.closed)
+	assert.True(t, rhs.closed)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser"
 	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
 )
 
 // FetchType gets the series from storage
@@ -47,7 +48,9 @@ type FetchNode struct {
 	op         FetchOp
 	controller *transform.Controller
 	storage    storage.Storage
-	now        time.Time
+	timespec   transform.TimeSpec
+	killChan   chan struct{}
+	warnings   []storage.Warning
 }
 
 // OpType for the operator
@@ -62,23 +65,35 @@ func (o FetchOp) String() string {
 
 // Node creates an execution node
 func (o FetchOp) Node(controller *transform.Controller, storage storage.Storage, options transform.Options) parser.Source {
-	return &FetchNode{op: o, controller: controller, storage: storage, now: options.Now}
+	return &FetchNode{op: o, controller: controller, storage: storage, timespec: options.TimeSpec, killChan: options.KillChan}
 }
 
 // Execute runs the fetch node operation
 func (n *FetchNode) Execute(ctx context.Context) error {
-	startTime := n.now.Add(-1 * n.op.Offset)
-	endTime := startTime.Add(n.op.Range)
+	if n.op.Range > 0 {
+		return n.executeRange(ctx)
+	}
+
+	timespec := n.timespec
+	startTime := timespec.Start.Add(-1 * n.op.Offset)
+	endTime := timespec.End.Add(-1 * n.op.Offset)
 	blockResult, err := n.storage.FetchBlocks(ctx, &storage.FetchQuery{
 		Start:       startTime,
 		End:         endTime,
 		TagMatchers: n.op.Matchers,
-	}, &storage.FetchOptions{})
+		Interval:    timespec.Step,
+	}, n.fetchOptions())
 	if err != nil {
 		return err
 	}
 
+	n.warnings = append(n.warnings, blockResult.Warnings...)
 	for _, block := range blockResult.Blocks {
+		block, err := shiftBlock(block, n.op.Offset)
+		if err != nil {
+			return err
+		}
+
 		if err := n.controller.Process(block); err != nil {
 			// Fail on first error
 			return err
@@ -87,3 +102,111 @@ func (n *FetchNode) Execute(ctx context.Context) error {
 
 	return nil
 }
+
+// shiftBlock moves a block fetched with an offset forward by the offset, so that its steps line up with the
+// steps of the query
+func shiftBlock(block storage.Block, offset time.Duration) (storage.Block, error) {
+	if offset == 0 {
+		return block, nil
+	}
+
+	meta := block.Meta()
+	meta.Bounds.Start = meta.Bounds.Start.Add(offset)
+	meta.Bounds.End = meta.Bounds.End.Add(offset)
+	values := make([][]float64, 0, len(block.SeriesMeta()))
+	iter := block.SeriesIter()
+	for iter.Next() {
+		series := iter.Current()
+		vals := make([]float64, series.Len())
+		for i := range vals {
+			vals[i] = series.ValueAt(i)
+		}
+
+		values = append(values, vals)
+	}
+
+	shifted, err := storage.NewSeriesBlock(meta, block.SeriesMeta(), values)
+	if err != nil {
+		return nil, err
+	}
+
+	return shifted, block.Close()
+}
+
+// executeRange fetches the raw datapoints of a range selector, going back a range from the first step of the
+// query, so that functions over the range are evaluated on them rather than on consolidated steps
+func (n *FetchNode) executeRange(ctx context.Context) error {
+	timespec := n.timespec
+	query := &storage.FetchQuery{
+		Start:       timespec.Start.Add(-1 * (n.op.Offset + n.op.Range)),
+		End:         timespec.End.Add(-1 * n.op.Offset),
+		TagMatchers: n.op.Matchers,
+		Interval:    timespec.Step,
+	}
+
+	var (
+		seriesMeta []storage.SeriesMeta
+		datapoints []ts.Datapoints
+	)
+	appendSeries := func(tags models.Tags, iter storage.DatapointIter) error {
+		var dps ts.Datapoints
+		for iter.Next() {
+			// Datapoints are only valid during the call, they are copied and moved to the time they are used at
+			dp := iter.Current()
+			dps = append(dps, &ts.Datapoint{Timestamp: dp.Timestamp.Add(n.op.Offset), Value: dp.Value})
+		}
+
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
+		seriesMeta = append(seriesMeta, storage.SeriesMeta{Tags: tags, Name: tags.ID()})
+		datapoints = append(datapoints, dps)
+		return nil
+	}
+
+	if err := n.fetchRaw(ctx, query, appendSeries); err != nil {
+		return err
+	}
+
+	bounds := storage.Bounds{Start: timespec.Start, End: timespec.End, StepSize: timespec.Step}
+	block, err := storage.NewRawBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, datapoints, n.op.Range)
+	if err != nil {
+		return err
+	}
+
+	return n.controller.Process(block)
+}
+
+// Warnings returns the stores left out of the results fetched by the node
+func (n *FetchNode) Warnings() []storage.Warning {
+	return n.warnings
+}
+
+// fetchOptions kills the fetches of the node along with the query
+func (n *FetchNode) fetchOptions() *storage.FetchOptions {
+	return &storage.FetchOptions{KillChan: n.killChan}
+}
+
+// fetchRaw streams the raw series of the storage, storages which cannot stream raw series are fetched in full
+func (n *FetchNode) fetchRaw(ctx context.Context, query *storage.FetchQuery, fn storage.RawSeriesFn) error {
+	if raw, ok := n.storage.(storage.RawQuerier); ok {
+		result, err := raw.FetchRaw(ctx, query, n.fetchOptions(), fn)
+		n.warnings = append(n.warnings, result.Warnings...)
+		return err
+	}
+
+	result, err := n.storage.Fetch(ctx, query, n.fetchOptions())
+	if err != nil {
+		return err
+	}
+
+	n.warnings = append(n.warnings, result.Warnings...)
+	for _, series := range result.SeriesList {
+		if err := fn(series.Tags, storage.SeriesToDatapointIter(series)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
+	"github.com/m3db/m3coordinator/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offsetStore returns a series valued by the minute of the hour at every step, or every minute for raw fetches
+type offsetStore struct {
+	storage.Storage
+	queries  []*storage.FetchQuery
+	options  []*storage.FetchOptions
+	warnings []storage.Warning
+}
+
+func (s *offsetStore) FetchBlocks(
+	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
+	s.queries = append(s.queries, query)
+	s.options = append(s.options, options)
+	bounds := storage.Bounds{Start: query.Start, End: query.End, StepSize: query.Interval}
+	values := make([]float64, bounds.Steps())
+	for i := range values {
+		values[i] = float64(bounds.TimeForIndex(i).Minute())
+	}
+
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+		[]storage.SeriesMeta{{Name: "up", Tags: models.Tags{"job": "api"}}}, [][]float64{values})
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: []storage.Block{block}, Warnings: s.warnings}, nil
+}
+
+func (s *offsetStore) FetchRaw(
+	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	s.queries = append(s.queries, query)
+	s.options = append(s.options, options)
+	var dps ts.Datapoints
+	for t := query.Start; !t.After(query.End); t = t.Add(time.Minute) {
+		dps = append(dps, &ts.Datapoint{Timestamp: t, Value: float64(t.Minute())})
+	}
+
+	return storage.RawResult{Warnings: s.warnings}, fn(models.Tags{"job": "api"}, &datapointsIter{datapoints: dps, idx: -1})
+}
+
+// datapointsIter is a storage.DatapointIter over a slice
+type datapointsIter struct {
+	datapoints ts.Datapoints
+	idx        int
+}
+
+func (it *datapointsIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.datapoints)
+}
+
+func (it *datapointsIter) Current() ts.Datapoint {
+	return *it.datapoints[it.idx]
+}
+
+func (it *datapointsIter) Err() error {
+	return nil
+}
+
+func TestFetchOffsetAlignsSteps(t *testing.T) {
+	start := time.Date(2018, time.May, 1, 12, 0, 0, 0, time.UTC)
+	timespec := transform.TimeSpec{Start: start, End: start.Add(2 * time.Minute), Now: start, Step: time.Minute}
+	store := &offsetStore{Storage: mock.NewMockStorage()}
+	controller, sink := newSink()
+
+	op := FetchOp{Name: "up", Offset: time.Hour}
+	require.NoError(t, op.Node(controller, store, transform.Options{TimeSpec: timespec}).Execute(context.TODO()))
+	require.Len(t, store.queries, 1)
+	assert.True(t, start.Add(-time.Hour).Equal(store.queries[0].Start))
+	assert.True(t, start.Add(2*time.Minute-time.Hour).Equal(store.queries[0].End))
+
+	// The values fetched an hour back are returned at the steps of the query
+	require.Len(t, sink.blocks, 1)
+	block := sink.blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(storage.Bounds{Start: start, End: start.Add(2 * time.Minute), StepSize: time.Minute}))
+	steps := block.StepIter()
+	for i := 0; steps.Next(); i++ {
+		assert.True(t, start.Add(time.Duration(i)*time.Minute).Equal(steps.Current().Time()))
+		assert.Equal(t, []float64{float64(i)}, steps.Current().Values())
+	}
+}
+
+func TestFetchRangeOffsetAlignsDatapoints(t *testing.T) {
+	start := time.Date(2018, time.May, 1, 12, 0, 0, 0, time.UTC)
+	timespec := transform.TimeSpec{Start: start, End: start.Add(2 * time.Minute), Now: start, Step: time.Minute}
+	store := &offsetStore{Storage: mock.NewMockStorage()}
+	controller, sink := newSink()
+
+	op := FetchOp{Name: "up", Range: 5 * time.Minute, Offset: time.Hour}
+	require.NoError(t, op.Node(controller, store, transform.Options{TimeSpec: timespec}).Execute(context.TODO()))
+	require.Len(t, store.queries, 1)
+	assert.True(t, start.Add(-time.Hour-5*time.Minute).Equal(store.queries[0].Start))
+
+	require.Len(t, sink.blocks, 1)
+	raw, ok := sink.blocks[0].(storage.RawBlock)
+	require.True(t, ok)
+	assert.True(t, raw.Meta().Bounds.Equals(storage.Bounds{Start: start, End: start.Add(2 * time.Minute), StepSize: time.Minute}))
+	require.Len(t, raw.Datapoints(), 1)
+	dps := raw.Datapoints()[0]
+	require.Len(t, dps, 8)
+	assert.True(t, start.Add(-5*time.Minute).Equal(dps[0].Timestamp))
+	assert.Equal(t, 55.0, dps[0].Value)
+	assert.True(t, start.Add(2*time.Minute).Equal(dps[7].Timestamp))
+	assert.Equal(t, 2.0, dps[7].Value)
+}
+
+func TestFetchPassesKillChan(t *testing.T) {
+	start := time.Date(2018, time.May, 1, 12, 0, 0, 0, time.UTC)
+	timespec := transform.TimeSpec{Start: start, End: start.Add(2 * time.Minute), Now: start, Step: time.Minute}
+	store := &offsetStore{Storage: mock.NewMockStorage()}
+	killChan := make(chan struct{})
+	options := transform.Options{TimeSpec: timespec, KillChan: killChan}
+
+	controller, _ := newSink()
+	require.NoError(t, FetchOp{Name: "up"}.Node(controller, store, options).Execute(context.TODO()))
+	controller, _ = newSink()
+	require.NoError(t, FetchOp{Name: "up", Range: time.Minute}.Node(controller, store, options).Execute(context.TODO()))
+
+	require.Len(t, store.options, 2)
+	for _, opts := range store.options {
+		assert.Equal(t, killChan, opts.KillChan)
+	}
+}
+
+func TestFetchCollectsWarnings(t *testing.T) {
+	start := time.Date(2018, time.May, 1, 12, 0, 0, 0, time.UTC)
+	timespec := transform.TimeSpec{Start: start, End: start.Add(2 * time.Minute), Now: start, Step: time.Minute}
+	warnings := []storage.Warning{{Store: "eu", Message: "unavailable"}}
+	store := &offsetStore{Storage: mock.NewMockStorage(), warnings: warnings}
+	options := transform.Options{TimeSpec: timespec}
+
+	for _, op := range []FetchOp{{Name: "up"}, {Name: "up", Range: time.Minute}} {
+		controller, _ := newSink()
+		node := op.Node(controller, store, options)
+		require.NoError(t, node.Execute(context.TODO()))
+		assert.Equal(t, warnings, node.(*FetchNode).Warnings())
+	}
+}
@@ -22,58 +22,32 @@ package functions
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/m3db/m3coordinator/executor/transform"
 	"github.com/m3db/m3coordinator/parser"
-	"github.com/m3db/m3coordinator/storage"
 )
 
-// CountType counts number of elements in the vector
-const CountType = "count"
+// FunctionConstructor creates the params of a function call from its literal arguments,
+// rangeDuration is the range of the range vector argument, or zero if there is none
+type FunctionConstructor func(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error)
 
-// CountOp stores required properties for count
-type CountOp struct {
-}
-
-// OpType for the operator
-func (o CountOp) OpType() string {
-	return CountType
-}
-
-// String representation
-func (o CountOp) String() string {
-	return fmt.Sprintf("type: %s", o.OpType())
-}
+var functionRegistry = make(map[string]FunctionConstructor)
 
-// Node creates an execution node
-func (o CountOp) Node(controller *transform.Controller) transform.OpNode {
-	return &CountNode{op: o, controller: controller}
-}
-
-// CountNode is an execution node
-type CountNode struct {
-	op         CountOp
-	controller *transform.Controller
-}
-
-// Process the block
-func (c *CountNode) Process(ID parser.NodeID, block storage.Block) error {
-	builder, err := c.controller.BlockBuilder(block.Meta())
-	if err != nil {
-		return err
+// RegisterFunction registers the constructor of an executable function, it panics if the name is registered twice
+func RegisterFunction(name string, constructor FunctionConstructor) {
+	if _, ok := functionRegistry[name]; ok {
+		panic(fmt.Sprintf("function %s is already registered", name))
 	}
 
-	stepIter := block.StepIter()
-	for index := 0; stepIter.Next(); index++ {
-		step := stepIter.Current()
-		values := step.Values()
-		sum := 0.0
-		for _, value := range values {
-			sum += value
-		}
+	functionRegistry[name] = constructor
+}
 
-		builder.AppendValue(index, sum)
+// NewFunction creates the params of a function call, functions which are not registered are not supported
+func NewFunction(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	constructor, ok := functionRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function: %s", name)
 	}
 
-	return c.controller.Process(builder.Build())
+	return constructor(name, arguments, rangeDuration)
 }
@@ -0,0 +1,229 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+const (
+	// HistogramQuantileType takes the φ-quantile of the buckets of each histogram
+	HistogramQuantileType = "histogram_quantile"
+
+	// bucketTag is the tag holding the upper bound of a histogram bucket
+	bucketTag = "le"
+)
+
+func init() {
+	RegisterFunction(HistogramQuantileType, NewHistogramQuantileOp)
+}
+
+// HistogramQuantileOp stores required properties for histogram_quantile
+type HistogramQuantileOp struct {
+	Quantile float64
+}
+
+// NewHistogramQuantileOp creates a new histogram_quantile function
+func NewHistogramQuantileOp(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	if rangeDuration > 0 {
+		return nil, fmt.Errorf("%s requires an instant vector argument", name)
+	}
+
+	if len(arguments) != 1 {
+		return nil, fmt.Errorf("%s requires a single number argument, got %v", name, arguments)
+	}
+
+	quantile, ok := arguments[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a number argument, got %v", name, arguments[0])
+	}
+
+	return HistogramQuantileOp{Quantile: quantile}, nil
+}
+
+// OpType for the operator
+func (o HistogramQuantileOp) OpType() string {
+	return HistogramQuantileType
+}
+
+// String representation
+func (o HistogramQuantileOp) String() string {
+	return fmt.Sprintf("type: %s, quantile: %v", o.OpType(), o.Quantile)
+}
+
+// Node creates an execution node
+func (o HistogramQuantileOp) Node(controller *transform.Controller) transform.OpNode {
+	return &HistogramQuantileNode{op: o, controller: controller}
+}
+
+// HistogramQuantileNode is an execution node
+type HistogramQuantileNode struct {
+	op         HistogramQuantileOp
+	controller *transform.Controller
+}
+
+// bucket is a single bucket of a histogram at a step
+type bucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogram is the series of the buckets of a single histogram along with their upper bounds
+type histogram struct {
+	series      []int
+	upperBounds []float64
+}
+
+// Process the block, series are grouped into histograms by their tags without the bucket tag and the metric
+// name, series without a valid bucket tag are dropped
+func (n *HistogramQuantileNode) Process(ID parser.NodeID, block storage.Block) error {
+	var (
+		histograms     []*histogram
+		meta           []storage.SeriesMeta
+		histogramIndex = make(map[string]int)
+	)
+
+	for i, seriesMeta := range allSeriesMeta(block) {
+		upperBound, err := strconv.ParseFloat(seriesMeta.Tags[bucketTag], 64)
+		if err != nil {
+			continue
+		}
+
+		tags := make(models.Tags, len(seriesMeta.Tags))
+		for k, v := range seriesMeta.Tags {
+			if k != bucketTag && k != models.MetricName {
+				tags[k] = v
+			}
+		}
+
+		id := tags.ID()
+		idx, ok := histogramIndex[id]
+		if !ok {
+			idx = len(histograms)
+			histogramIndex[id] = idx
+			histograms = append(histograms, &histogram{})
+			meta = append(meta, storage.SeriesMeta{Tags: tags, Name: id})
+		}
+
+		histograms[idx].series = append(histograms[idx].series, i)
+		histograms[idx].upperBounds = append(histograms[idx].upperBounds, upperBound)
+	}
+
+	builder, err := n.controller.BlockBuilder(outputMeta(block), meta)
+	if err != nil {
+		return err
+	}
+
+	var buckets []bucket
+	stepIter := block.StepIter()
+	for index := 0; stepIter.Next(); index++ {
+		values := stepIter.Current().Values()
+		for _, h := range histograms {
+			buckets = buckets[:0]
+			for i, idx := range h.series {
+				if !math.IsNaN(values[idx]) {
+					buckets = append(buckets, bucket{upperBound: h.upperBounds[i], count: values[idx]})
+				}
+			}
+
+			if err := builder.AppendValue(index, bucketQuantile(n.op.Quantile, buckets)); err != nil {
+				return err
+			}
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
+
+// bucketQuantile follows the Prometheus implementation, the quantile is linearly interpolated within the
+// bucket it falls into. The buckets must include the +Inf bucket, counts which decrease with the upper
+// bound are raised to keep the buckets monotonic.
+func bucketQuantile(q float64, buckets []bucket) float64 {
+	if q < 0 {
+		return math.Inf(-1)
+	}
+
+	if q > 1 {
+		return math.Inf(1)
+	}
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].upperBound < buckets[j].upperBound
+	})
+
+	if !math.IsInf(buckets[len(buckets)-1].upperBound, 1) {
+		return math.NaN()
+	}
+
+	max := math.Inf(-1)
+	for i := range buckets {
+		if buckets[i].count > max {
+			max = buckets[i].count
+		} else {
+			buckets[i].count = max
+		}
+	}
+
+	rank := q * buckets[len(buckets)-1].count
+	b := sort.Search(len(buckets)-1, func(i int) bool {
+		return buckets[i].count >= rank
+	})
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+
+	bucketStart, bucketEnd, count := 0.0, buckets[b].upperBound, buckets[b].count
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
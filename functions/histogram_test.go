@@ -0,0 +1,83 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	tags := []models.Tags{
+		{models.MetricName: "latency_bucket", "job": "api", bucketTag: "0.1"},
+		{models.MetricName: "latency_bucket", "job": "api", bucketTag: "1"},
+		{models.MetricName: "latency_bucket", "job": "api", bucketTag: "+Inf"},
+		{models.MetricName: "latency_bucket", "job": "db", bucketTag: "0.5"},
+		{models.MetricName: "latency_bucket", "job": "db", bucketTag: "1"},
+		{models.MetricName: "latency_bucket", "job": "db", bucketTag: "foo"},
+	}
+	values := [][]float64{
+		{1, 4},
+		{3, 6},
+		{4, 8},
+		{1, 1},
+		{2, 2},
+		{5, 5},
+	}
+
+	op, err := NewFunction(HistogramQuantileType, []interface{}{0.5}, 0)
+	require.NoError(t, err)
+
+	controller, sink := newSink()
+	node := op.(HistogramQuantileOp).Node(controller)
+	require.NoError(t, node.Process(parser.NodeID("0"), newTestBlock(t, tags, values)))
+	require.Len(t, sink.blocks, 1)
+	assert.Equal(t, []models.Tags{{"job": "api"}, {"job": "db"}}, seriesTags(sink.blocks[0]))
+	// The db histogram has no +Inf bucket so its quantile is undefined
+	assertValuesEqual(t, [][]float64{{0.55, nan}, {0.1, nan}}, stepValues(sink.blocks[0]))
+}
+
+func TestBucketQuantile(t *testing.T) {
+	buckets := func() []bucket {
+		return []bucket{{upperBound: math.Inf(1), count: 10}, {upperBound: 1, count: 5}, {upperBound: 2, count: 4}}
+	}
+
+	assert.Equal(t, math.Inf(-1), bucketQuantile(-1, buckets()))
+	assert.Equal(t, math.Inf(1), bucketQuantile(2, buckets()))
+	// Counts are made monotonic so the bucket below 2 holds 5 rather than 4
+	assert.InDelta(t, 0.5, bucketQuantile(0.25, buckets()), 1e-9)
+	// Ranks falling in the +Inf bucket return the highest finite upper bound
+	assert.Equal(t, 2.0, bucketQuantile(0.9, buckets()))
+	assert.True(t, math.IsNaN(bucketQuantile(0.5, buckets()[:1])))
+}
+
+func TestNewHistogramQuantileOp(t *testing.T) {
+	_, err := NewFunction(HistogramQuantileType, nil, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(HistogramQuantileType, []interface{}{"0.5"}, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/prometheus/common/model"
+)
+
+// LabelReplaceType sets a tag from the match of a regular expression against another tag
+const LabelReplaceType = "label_replace"
+
+func init() {
+	RegisterFunction(LabelReplaceType, NewLabelReplaceOp)
+}
+
+// LabelReplaceOp stores required properties for label_replace
+type LabelReplaceOp struct {
+	Destination string
+	Replacement string
+	Source      string
+	// Regex is anchored at both ends of the source tag value
+	Regex *regexp.Regexp
+}
+
+// NewLabelReplaceOp creates a new label_replace function
+func NewLabelReplaceOp(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	if rangeDuration > 0 {
+		return nil, fmt.Errorf("%s requires an instant vector argument", name)
+	}
+
+	if len(arguments) != 4 {
+		return nil, fmt.Errorf("%s requires four string arguments, got %v", name, arguments)
+	}
+
+	strs := make([]string, len(arguments))
+	for i, argument := range arguments {
+		str, ok := argument.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s requires string arguments, got %v", name, argument)
+		}
+
+		strs[i] = str
+	}
+
+	if !model.LabelName(strs[0]).IsValid() {
+		return nil, fmt.Errorf("invalid destination label name in %s: %s", name, strs[0])
+	}
+
+	regex, err := regexp.Compile("^(?:" + strs[3] + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression in %s: %s", name, strs[3])
+	}
+
+	return LabelReplaceOp{Destination: strs[0], Replacement: strs[1], Source: strs[2], Regex: regex}, nil
+}
+
+// OpType for the operator
+func (o LabelReplaceOp) OpType() string {
+	return LabelReplaceType
+}
+
+// String representation
+func (o LabelReplaceOp) String() string {
+	return fmt.Sprintf("type: %s, destination: %s, replacement: %s, source: %s, regex: %s",
+		o.OpType(), o.Destination, o.Replacement, o.Source, o.Regex)
+}
+
+// Node creates an execution node
+func (o LabelReplaceOp) Node(controller *transform.Controller) transform.OpNode {
+	return &LabelReplaceNode{op: o, controller: controller}
+}
+
+// LabelReplaceNode is an execution node
+type LabelReplaceNode struct {
+	op         LabelReplaceOp
+	controller *transform.Controller
+}
+
+// Process the block, series whose source tag does not match keep their tags and an empty replacement
+// removes the destination tag. The values are left as they are.
+func (n *LabelReplaceNode) Process(ID parser.NodeID, block storage.Block) error {
+	seriesMeta := allSeriesMeta(block)
+	for i, meta := range seriesMeta {
+		value := meta.Tags[n.op.Source]
+		indexes := n.op.Regex.FindStringSubmatchIndex(value)
+		if indexes == nil {
+			continue
+		}
+
+		replaced := n.op.Regex.ExpandString(nil, n.op.Replacement, value, indexes)
+		if len(replaced) == 0 {
+			delete(meta.Tags, n.op.Destination)
+		} else {
+			meta.Tags[n.op.Destination] = string(replaced)
+		}
+
+		seriesMeta[i] = storage.SeriesMeta{Tags: meta.Tags, Name: meta.Tags.ID()}
+	}
+
+	builder, err := n.controller.BlockBuilder(outputMeta(block), seriesMeta)
+	if err != nil {
+		return err
+	}
+
+	stepIter := block.StepIter()
+	for index := 0; stepIter.Next(); index++ {
+		if err := builder.AppendValues(index, stepIter.Current().Values()); err != nil {
+			return err
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
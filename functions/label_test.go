@@ -0,0 +1,85 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelReplace(t *testing.T) {
+	tags := []models.Tags{
+		{models.MetricName: "up", "instance": "host-a:9090", "job": "api"},
+		{models.MetricName: "up", "instance": "host-b", "job": "api"},
+	}
+	values := [][]float64{{1, 2}, {3, nan}}
+
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected []models.Tags
+	}{
+		{
+			name: "replace",
+			args: []interface{}{"host", "$1", "instance", "(.*):.*"},
+			expected: []models.Tags{
+				{models.MetricName: "up", "instance": "host-a:9090", "job": "api", "host": "host-a"},
+				{models.MetricName: "up", "instance": "host-b", "job": "api"},
+			},
+		},
+		{
+			name: "empty replacement",
+			args: []interface{}{"job", "", "instance", "host-b"},
+			expected: []models.Tags{
+				{models.MetricName: "up", "instance": "host-a:9090", "job": "api"},
+				{models.MetricName: "up", "instance": "host-b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := NewFunction(LabelReplaceType, tt.args, 0)
+			require.NoError(t, err)
+
+			controller, sink := newSink()
+			node := op.(LabelReplaceOp).Node(controller)
+			require.NoError(t, node.Process(parser.NodeID("0"), newTestBlock(t, tags, values)))
+			require.Len(t, sink.blocks, 1)
+			assert.Equal(t, tt.expected, seriesTags(sink.blocks[0]))
+			assertValuesEqual(t, [][]float64{{1, 3}, {2, nan}}, stepValues(sink.blocks[0]))
+		})
+	}
+}
+
+func TestNewLabelReplaceOp(t *testing.T) {
+	_, err := NewFunction(LabelReplaceType, []interface{}{"dst", "$1", "src"}, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(LabelReplaceType, []interface{}{"not-valid", "$1", "src", "(.*)"}, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(LabelReplaceType, []interface{}{"dst", "$1", "src", "("}, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+const (
+	// AbsType takes the absolute value of every value
+	AbsType = "abs"
+	// CeilType rounds every value up to the nearest integer
+	CeilType = "ceil"
+	// FloorType rounds every value down to the nearest integer
+	FloorType = "floor"
+	// ExpFuncType takes the exponential of every value
+	ExpFuncType = "exp"
+	// LnType takes the natural logarithm of every value
+	LnType = "ln"
+	// Log2Type takes the binary logarithm of every value
+	Log2Type = "log2"
+	// Log10Type takes the decimal logarithm of every value
+	Log10Type = "log10"
+	// SqrtType takes the square root of every value
+	SqrtType = "sqrt"
+	// RoundType rounds every value to the nearest multiple of its optional argument, which defaults to 1
+	RoundType = "round"
+	// ClampMaxType caps every value at its argument
+	ClampMaxType = "clamp_max"
+	// ClampMinType raises every value to at least its argument
+	ClampMinType = "clamp_min"
+)
+
+// mathFn computes a single value from a value and the literal arguments of the function
+type mathFn func(v float64, args []float64) float64
+
+// mathFunction is a function applied to every value along with the number of literal arguments it takes
+type mathFunction struct {
+	fn               mathFn
+	minArgs, maxArgs int
+}
+
+var mathFns = map[string]mathFunction{
+	AbsType:      {fn: unaryMathFn(math.Abs)},
+	CeilType:     {fn: unaryMathFn(math.Ceil)},
+	FloorType:    {fn: unaryMathFn(math.Floor)},
+	ExpFuncType:  {fn: unaryMathFn(math.Exp)},
+	LnType:       {fn: unaryMathFn(math.Log)},
+	Log2Type:     {fn: unaryMathFn(math.Log2)},
+	Log10Type:    {fn: unaryMathFn(math.Log10)},
+	SqrtType:     {fn: unaryMathFn(math.Sqrt)},
+	RoundType:    {fn: roundFn, maxArgs: 1},
+	ClampMaxType: {fn: func(v float64, args []float64) float64 { return math.Min(v, args[0]) }, minArgs: 1, maxArgs: 1},
+	ClampMinType: {fn: func(v float64, args []float64) float64 { return math.Max(v, args[0]) }, minArgs: 1, maxArgs: 1},
+}
+
+func init() {
+	for name := range mathFns {
+		RegisterFunction(name, NewMathOp)
+	}
+}
+
+func unaryMathFn(fn func(float64) float64) mathFn {
+	return func(v float64, _ []float64) float64 {
+		return fn(v)
+	}
+}
+
+// roundFn follows Prometheus, ties are rounded up and the value is rounded to a multiple of the argument
+func roundFn(v float64, args []float64) float64 {
+	toNearest := 1.0
+	if len(args) > 0 {
+		toNearest = args[0]
+	}
+
+	// Dividing by the inverse keeps precision for fractions such as 0.1
+	toNearestInverse := 1.0 / toNearest
+	return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+}
+
+// MathOp stores required properties for functions applied to every value of an instant vector
+type MathOp struct {
+	OperatorType string
+	// Arguments are the numeric literal arguments, such as the bound of clamp_max
+	Arguments []float64
+}
+
+// NewMathOp creates a new function applied to every value of an instant vector
+func NewMathOp(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	mathFunc, ok := mathFns[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown math function: %s", name)
+	}
+
+	if rangeDuration > 0 {
+		return nil, fmt.Errorf("%s requires an instant vector argument", name)
+	}
+
+	if len(arguments) < mathFunc.minArgs || len(arguments) > mathFunc.maxArgs {
+		return nil, fmt.Errorf("%s takes between %d and %d number arguments, got %v", name, mathFunc.minArgs, mathFunc.maxArgs, arguments)
+	}
+
+	op := MathOp{OperatorType: name}
+	for _, argument := range arguments {
+		arg, ok := argument.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s requires number arguments, got %v", name, argument)
+		}
+
+		op.Arguments = append(op.Arguments, arg)
+	}
+
+	return op, nil
+}
+
+// OpType for the operator
+func (o MathOp) OpType() string {
+	return o.OperatorType
+}
+
+// String representation
+func (o MathOp) String() string {
+	return fmt.Sprintf("type: %s, arguments: %v", o.OpType(), o.Arguments)
+}
+
+// Node creates an execution node
+func (o MathOp) Node(controller *transform.Controller) transform.OpNode {
+	return &MathNode{op: o, controller: controller}
+}
+
+// MathNode is an execution node
+type MathNode struct {
+	op         MathOp
+	controller *transform.Controller
+}
+
+// Process the block, the metric name is dropped as the values no longer represent the metric
+func (n *MathNode) Process(ID parser.NodeID, block storage.Block) error {
+	mathFunc, ok := mathFns[n.op.OperatorType]
+	if !ok {
+		return fmt.Errorf("unknown math function: %s", n.op.OperatorType)
+	}
+
+	builder, err := n.controller.BlockBuilder(outputMeta(block), dropMetricName(allSeriesMeta(block)))
+	if err != nil {
+		return err
+	}
+
+	seriesIter := block.SeriesIter()
+	for seriesIter.Next() {
+		series := seriesIter.Current()
+		for i := 0; i < series.Len(); i++ {
+			if err := builder.AppendValue(i, mathFunc.fn(series.ValueAt(i), n.op.Arguments)); err != nil {
+				return err
+			}
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
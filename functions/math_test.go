@@ -0,0 +1,80 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMathFunctions(t *testing.T) {
+	values := [][]float64{{-1.5, 2.4, 100, nan}}
+	tests := []struct {
+		opType    string
+		arguments []interface{}
+		expected  [][]float64
+	}{
+		{AbsType, nil, [][]float64{{1.5}, {2.4}, {100}, {nan}}},
+		{CeilType, nil, [][]float64{{-1}, {3}, {100}, {nan}}},
+		{FloorType, nil, [][]float64{{-2}, {2}, {100}, {nan}}},
+		{ExpFuncType, nil, [][]float64{{math.Exp(-1.5)}, {math.Exp(2.4)}, {math.Exp(100)}, {nan}}},
+		{LnType, nil, [][]float64{{nan}, {math.Log(2.4)}, {math.Log(100)}, {nan}}},
+		{Log2Type, nil, [][]float64{{nan}, {math.Log2(2.4)}, {math.Log2(100)}, {nan}}},
+		{Log10Type, nil, [][]float64{{nan}, {math.Log10(2.4)}, {2}, {nan}}},
+		{SqrtType, nil, [][]float64{{nan}, {math.Sqrt(2.4)}, {10}, {nan}}},
+		{RoundType, nil, [][]float64{{-1}, {2}, {100}, {nan}}},
+		{RoundType, []interface{}{0.5}, [][]float64{{-1.5}, {2.5}, {100}, {nan}}},
+		{ClampMaxType, []interface{}{2.0}, [][]float64{{-1.5}, {2}, {2}, {nan}}},
+		{ClampMinType, []interface{}{2.0}, [][]float64{{2}, {2.4}, {100}, {nan}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			op, err := NewFunction(tt.opType, tt.arguments, 0)
+			require.NoError(t, err)
+
+			controller, sink := newSink()
+			block := newTestBlock(t, []models.Tags{{models.MetricName: "up", "job": "api"}}, values)
+			require.NoError(t, op.(MathOp).Node(controller).Process(parser.NodeID("0"), block))
+			require.Len(t, sink.blocks, 1)
+			assert.Equal(t, []models.Tags{{"job": "api"}}, seriesTags(sink.blocks[0]))
+			assertValuesEqual(t, tt.expected, stepValues(sink.blocks[0]))
+		})
+	}
+}
+
+func TestNewMathOp(t *testing.T) {
+	_, err := NewFunction(ClampMaxType, nil, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(ClampMaxType, []interface{}{"1"}, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(AbsType, []interface{}{1.0}, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(AbsType, nil, time.Minute)
+	assert.Error(t, err)
+}
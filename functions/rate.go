@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// RateType calculates the per second rate of increase of a counter, extrapolated to the range
+	RateType = "rate"
+	// IRateType calculates the per second rate of increase of a counter from the last two samples
+	IRateType = "irate"
+	// IncreaseType calculates the increase of a counter, extrapolated to the range
+	IncreaseType = "increase"
+	// DeltaType calculates the difference between the first and last values of a gauge, extrapolated to the range
+	DeltaType = "delta"
+	// DerivType calculates the per second derivative of a gauge using linear regression
+	DerivType = "deriv"
+)
+
+func rateFn(samples []sample, rangeStart, rangeEnd time.Time, _ float64) float64 {
+	return extrapolatedRate(samples, rangeStart, rangeEnd, true, true)
+}
+
+func increaseFn(samples []sample, rangeStart, rangeEnd time.Time, _ float64) float64 {
+	return extrapolatedRate(samples, rangeStart, rangeEnd, true, false)
+}
+
+func deltaFn(samples []sample, rangeStart, rangeEnd time.Time, _ float64) float64 {
+	return extrapolatedRate(samples, rangeStart, rangeEnd, false, false)
+}
+
+// extrapolatedRate follows the Prometheus implementation, the difference between the first and last
+// samples is extrapolated to the edges of the range unless the samples are too far from them.
+// Counters are corrected for resets and are never extrapolated below zero.
+func extrapolatedRate(samples []sample, rangeStart, rangeEnd time.Time, isCounter, isRate bool) float64 {
+	if len(samples) < 2 {
+		return math.NaN()
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	result := last.v - first.v
+	if isCounter {
+		prev := first.v
+		for _, s := range samples[1:] {
+			if s.v < prev {
+				result += prev
+			}
+
+			prev = s.v
+		}
+	}
+
+	durationToStart := first.t.Sub(rangeStart).Seconds()
+	durationToEnd := rangeEnd.Sub(last.t).Seconds()
+	sampledInterval := last.t.Sub(first.t).Seconds()
+	averageDurationBetweenSamples := sampledInterval / float64(len(samples)-1)
+
+	if isCounter && result > 0 && first.v >= 0 {
+		// A counter can not have been below zero, so do not extrapolate further back than that
+		durationToZero := sampledInterval * (first.v / result)
+		if durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+	if durationToStart < extrapolationThreshold {
+		extrapolateToInterval += durationToStart
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+
+	if durationToEnd < extrapolationThreshold {
+		extrapolateToInterval += durationToEnd
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+
+	result = result * (extrapolateToInterval / sampledInterval)
+	if isRate {
+		result = result / rangeEnd.Sub(rangeStart).Seconds()
+	}
+
+	return result
+}
+
+func irateFn(samples []sample, _, _ time.Time, _ float64) float64 {
+	if len(samples) < 2 {
+		return math.NaN()
+	}
+
+	prev, last := samples[len(samples)-2], samples[len(samples)-1]
+	result := last.v - prev.v
+	if last.v < prev.v {
+		// Counter reset
+		result = last.v
+	}
+
+	sampledInterval := last.t.Sub(prev.t).Seconds()
+	if sampledInterval == 0 {
+		return math.NaN()
+	}
+
+	return result / sampledInterval
+}
+
+// derivFn is the slope of the least squares fit of the samples
+func derivFn(samples []sample, _, _ time.Time, _ float64) float64 {
+	if len(samples) < 2 {
+		return math.NaN()
+	}
+
+	// Times are relative to the first sample to keep precision
+	var sumX, sumY, sumXY, sumX2 float64
+	for _, s := range samples {
+		x := s.t.Sub(samples[0].t).Seconds()
+		sumX += x
+		sumY += s.v
+		sumXY += x * s.v
+		sumX2 += x * x
+	}
+
+	n := float64(len(samples))
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+	return covXY / varX
+}
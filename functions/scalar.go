@@ -0,0 +1,161 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+const (
+	// ScalarType is a scalar literal, it is distinct from the name of the scalar function
+	ScalarType = "scalar_literal"
+	// StringType is a string literal
+	StringType = "string"
+	// TimeType is the time of every step in seconds
+	TimeType = "time"
+)
+
+func init() {
+	RegisterFunction(TimeType, NewTimeOp)
+}
+
+// ScalarOp is a scalar literal
+type ScalarOp struct {
+	Val float64
+}
+
+// OpType for the operator
+func (o ScalarOp) OpType() string {
+	return ScalarType
+}
+
+// String representation
+func (o ScalarOp) String() string {
+	return fmt.Sprintf("type: %s, value: %v", o.OpType(), o.Val)
+}
+
+// Node creates an execution node
+func (o ScalarOp) Node(controller *transform.Controller, _ storage.Storage, options transform.Options) parser.Source {
+	return &ScalarNode{op: o, controller: controller, timespec: options.TimeSpec}
+}
+
+// ScalarNode is the execution node for a scalar literal
+type ScalarNode struct {
+	op         ScalarOp
+	controller *transform.Controller
+	timespec   transform.TimeSpec
+}
+
+// Execute emits a block with a single series holding the scalar at every step of the query
+func (n *ScalarNode) Execute(ctx context.Context) error {
+	return executeStepValues(n.controller, n.timespec, func(time.Time) float64 {
+		return n.op.Val
+	})
+}
+
+// executeStepValues emits a block with a single series without tags, valued by fn at every step of the query
+func executeStepValues(controller *transform.Controller, timespec transform.TimeSpec, fn func(t time.Time) float64) error {
+	bounds := storage.Bounds{
+		Start:    timespec.Start,
+		End:      timespec.End,
+		StepSize: timespec.Step,
+	}
+
+	builder, err := controller.BlockBuilder(storage.BlockMetadata{Bounds: bounds}, []storage.SeriesMeta{{Tags: models.Tags{}}})
+	if err != nil {
+		return err
+	}
+
+	for idx := 0; idx < bounds.Steps(); idx++ {
+		if err := builder.AppendValue(idx, fn(bounds.TimeForIndex(idx))); err != nil {
+			return err
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	return controller.Process(output)
+}
+
+// TimeOp is the time function
+type TimeOp struct{}
+
+// NewTimeOp creates a new time function
+func NewTimeOp(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	if len(arguments) != 0 || rangeDuration > 0 {
+		return nil, fmt.Errorf("%s does not take arguments", name)
+	}
+
+	return TimeOp{}, nil
+}
+
+// OpType for the operator
+func (o TimeOp) OpType() string {
+	return TimeType
+}
+
+// String representation
+func (o TimeOp) String() string {
+	return fmt.Sprintf("type: %s", o.OpType())
+}
+
+// Node creates an execution node
+func (o TimeOp) Node(controller *transform.Controller, _ storage.Storage, options transform.Options) parser.Source {
+	return &TimeNode{controller: controller, timespec: options.TimeSpec}
+}
+
+// TimeNode is the execution node for the time function
+type TimeNode struct {
+	controller *transform.Controller
+	timespec   transform.TimeSpec
+}
+
+// Execute emits a block with a single series holding the time of every step in seconds
+func (n *TimeNode) Execute(ctx context.Context) error {
+	return executeStepValues(n.controller, n.timespec, func(t time.Time) float64 {
+		return float64(t.UnixNano()) / float64(time.Second)
+	})
+}
+
+// StringOp is a string literal
+type StringOp struct {
+	Val string
+}
+
+// OpType for the operator
+func (o StringOp) OpType() string {
+	return StringType
+}
+
+// String representation
+func (o StringOp) String() string {
+	return fmt.Sprintf("type: %s, value: %q", o.OpType(), o.Val)
+}
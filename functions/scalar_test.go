@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarNode(t *testing.T) {
+	now := time.Now()
+	controller, sink := newSink()
+	timespec := transform.TimeSpec{Start: now, End: now.Add(2 * time.Minute), Now: now, Step: time.Minute}
+	node := ScalarOp{Val: 4}.Node(controller, nil, transform.Options{TimeSpec: timespec})
+	require.NoError(t, node.Execute(context.Background()))
+	require.Len(t, sink.blocks, 1)
+
+	block := sink.blocks[0]
+	assert.Equal(t, now, block.Meta().Bounds.Start)
+	assert.Equal(t, []models.Tags{{}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{4}, {4}, {4}}, stepValues(block))
+}
+
+func TestTimeNode(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	controller, sink := newSink()
+	timespec := transform.TimeSpec{Start: start, End: start.Add(time.Minute), Now: start, Step: 30 * time.Second}
+	op, err := NewFunction(TimeType, nil, 0)
+	require.NoError(t, err)
+	node := op.(TimeOp).Node(controller, nil, transform.Options{TimeSpec: timespec})
+	require.NoError(t, node.Execute(context.Background()))
+	require.Len(t, sink.blocks, 1)
+
+	block := sink.blocks[0]
+	assert.Equal(t, []models.Tags{{}}, seriesTags(block))
+	assertValuesEqual(t, [][]float64{{1500000000}, {1500000030}, {1500000060}}, stepValues(block))
+
+	_, err = NewFunction(TimeType, []interface{}{1.0}, 0)
+	assert.Error(t, err)
+}
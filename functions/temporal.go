@@ -0,0 +1,233 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+)
+
+const (
+	// AvgOverTimeType averages the values in the range
+	AvgOverTimeType = "avg_over_time"
+	// MinOverTimeType takes the minimum of the values in the range
+	MinOverTimeType = "min_over_time"
+	// MaxOverTimeType takes the maximum of the values in the range
+	MaxOverTimeType = "max_over_time"
+	// SumOverTimeType adds the values in the range
+	SumOverTimeType = "sum_over_time"
+	// CountOverTimeType counts the values in the range
+	CountOverTimeType = "count_over_time"
+	// StdDevOverTimeType takes the population standard deviation of the values in the range
+	StdDevOverTimeType = "stddev_over_time"
+	// StdVarOverTimeType takes the population standard variance of the values in the range
+	StdVarOverTimeType = "stdvar_over_time"
+	// QuantileOverTimeType takes the φ-quantile of the values in the range
+	QuantileOverTimeType = "quantile_over_time"
+)
+
+// sample is a single non nan value in a range
+type sample struct {
+	t time.Time
+	v float64
+}
+
+// temporalFn computes the value of a range ending at rangeEnd from the samples in it
+type temporalFn func(samples []sample, rangeStart, rangeEnd time.Time, param float64) float64
+
+var temporalFns = map[string]temporalFn{
+	RateType:             rateFn,
+	IRateType:            irateFn,
+	IncreaseType:         increaseFn,
+	DeltaType:            deltaFn,
+	DerivType:            derivFn,
+	AvgOverTimeType:      overTime(avgFn),
+	MinOverTimeType:      overTime(minFn),
+	MaxOverTimeType:      overTime(maxFn),
+	SumOverTimeType:      overTime(sumFn),
+	CountOverTimeType:    overTime(countFn),
+	StdDevOverTimeType:   overTime(stdDevFn),
+	StdVarOverTimeType:   overTime(stdVarFn),
+	QuantileOverTimeType: overTime(quantileFn),
+}
+
+func init() {
+	for name := range temporalFns {
+		RegisterFunction(name, NewTemporalOp)
+	}
+}
+
+// TemporalOp stores required properties for functions over range vectors
+type TemporalOp struct {
+	OperatorType string
+	// Duration is the range of the range vector
+	Duration time.Duration
+	// Parameter is the numeric parameter for quantile_over_time
+	Parameter float64
+}
+
+// NewTemporalOp creates a new function over a range vector
+func NewTemporalOp(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	if _, ok := temporalFns[name]; !ok {
+		return nil, fmt.Errorf("unknown temporal function: %s", name)
+	}
+
+	if rangeDuration <= 0 {
+		return nil, fmt.Errorf("%s requires a range vector argument", name)
+	}
+
+	op := TemporalOp{OperatorType: name, Duration: rangeDuration}
+	if name != QuantileOverTimeType {
+		if len(arguments) != 0 {
+			return nil, fmt.Errorf("%s does not take literal arguments, got %v", name, arguments)
+		}
+
+		return op, nil
+	}
+
+	if len(arguments) != 1 {
+		return nil, fmt.Errorf("%s requires a single number argument, got %v", name, arguments)
+	}
+
+	param, ok := arguments[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a number argument, got %v", name, arguments[0])
+	}
+
+	op.Parameter = param
+	return op, nil
+}
+
+// OpType for the operator
+func (o TemporalOp) OpType() string {
+	return o.OperatorType
+}
+
+// String representation
+func (o TemporalOp) String() string {
+	return fmt.Sprintf("type: %s, duration: %v, parameter: %v", o.OpType(), o.Duration, o.Parameter)
+}
+
+// Node creates an execution node
+func (o TemporalOp) Node(controller *transform.Controller) transform.OpNode {
+	return &TemporalNode{op: o, controller: controller}
+}
+
+// TemporalNode is an execution node
+type TemporalNode struct {
+	op         TemporalOp
+	controller *transform.Controller
+}
+
+// Process the block. Functions over ranges are evaluated on the raw datapoints of the range selector, every
+// output step at time t uses the datapoints in [t - range, t]
+func (n *TemporalNode) Process(ID parser.NodeID, block storage.Block) error {
+	fn, ok := temporalFns[n.op.OperatorType]
+	if !ok {
+		return fmt.Errorf("unknown temporal function: %s", n.op.OperatorType)
+	}
+
+	rawBlock, ok := block.(storage.RawBlock)
+	if !ok {
+		return fmt.Errorf("%s requires the raw datapoints of a range selector", n.op.OperatorType)
+	}
+
+	bounds := block.Meta().Bounds
+	if bounds.StepSize <= 0 {
+		return fmt.Errorf("invalid step size for %s: %v", n.op.OperatorType, bounds.StepSize)
+	}
+
+	builder, err := n.controller.BlockBuilder(storage.BlockMetadata{Bounds: bounds}, dropMetricName(allSeriesMeta(block)))
+	if err != nil {
+		return err
+	}
+
+	var (
+		samples  []sample
+		buildErr error
+	)
+	for _, datapoints := range rawBlock.Datapoints() {
+		storage.RangeWindows(datapoints, bounds, n.op.Duration, func(idx int, window ts.Datapoints) {
+			if buildErr != nil {
+				return
+			}
+
+			samples = samples[:0]
+			for _, dp := range window {
+				if !math.IsNaN(dp.Value) {
+					samples = append(samples, sample{t: dp.Timestamp, v: dp.Value})
+				}
+			}
+
+			rangeEnd := bounds.TimeForIndex(idx)
+			buildErr = builder.AppendValue(idx, fn(samples, rangeEnd.Add(-n.op.Duration), rangeEnd, n.op.Parameter))
+		})
+
+		if buildErr != nil {
+			return buildErr
+		}
+	}
+
+	output, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	// The input is no longer read once the output is built
+	if err := block.Close(); err != nil {
+		return err
+	}
+
+	return n.controller.Process(output)
+}
+
+// dropMetricName removes the metric name from the series, as the values no longer represent the metric
+func dropMetricName(seriesMeta []storage.SeriesMeta) []storage.SeriesMeta {
+	for i, meta := range seriesMeta {
+		delete(meta.Tags, models.MetricName)
+		seriesMeta[i] = storage.SeriesMeta{Tags: meta.Tags, Name: meta.Tags.ID()}
+	}
+
+	return seriesMeta
+}
+
+// overTime applies an aggregation to the values in a range
+func overTime(fn aggregationFn) temporalFn {
+	return func(samples []sample, _, _ time.Time, param float64) float64 {
+		if len(samples) == 0 {
+			return math.NaN()
+		}
+
+		values := make([]float64, len(samples))
+		for i, s := range samples {
+			values[i] = s.v
+		}
+
+		return fn(values, param)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRawTestBlock creates a range selector block with a datapoint a minute for every value which is not NaN,
+// the steps of the block start once a full range of datapoints is available
+func newRawTestBlock(t *testing.T, tags []models.Tags, values [][]float64, rangeDuration time.Duration) storage.Block {
+	start := time.Now().Truncate(time.Minute)
+	bounds := storage.Bounds{
+		Start:    start.Add(rangeDuration),
+		End:      start.Add(time.Duration(len(values[0])-1) * time.Minute),
+		StepSize: time.Minute,
+	}
+
+	seriesMeta := make([]storage.SeriesMeta, len(tags))
+	datapoints := make([]ts.Datapoints, len(tags))
+	for i, tag := range tags {
+		seriesMeta[i] = storage.SeriesMeta{Tags: tag, Name: tag.ID()}
+		for j, v := range values[i] {
+			if !math.IsNaN(v) {
+				datapoints[i] = append(datapoints[i], &ts.Datapoint{Timestamp: start.Add(time.Duration(j) * time.Minute), Value: v})
+			}
+		}
+	}
+
+	block, err := storage.NewRawBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, datapoints, rangeDuration)
+	require.NoError(t, err)
+	return block
+}
+
+func processTemporal(t *testing.T, opType string, param float64, values [][]float64) [][]float64 {
+	controller, sink := newSink()
+	var arguments []interface{}
+	if opType == QuantileOverTimeType {
+		arguments = append(arguments, param)
+	}
+
+	op, err := NewTemporalOp(opType, arguments, 2*time.Minute)
+	require.NoError(t, err)
+
+	tags := []models.Tags{{models.MetricName: "requests", "job": "api"}}
+	block := newRawTestBlock(t, tags, values, 2*time.Minute)
+	require.NoError(t, op.(TemporalOp).Node(controller).Process(parser.NodeID("0"), block))
+	require.Len(t, sink.blocks, 1)
+
+	out := sink.blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(out.Meta().Bounds))
+	require.Len(t, out.SeriesMeta(), 1)
+	assert.Equal(t, models.Tags{"job": "api"}, out.SeriesMeta()[0].Tags)
+	return stepValues(out)
+}
+
+func TestTemporalFunctions(t *testing.T) {
+	counter := [][]float64{{0, 60, 120, 180, 240}}
+	reset := [][]float64{{0, 60, 120, 30, 90}}
+	sparse := [][]float64{{0, 60, nan, nan, 240}}
+
+	// Every step uses the datapoints of the last two minutes, both ends included
+	tests := []struct {
+		opType   string
+		param    float64
+		values   [][]float64
+		expected [][]float64
+	}{
+		{RateType, 0, counter, [][]float64{{1}, {1}, {1}}},
+		{RateType, 0, reset, [][]float64{{1}, {0.75}, {0.75}}},
+		{RateType, 0, sparse, [][]float64{{1}, {nan}, {nan}}},
+		{IncreaseType, 0, counter, [][]float64{{120}, {120}, {120}}},
+		{DeltaType, 0, reset, [][]float64{{120}, {-30}, {-30}}},
+		{IRateType, 0, counter, [][]float64{{1}, {1}, {1}}},
+		{IRateType, 0, reset, [][]float64{{1}, {0.5}, {1}}},
+		{DerivType, 0, counter, [][]float64{{1}, {1}, {1}}},
+		{AvgOverTimeType, 0, counter, [][]float64{{60}, {120}, {180}}},
+		{MinOverTimeType, 0, reset, [][]float64{{0}, {30}, {30}}},
+		{MaxOverTimeType, 0, reset, [][]float64{{120}, {120}, {120}}},
+		{SumOverTimeType, 0, counter, [][]float64{{180}, {360}, {540}}},
+		{CountOverTimeType, 0, sparse, [][]float64{{2}, {1}, {1}}},
+		{StdDevOverTimeType, 0, counter, [][]float64{{math.Sqrt(2400)}, {math.Sqrt(2400)}, {math.Sqrt(2400)}}},
+		{StdVarOverTimeType, 0, counter, [][]float64{{2400}, {2400}, {2400}}},
+		{QuantileOverTimeType, 0.5, counter, [][]float64{{60}, {120}, {180}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			assertValuesEqual(t, tt.expected, processTemporal(t, tt.opType, tt.param, tt.values))
+		})
+	}
+}
+
+func TestTemporalRangeShorterThanStep(t *testing.T) {
+	// A one minute range at a five minute step still sees the raw datapoints of its range
+	start := time.Now().Truncate(time.Hour)
+	var dps ts.Datapoints
+	for i := 0; i <= 24; i++ {
+		dps = append(dps, &ts.Datapoint{Timestamp: start.Add(time.Duration(i) * 15 * time.Second), Value: float64(i * 15)})
+	}
+
+	bounds := storage.Bounds{Start: start.Add(time.Minute), End: start.Add(6 * time.Minute), StepSize: 5 * time.Minute}
+	tags := models.Tags{"job": "api"}
+	block, err := storage.NewRawBlock(storage.BlockMetadata{Bounds: bounds},
+		[]storage.SeriesMeta{{Tags: tags, Name: tags.ID()}}, []ts.Datapoints{dps}, time.Minute)
+	require.NoError(t, err)
+
+	controller, sink := newSink()
+	op := TemporalOp{OperatorType: RateType, Duration: time.Minute}
+	require.NoError(t, op.Node(controller).Process(parser.NodeID("0"), block))
+	require.Len(t, sink.blocks, 1)
+	assertValuesEqual(t, [][]float64{{1}, {1}}, stepValues(sink.blocks[0]))
+}
+
+func TestTemporalRequiresRawBlock(t *testing.T) {
+	controller, _ := newSink()
+	op := TemporalOp{OperatorType: RateType, Duration: time.Minute}
+	block := newTestBlock(t, []models.Tags{{"job": "api"}}, [][]float64{{1, 2, 3}})
+	assert.Error(t, op.Node(controller).Process(parser.NodeID("0"), block))
+}
+
+func TestExtrapolatedRateLimitsToZero(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	samples := []sample{
+		{t: start.Add(2 * time.Minute), v: 10},
+		{t: start.Add(3 * time.Minute), v: 40},
+	}
+
+	// The counter would have been zero 20s before the first sample, so it is not extrapolated further back
+	increase := increaseFn(samples, start, start.Add(3*time.Minute), 0)
+	assert.InDelta(t, 40.0, increase, 1e-9)
+
+	delta := deltaFn(samples, start, start.Add(3*time.Minute), 0)
+	assert.InDelta(t, 45.0, delta, 1e-9)
+	assert.True(t, math.IsNaN(rateFn(samples[:1], start, start.Add(3*time.Minute), 0)))
+}
+
+func TestNewTemporalOp(t *testing.T) {
+	op, err := NewFunction(RateType, nil, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, TemporalOp{OperatorType: RateType, Duration: 5 * time.Minute}, op)
+
+	op, err = NewFunction(QuantileOverTimeType, []interface{}{0.9}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, TemporalOp{OperatorType: QuantileOverTimeType, Duration: time.Minute, Parameter: 0.9}, op)
+
+	_, err = NewFunction(RateType, nil, 0)
+	assert.Error(t, err)
+	_, err = NewFunction(RateType, []interface{}{1.0}, time.Minute)
+	assert.Error(t, err)
+	_, err = NewFunction(QuantileOverTimeType, []interface{}{"0.9"}, time.Minute)
+	assert.Error(t, err)
+	_, err = NewFunction(QuantileOverTimeType, nil, time.Minute)
+	assert.Error(t, err)
+
+	// Functions which are not registered are not supported
+	_, err = NewFunction("holt_winters", nil, time.Minute)
+	assert.EqualError(t, err, "unsupported function: holt_winters")
+}
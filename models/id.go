@@ -0,0 +1,142 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	idCountSeparator = '|'
+	idNameTerminator = '='
+	idTagTerminator  = ','
+	idEscape         = '\\'
+)
+
+// ID returns the canonical series ID for the tags. The ID is collision free and
+// can be decoded back into tags using TagsFromID.
+//
+// The ID is the number of tags followed by the tags sorted by name, each written
+// as an escaped name=value pair, e.g. tags {job="api,web", __name__="up"} have the
+// ID `2|__name__=up,job=api\,web,`.
+func (t Tags) ID() string {
+	names := make([]string, 0, len(t))
+	size := 0
+	for name, value := range t {
+		names = append(names, name)
+		size += len(name) + len(value) + 2
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.Grow(size + 4)
+	b.WriteString(strconv.Itoa(len(t)))
+	b.WriteByte(idCountSeparator)
+	for _, name := range names {
+		writeEscaped(&b, name)
+		b.WriteByte(idNameTerminator)
+		writeEscaped(&b, t[name])
+		b.WriteByte(idTagTerminator)
+	}
+
+	return b.String()
+}
+
+func writeEscaped(b *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == idEscape || c == idNameTerminator || c == idTagTerminator {
+			b.WriteByte(idEscape)
+		}
+
+		b.WriteByte(c)
+	}
+}
+
+// TagsFromID decodes an ID generated by Tags.ID back into tags
+func TagsFromID(id string) (Tags, error) {
+	sep := strings.IndexByte(id, idCountSeparator)
+	if sep < 0 {
+		return nil, fmt.Errorf("invalid series id %q: missing tag count", id)
+	}
+
+	count, err := strconv.Atoi(id[:sep])
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid series id %q: bad tag count", id)
+	}
+
+	tags := make(Tags, count)
+	rest := id[sep+1:]
+	for i := 0; i < count; i++ {
+		var name, value string
+		name, rest, err = readEscaped(rest, idNameTerminator)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series id %q: %v", id, err)
+		}
+
+		value, rest, err = readEscaped(rest, idTagTerminator)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series id %q: %v", id, err)
+		}
+
+		if _, exists := tags[name]; exists {
+			return nil, fmt.Errorf("invalid series id %q: duplicate tag %s", id, name)
+		}
+
+		tags[name] = value
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid series id %q: unexpected trailing data", id)
+	}
+
+	return tags, nil
+}
+
+// readEscaped reads an escaped string up to the terminator and returns it along
+// with the remainder of the input after the terminator
+func readEscaped(s string, terminator byte) (string, string, error) {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == idEscape:
+			i++
+			if i == len(s) {
+				return "", "", fmt.Errorf("dangling escape character")
+			}
+
+			b.WriteByte(s[i])
+		case c == terminator:
+			return b.String(), s[i+1:], nil
+		case c == idNameTerminator || c == idTagTerminator:
+			return "", "", fmt.Errorf("unescaped separator %q", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", "", fmt.Errorf("missing terminator %q", terminator)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDSortedAndEscaped(t *testing.T) {
+	tags := Tags{"job": "api,web", "__name__": "up", `a\b`: "c=d"}
+	assert.Equal(t, `3|__name__=up,a\\b=c\=d,job=api\,web,`, tags.ID())
+	assert.Equal(t, "0|", Tags{}.ID())
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	tests := []Tags{
+		{},
+		{"foo": "bar"},
+		{"foo": ""},
+		{"": "bar"},
+		{"__name__": "http_requests_total", "method": "GET", "code": "200"},
+		{"a": `\`, "b": `\\=,`, "c": ",,,", "d": "==="},
+		{"unicode": "日本語", "pipe": "a|b"},
+	}
+
+	for _, tags := range tests {
+		decoded, err := TagsFromID(tags.ID())
+		require.NoError(t, err, tags.ID())
+		assert.Equal(t, tags, decoded)
+	}
+}
+
+func TestIDNoCollisions(t *testing.T) {
+	// Each of these pairs collide when tags are simply concatenated
+	pairs := [][2]Tags{
+		{{"a": "b,c=d"}, {"a": "b", "c": "d"}},
+		{{"a=b": "c"}, {"a": "b=c"}},
+		{{"ab": "c"}, {"a": "bc"}},
+		{{"a": `b\`, "c": "d"}, {"a": `b\,c=d`}},
+	}
+
+	for _, pair := range pairs {
+		assert.NotEqual(t, pair[0].ID(), pair[1].ID())
+	}
+}
+
+func TestTagsFromInvalidID(t *testing.T) {
+	ids := []string{
+		"",
+		"12345",
+		"x|a=b,",
+		"-1|",
+		"2|a=b,",
+		"1|a=b,c=d,",
+		"1|a=b",
+		"1|ab,",
+		`1|a=b\`,
+		"1|a=b=c,",
+		"2|a=b,a=c,",
+	}
+
+	for _, id := range ids {
+		_, err := TagsFromID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+// legacyHashID is the FNV-32 hash based ID which was previously used for tags
+func legacyHashID(t Tags) string {
+	var b string
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b += k
+		b += "="
+		b += t[k]
+		b += ","
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(b))
+	return fmt.Sprintf("%d", h.Sum32())
+}
+
+func benchmarkTags() Tags {
+	return Tags{
+		"__name__": "http_request_duration_seconds_bucket",
+		"code":     "200",
+		"handler":  "/api/v1/query_range",
+		"instance": "localhost:9090",
+		"job":      "prometheus",
+		"le":       "0.25",
+		"method":   "get",
+	}
+}
+
+func BenchmarkTagsID(b *testing.B) {
+	tags := benchmarkTags()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tags.ID()
+	}
+}
+
+func BenchmarkTagsLegacyHashID(b *testing.B) {
+	tags := benchmarkTags()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = legacyHashID(tags)
+	}
+}
+
+func BenchmarkTagsFromID(b *testing.B) {
+	id := benchmarkTags().ID()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := TagsFromID(id); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -22,11 +22,12 @@ package models
 
 import (
 	"fmt"
-	"hash/fnv"
 	"regexp"
-	"sort"
 )
 
+// MetricName is the tag holding the name of a metric
+const MetricName = "__name__"
+
 // Tags is a key/value map of metric tags.
 type Tags map[string]string
 
@@ -125,28 +126,3 @@ func (m Matchers) ToTags() (Tags, error) {
 
 	return tags, nil
 }
-
-// ID returns a string representation of the tags
-func (t Tags) ID() string {
-	sep := ","
-	eq := "="
-
-	var b string
-	var keys []string
-
-	for k := range t {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	for _, k := range keys {
-		b += k
-		b += eq
-		b += t[k]
-		b += sep
-	}
-
-	h := fnv.New32a()
-	h.Write([]byte(b))
-	return fmt.Sprintf("%d", h.Sum32())
-}
@@ -107,5 +107,5 @@ func TestMatchType(t *testing.T) {
 func TestTagID(t *testing.T) {
 	tags := make(Tags)
 	tags["t1"] = "v1"
-	assert.Equal(t, tags.ID(), "2922638416")
+	assert.Equal(t, tags.ID(), "1|t1=v1,")
 }
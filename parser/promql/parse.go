@@ -22,6 +22,7 @@ package promql
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/m3db/m3coordinator/errors"
 	"github.com/m3db/m3coordinator/parser"
@@ -44,63 +45,191 @@ func Parse(q string) (parser.Parser, error) {
 }
 
 func (p *promParser) DAG() (parser.Nodes, parser.Edges, error) {
-	return walk(p.expr)
+	state := &parseState{}
+	if err := state.walk(p.expr); err != nil {
+		return nil, nil, err
+	}
+
+	return state.transforms, state.edges, nil
 }
 
 func (p *promParser) String() string {
 	return p.expr.String()
 }
 
-func walk(node pql.Node) (parser.Nodes, parser.Edges, error) {
-	if node == nil {
-		return nil, nil, nil
+type parseState struct {
+	edges      parser.Edges
+	transforms parser.Nodes
+}
+
+// lastID is the ID of the most recently added transform, which is the root of the last walked expression
+func (p *parseState) lastID() parser.NodeID {
+	return p.transforms[len(p.transforms)-1].ID
+}
+
+// addTransform adds a transform which is a child of all the given parents
+func (p *parseState) addTransform(op parser.Params, parents ...parser.NodeID) {
+	transform := parser.NewTransformFromOperation(op, len(p.transforms))
+	for _, parent := range parents {
+		p.edges = append(p.edges, parser.Edge{
+			ParentID: parent,
+			ChildID:  transform.ID,
+		})
 	}
 
-	switch n := node.(type) {
-	case pql.Statements:
-	case *pql.AlertStmt:
+	p.transforms = append(p.transforms, transform)
+}
+
+// walkParent walks the expression and returns the ID of its root
+func (p *parseState) walkParent(expr pql.Expr) (parser.NodeID, error) {
+	if err := p.walk(expr); err != nil {
+		return "", err
+	}
+
+	if len(p.transforms) == 0 {
+		return "", fmt.Errorf("promql.Walk: no transform generated for %s", expr)
+	}
 
-	case *pql.EvalStmt:
+	return p.lastID(), nil
+}
 
-	case *pql.RecordStmt:
+func (p *parseState) walk(node pql.Node) error {
+	if node == nil {
+		return nil
+	}
 
-	case pql.Expressions:
+	switch n := node.(type) {
 	case *pql.AggregateExpr:
-		transforms, edges, err := walk(n.Expr)
+		parent, err := p.walkParent(n.Expr)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
 
-		opTransform := parser.NewTransformFromOperation(NewOperator(n.Op), len(transforms))
-		edges = append(edges, parser.Edge{
-			ParentID: transforms[len(transforms)-1].ID,
-			ChildID:  opTransform.ID,
-		})
-		transforms = append(transforms, opTransform)
-		// TODO: handle labels, params
-		return transforms, edges, nil
+		op, err := NewAggregationOperator(n)
+		if err != nil {
+			return err
+		}
+
+		p.addTransform(op, parent)
+		return nil
+
 	case *pql.BinaryExpr:
+		lhs, err := p.walkParent(n.LHS)
+		if err != nil {
+			return err
+		}
+
+		rhs, err := p.walkParent(n.RHS)
+		if err != nil {
+			return err
+		}
+
+		op, err := NewBinaryOperator(n, lhs, rhs)
+		if err != nil {
+			return err
+		}
+
+		p.addTransform(op, lhs, rhs)
+		return nil
 
 	case *pql.Call:
+		arguments := make([]interface{}, 0, len(n.Args))
+		parents := make([]parser.NodeID, 0, len(n.Args))
+		var rangeDuration time.Duration
+		for _, arg := range n.Args {
+			switch a := unwrapParenExpr(arg).(type) {
+			case *pql.NumberLiteral:
+				arguments = append(arguments, a.Val)
+			case *pql.StringLiteral:
+				arguments = append(arguments, a.Val)
+			default:
+				if matrix, ok := a.(*pql.MatrixSelector); ok {
+					rangeDuration = matrix.Range
+				}
+
+				parent, err := p.walkParent(a)
+				if err != nil {
+					return err
+				}
+
+				parents = append(parents, parent)
+			}
+		}
+
+		op, err := NewFunctionExpr(n.Func.Name, arguments, rangeDuration)
+		if err != nil {
+			return err
+		}
+
+		p.addTransform(op, parents...)
+		return nil
 
 	case *pql.ParenExpr:
+		return p.walk(n.Expr)
 
 	case *pql.UnaryExpr:
+		if n.Op == pql.ItemType(itemADD) {
+			return p.walk(n.Expr)
+		}
+
+		if n.Op != pql.ItemType(itemSUB) {
+			return fmt.Errorf("promql.Walk: unhandled unary operator %v", n.Op)
+		}
+
+		// Negation is a multiplication by -1 which also drops the metric name
+		p.addTransform(NewScalarOperator(-1))
+		lhs := p.lastID()
+		rhs, err := p.walkParent(n.Expr)
+		if err != nil {
+			return err
+		}
+
+		op := NewNegationOperator(lhs, rhs, n.Expr.Type() == pql.ValueTypeScalar)
+		p.addTransform(op, lhs, rhs)
+		return nil
 
 	case *pql.MatrixSelector:
-		operation := NewSelectorFromMatrix(n)
-		return []parser.Node{parser.NewTransformFromOperation(operation, 0)}, nil, nil
+		op, err := NewSelectorFromMatrix(n)
+		if err != nil {
+			return err
+		}
+
+		p.addTransform(op)
+		return nil
 
 	case *pql.VectorSelector:
-		operation := NewSelectorFromVector(n)
-		return []parser.Node{parser.NewTransformFromOperation(operation, 0)}, nil, nil
+		op, err := NewSelectorFromVector(n)
+		if err != nil {
+			return err
+		}
 
-	case *pql.NumberLiteral, *pql.StringLiteral:
+		p.addTransform(op)
+		return nil
+
+	case *pql.NumberLiteral:
+		p.addTransform(NewScalarOperator(n.Val))
+		return nil
+
+	case *pql.StringLiteral:
+		p.addTransform(NewStringOperator(n.Val))
+		return nil
+
+	case pql.Statements, *pql.AlertStmt, *pql.EvalStmt, *pql.RecordStmt, pql.Expressions:
+		// Statements are not produced when parsing expressions
+		return errors.ErrNotImplemented
 
 	default:
-		return nil, nil, fmt.Errorf("promql.Walk: unhandled node type %T", node)
+		return fmt.Errorf("promql.Walk: unhandled node type %T", node)
 	}
+}
 
-	// TODO: This should go away once all cases have been implemented
-	return nil, nil, errors.ErrNotImplemented
+func unwrapParenExpr(expr pql.Expr) pql.Expr {
+	for {
+		paren, ok := expr.(*pql.ParenExpr)
+		if !ok {
+			return expr
+		}
+
+		expr = paren.Expr
+	}
 }
@@ -21,6 +21,7 @@
 package promql
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/m3db/m3coordinator/functions"
@@ -45,3 +46,213 @@ func TestDAG(t *testing.T) {
 	assert.Equal(t, edges[0].ChildID, parser.NodeID("1"), "aggregation should be the child")
 
 }
+
+type expectedDAG struct {
+	query string
+	ops   []string
+	// edges are pairs of parent, child IDs
+	edges [][2]string
+}
+
+// Queries commonly sent by Grafana dashboards
+var grafanaQueries = []expectedDAG{
+	{
+		query: `up`,
+		ops:   []string{functions.FetchType},
+	},
+	{
+		query: `rate(http_requests_total{job="api"}[5m])`,
+		ops:   []string{functions.FetchType, "rate"},
+		edges: [][2]string{{"0", "1"}},
+	},
+	{
+		query: `sum(rate(http_requests_total{job="api"}[5m])) by (code)`,
+		ops:   []string{functions.FetchType, "rate", functions.SumType},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}},
+	},
+	{
+		query: `histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le))`,
+		ops:   []string{functions.FetchType, "rate", functions.SumType, "histogram_quantile"},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}, {"2", "3"}},
+	},
+	{
+		query: `sum(rate(errors_total[1m])) / sum(rate(requests_total[1m]))`,
+		ops: []string{functions.FetchType, "rate", functions.SumType,
+			functions.FetchType, "rate", functions.SumType, functions.DivType},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}, {"3", "4"}, {"4", "5"}, {"2", "6"}, {"5", "6"}},
+	},
+	{
+		query: `100 * (1 - avg by (instance) (irate(node_cpu{mode="idle"}[5m])))`,
+		ops: []string{functions.ScalarType, functions.ScalarType, functions.FetchType,
+			"irate", functions.AvgType, functions.MinusType, functions.MultiplyType},
+		edges: [][2]string{{"2", "3"}, {"3", "4"}, {"1", "5"}, {"4", "5"}, {"0", "6"}, {"5", "6"}},
+	},
+	{
+		query: `topk(5, sum without (instance) (up))`,
+		ops:   []string{functions.FetchType, functions.SumType, functions.TopKType},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}},
+	},
+	{
+		query: `count_values("version", build_info)`,
+		ops:   []string{functions.FetchType, functions.CountValuesType},
+		edges: [][2]string{{"0", "1"}},
+	},
+	{
+		query: `-up`,
+		ops:   []string{functions.ScalarType, functions.FetchType, functions.MultiplyType},
+		edges: [][2]string{{"0", "2"}, {"1", "2"}},
+	},
+	{
+		query: `+up`,
+		ops:   []string{functions.FetchType},
+	},
+	{
+		query: `up == bool 1`,
+		ops:   []string{functions.FetchType, functions.ScalarType, functions.EqType},
+		edges: [][2]string{{"0", "2"}, {"1", "2"}},
+	},
+	{
+		query: `node_filesystem_free / on(instance, device) group_left(job) node_filesystem_size`,
+		ops:   []string{functions.FetchType, functions.FetchType, functions.DivType},
+		edges: [][2]string{{"0", "2"}, {"1", "2"}},
+	},
+	{
+		query: `up and ignoring(job) (process_start_time_seconds > 0)`,
+		ops: []string{functions.FetchType, functions.FetchType, functions.ScalarType,
+			functions.GreaterType, functions.AndType},
+		edges: [][2]string{{"1", "3"}, {"2", "3"}, {"0", "4"}, {"3", "4"}},
+	},
+	{
+		query: `max_over_time(process_resident_memory_bytes[1h] offset 1d)`,
+		ops:   []string{functions.FetchType, "max_over_time"},
+		edges: [][2]string{{"0", "1"}},
+	},
+	{
+		query: `clamp_max(rate(cpu_seconds_total[5m]), 1)`,
+		ops:   []string{functions.FetchType, "rate", "clamp_max"},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}},
+	},
+	{
+		query: `time() - process_start_time_seconds`,
+		ops:   []string{"time", functions.FetchType, functions.MinusType},
+		edges: [][2]string{{"0", "2"}, {"1", "2"}},
+	},
+	{
+		query: `quantile(0.9, rate(http_requests_total[5m]))`,
+		ops:   []string{functions.FetchType, "rate", functions.QuantileType},
+		edges: [][2]string{{"0", "1"}, {"1", "2"}},
+	},
+	{
+		query: `((up))`,
+		ops:   []string{functions.FetchType},
+	},
+	{
+		query: `1 + 2`,
+		ops:   []string{functions.ScalarType, functions.ScalarType, functions.PlusType},
+		edges: [][2]string{{"0", "2"}, {"1", "2"}},
+	},
+	{
+		query: `"foo"`,
+		ops:   []string{functions.StringType},
+	},
+}
+
+func TestGrafanaQueriesDAG(t *testing.T) {
+	for _, tt := range grafanaQueries {
+		t.Run(tt.query, func(t *testing.T) {
+			p, err := Parse(tt.query)
+			require.NoError(t, err)
+			transforms, edges, err := p.DAG()
+			require.NoError(t, err)
+			require.Len(t, transforms, len(tt.ops))
+			for i, transform := range transforms {
+				assert.Equal(t, parser.NodeID(strconv.Itoa(i)), transform.ID)
+				assert.Equal(t, tt.ops[i], transform.Op.OpType())
+			}
+
+			require.Len(t, edges, len(tt.edges))
+			for i, edge := range edges {
+				assert.Equal(t, parser.NodeID(tt.edges[i][0]), edge.ParentID)
+				assert.Equal(t, parser.NodeID(tt.edges[i][1]), edge.ChildID)
+			}
+		})
+	}
+}
+
+func TestAggregationParams(t *testing.T) {
+	p, err := Parse(`topk(5, sum without (instance, job) (up))`)
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+
+	sum, ok := transforms[1].Op.(functions.AggregationOp)
+	require.True(t, ok)
+	assert.Equal(t, []string{"instance", "job"}, sum.Params.MatchingTags)
+	assert.True(t, sum.Params.Without)
+
+	topk, ok := transforms[2].Op.(functions.AggregationOp)
+	require.True(t, ok)
+	assert.Equal(t, 5.0, topk.Params.Parameter)
+	assert.False(t, topk.Params.Without)
+
+	p, err = Parse(`count_values("version", build_info)`)
+	require.NoError(t, err)
+	transforms, _, err = p.DAG()
+	require.NoError(t, err)
+	countValues, ok := transforms[1].Op.(functions.AggregationOp)
+	require.True(t, ok)
+	assert.Equal(t, "version", countValues.Params.StringParameter)
+}
+
+func TestBinaryParams(t *testing.T) {
+	p, err := Parse(`a / on(instance) group_left(job) b`)
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+
+	op, ok := transforms[2].Op.(functions.BinaryOp)
+	require.True(t, ok)
+	assert.Equal(t, transforms[0].ID, op.Params.LNode)
+	assert.Equal(t, transforms[1].ID, op.Params.RNode)
+	assert.False(t, op.Params.LIsScalar)
+	assert.False(t, op.Params.RIsScalar)
+	require.NotNil(t, op.Params.VectorMatching)
+	assert.Equal(t, functions.CardManyToOne, op.Params.VectorMatching.Card)
+	assert.Equal(t, []string{"instance"}, op.Params.VectorMatching.MatchingLabels)
+	assert.True(t, op.Params.VectorMatching.On)
+	assert.Equal(t, []string{"job"}, op.Params.VectorMatching.Include)
+
+	p, err = Parse(`up > bool 1`)
+	require.NoError(t, err)
+	transforms, _, err = p.DAG()
+	require.NoError(t, err)
+	op, ok = transforms[2].Op.(functions.BinaryOp)
+	require.True(t, ok)
+	assert.True(t, op.Params.ReturnBool)
+	assert.True(t, op.Params.RIsScalar)
+}
+
+func TestFunctionArguments(t *testing.T) {
+	p, err := Parse(`label_replace(up, "dst", "$1", "src", "(.*)")`)
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	require.Len(t, edges, 1)
+
+	op, ok := transforms[1].Op.(functions.LabelReplaceOp)
+	require.True(t, ok)
+	assert.Equal(t, "dst", op.Destination)
+	assert.Equal(t, "$1", op.Replacement)
+	assert.Equal(t, "src", op.Source)
+	assert.Equal(t, "^(?:(.*))$", op.Regex.String())
+}
+
+func TestUnsupportedFunction(t *testing.T) {
+	p, err := Parse(`holt_winters(up[5m], 0.5, 0.5)`)
+	require.NoError(t, err)
+	_, _, err = p.DAG()
+	assert.EqualError(t, err, "unsupported function: holt_winters")
+}
@@ -21,40 +21,254 @@
 package promql
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser"
 	"github.com/m3db/m3coordinator/parser/common"
 
+	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/promql"
 )
 
 // NewSelectorFromVector creates a new fetchop
-func NewSelectorFromVector(n *promql.VectorSelector) parser.Params {
-	// TODO: convert n.LabelMatchers to Matchers
-	return functions.FetchOp{Name: n.Name, Offset: n.Offset, Matchers: nil}
+func NewSelectorFromVector(n *promql.VectorSelector) (parser.Params, error) {
+	matchers, err := labelMatchersToModelMatcher(n.LabelMatchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return functions.FetchOp{Name: n.Name, Offset: n.Offset, Matchers: matchers}, nil
 }
 
 // NewSelectorFromMatrix creates a new fetchop
-func NewSelectorFromMatrix(n *promql.MatrixSelector) parser.Params {
-	// TODO: convert n.LabelMatchers to Matchers
-	return functions.FetchOp{Name: n.Name, Offset: n.Offset, Matchers: nil, Range: n.Range}
+func NewSelectorFromMatrix(n *promql.MatrixSelector) (parser.Params, error) {
+	matchers, err := labelMatchersToModelMatcher(n.LabelMatchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return functions.FetchOp{Name: n.Name, Offset: n.Offset, Matchers: matchers, Range: n.Range}, nil
+}
+
+// ParseMatchers parses a series selector, such as `up{job="api"}`, into matchers
+func ParseMatchers(selector string) (models.Matchers, error) {
+	lMatchers, err := promql.ParseMetricSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return labelMatchersToModelMatcher(lMatchers)
+}
+
+// labelMatchersToModelMatcher converts Prometheus label matchers, which include
+// the __name__ matcher for the metric name, to models.Matchers
+func labelMatchersToModelMatcher(lMatchers []*labels.Matcher) (models.Matchers, error) {
+	matchers := make(models.Matchers, 0, len(lMatchers))
+	for _, m := range lMatchers {
+		matchType, err := promTypeToM3(m.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		match, err := models.NewMatcher(matchType, m.Name, m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %s: %v", m, err)
+		}
+
+		matchers = append(matchers, match)
+	}
+
+	return matchers, nil
 }
 
-// NewOperator creates a new operator based on the type
-func NewOperator(opType promql.ItemType) parser.Params {
-	switch getOpType(opType) {
-	case functions.CountType:
-		return functions.CountOp{}
+// promTypeToM3 converts a prometheus label type to m3 matcher type
+func promTypeToM3(labelType labels.MatchType) (models.MatchType, error) {
+	switch labelType {
+	case labels.MatchEqual:
+		return models.MatchEqual, nil
+	case labels.MatchNotEqual:
+		return models.MatchNotEqual, nil
+	case labels.MatchRegexp:
+		return models.MatchRegexp, nil
+	case labels.MatchNotRegexp:
+		return models.MatchNotRegexp, nil
+
+	default:
+		return 0, fmt.Errorf("unknown match type %v", labelType)
 	}
-	// TODO: handle other types
-	return nil
 }
 
-func getOpType(opType promql.ItemType) string {
+// NewAggregationOperator creates a new aggregation operator based on the type
+func NewAggregationOperator(expr *promql.AggregateExpr) (parser.Params, error) {
+	opType := getAggOpType(expr.Op)
+	if opType == common.UnknownOpType {
+		return nil, fmt.Errorf("operator not supported: %s", expr.Op)
+	}
+
+	params := functions.AggregationParams{
+		MatchingTags: expr.Grouping,
+		Without:      expr.Without,
+	}
+
 	switch opType {
+	case functions.TopKType, functions.BottomKType, functions.QuantileType:
+		val, ok := unwrapParenExpr(expr.Param).(*promql.NumberLiteral)
+		if !ok {
+			return nil, fmt.Errorf("%s requires a number literal parameter, got %v", opType, expr.Param)
+		}
+
+		params.Parameter = val.Val
+
+	case functions.CountValuesType:
+		val, ok := unwrapParenExpr(expr.Param).(*promql.StringLiteral)
+		if !ok {
+			return nil, fmt.Errorf("%s requires a string literal parameter, got %v", opType, expr.Param)
+		}
+
+		params.StringParameter = val.Val
+	}
+
+	return functions.AggregationOp{OperatorType: opType, Params: params}, nil
+}
+
+func getAggOpType(opType promql.ItemType) string {
+	switch opType {
+	case promql.ItemType(itemSum):
+		return functions.SumType
+	case promql.ItemType(itemMin):
+		return functions.MinType
+	case promql.ItemType(itemMax):
+		return functions.MaxType
+	case promql.ItemType(itemAvg):
+		return functions.AvgType
+	case promql.ItemType(itemStddev):
+		return functions.StdDevType
+	case promql.ItemType(itemStdvar):
+		return functions.StdVarType
 	case promql.ItemType(itemCount):
 		return functions.CountType
+	case promql.ItemType(itemTopK):
+		return functions.TopKType
+	case promql.ItemType(itemBottomK):
+		return functions.BottomKType
+	case promql.ItemType(itemQuantile):
+		return functions.QuantileType
+	case promql.ItemType(itemCountValues):
+		return functions.CountValuesType
 	default:
 		return common.UnknownOpType
 	}
 }
+
+// NewBinaryOperator creates a new binary operator based on the type
+func NewBinaryOperator(expr *promql.BinaryExpr, lhs, rhs parser.NodeID) (parser.Params, error) {
+	opType := getBinaryOpType(expr.Op)
+	if opType == common.UnknownOpType {
+		return nil, fmt.Errorf("operator not supported: %s", expr.Op)
+	}
+
+	params := functions.BinaryParams{
+		LNode:          lhs,
+		RNode:          rhs,
+		LIsScalar:      expr.LHS.Type() == promql.ValueTypeScalar,
+		RIsScalar:      expr.RHS.Type() == promql.ValueTypeScalar,
+		ReturnBool:     expr.ReturnBool,
+		VectorMatching: promMatchingToM3(expr.VectorMatching),
+	}
+
+	return functions.BinaryOp{OperatorType: opType, Params: params}, nil
+}
+
+// NewNegationOperator creates a new operator which negates the rhs, where lhs is a scalar -1
+func NewNegationOperator(lhs, rhs parser.NodeID, rhsIsScalar bool) parser.Params {
+	return functions.BinaryOp{
+		OperatorType: functions.MultiplyType,
+		Params: functions.BinaryParams{
+			LNode:     lhs,
+			RNode:     rhs,
+			LIsScalar: true,
+			RIsScalar: rhsIsScalar,
+		},
+	}
+}
+
+func promMatchingToM3(matching *promql.VectorMatching) *functions.VectorMatching {
+	if matching == nil {
+		return nil
+	}
+
+	return &functions.VectorMatching{
+		Card:           promCardToM3(matching.Card),
+		MatchingLabels: matching.MatchingLabels,
+		On:             matching.On,
+		Include:        matching.Include,
+	}
+}
+
+func promCardToM3(card promql.VectorMatchCardinality) functions.VectorMatchCardinality {
+	switch card {
+	case promql.CardManyToOne:
+		return functions.CardManyToOne
+	case promql.CardOneToMany:
+		return functions.CardOneToMany
+	case promql.CardManyToMany:
+		return functions.CardManyToMany
+	default:
+		return functions.CardOneToOne
+	}
+}
+
+func getBinaryOpType(opType promql.ItemType) string {
+	switch opType {
+	case promql.ItemType(itemADD):
+		return functions.PlusType
+	case promql.ItemType(itemSUB):
+		return functions.MinusType
+	case promql.ItemType(itemMUL):
+		return functions.MultiplyType
+	case promql.ItemType(itemDIV):
+		return functions.DivType
+	case promql.ItemType(itemMOD):
+		return functions.ModType
+	case promql.ItemType(itemPOW):
+		return functions.PowType
+	case promql.ItemType(itemEQL):
+		return functions.EqType
+	case promql.ItemType(itemNEQ):
+		return functions.NotEqType
+	case promql.ItemType(itemGTR):
+		return functions.GreaterType
+	case promql.ItemType(itemLSS):
+		return functions.LesserType
+	case promql.ItemType(itemGTE):
+		return functions.GreaterEqType
+	case promql.ItemType(itemLTE):
+		return functions.LesserEqType
+	case promql.ItemType(itemLAND):
+		return functions.AndType
+	case promql.ItemType(itemLOR):
+		return functions.OrType
+	case promql.ItemType(itemLUnless):
+		return functions.UnlessType
+	default:
+		return common.UnknownOpType
+	}
+}
+
+// NewFunctionExpr creates a new function call with the given literal arguments and the range of its range vector argument
+func NewFunctionExpr(name string, arguments []interface{}, rangeDuration time.Duration) (parser.Params, error) {
+	return functions.NewFunction(name, arguments, rangeDuration)
+}
+
+// NewScalarOperator creates a new scalar literal
+func NewScalarOperator(val float64) parser.Params {
+	return functions.ScalarOp{Val: val}
+}
+
+// NewStringOperator creates a new string literal
+func NewStringOperator(val string) parser.Params {
+	return functions.StringOp{Val: val}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatchers(t *testing.T) {
+	p, err := Parse(`http_requests_total{job="api", method!="GET", code=~"5..", handler!~"/debug.*"}[5m]`)
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 1)
+
+	op, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Minute, op.Range)
+
+	expected := map[string]models.MatchType{
+		"job":      models.MatchEqual,
+		"method":   models.MatchNotEqual,
+		"code":     models.MatchRegexp,
+		"handler":  models.MatchNotRegexp,
+		"__name__": models.MatchEqual,
+	}
+	require.Len(t, op.Matchers, len(expected))
+	for _, m := range op.Matchers {
+		matchType, ok := expected[m.Name]
+		require.True(t, ok, "unexpected matcher %s", m)
+		assert.Equal(t, matchType, m.Type, m.Name)
+	}
+
+	assert.True(t, findMatcher(t, op.Matchers, "__name__").Matches("http_requests_total"))
+	assert.True(t, findMatcher(t, op.Matchers, "code").Matches("503"))
+	assert.False(t, findMatcher(t, op.Matchers, "code").Matches("200"))
+	assert.False(t, findMatcher(t, op.Matchers, "handler").Matches("/debug/pprof"))
+}
+
+func findMatcher(t *testing.T, matchers models.Matchers, name string) *models.Matcher {
+	for _, m := range matchers {
+		if m.Name == name {
+			return m
+		}
+	}
+
+	require.FailNow(t, "matcher not found", name)
+	return nil
+}
+
+func TestVectorSelectorWithoutName(t *testing.T) {
+	p, err := Parse(`{job="api"}`)
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	op, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	require.Len(t, op.Matchers, 1)
+	assert.Equal(t, "job", op.Matchers[0].Name)
+	assert.Equal(t, "api", op.Matchers[0].Value)
+}
+
+func TestInvalidRegexMatcher(t *testing.T) {
+	lMatchers := []*labels.Matcher{
+		{Type: labels.MatchRegexp, Name: "job", Value: "(api"},
+	}
+
+	_, err := labelMatchersToModelMatcher(lMatchers)
+	assert.Error(t, err)
+
+	lMatchers[0].Type = labels.MatchNotRegexp
+	_, err = labelMatchersToModelMatcher(lMatchers)
+	assert.Error(t, err)
+
+	lMatchers[0].Type = labels.MatchEqual
+	matchers, err := labelMatchersToModelMatcher(lMatchers)
+	require.NoError(t, err)
+	assert.True(t, matchers[0].Matches("(api"))
+}
+
+func TestTemporalFunction(t *testing.T) {
+	p, err := Parse(`quantile_over_time(0.9, http_request_duration_seconds[10m])`)
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	require.Len(t, edges, 1)
+
+	op, ok := transforms[1].Op.(functions.TemporalOp)
+	require.True(t, ok)
+	assert.Equal(t, functions.QuantileOverTimeType, op.OpType())
+	assert.Equal(t, 10*time.Minute, op.Duration)
+	assert.Equal(t, 0.9, op.Parameter)
+}
@@ -22,8 +22,10 @@ package plan
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser"
 	"github.com/m3db/m3coordinator/storage"
 )
@@ -33,7 +35,7 @@ type PhysicalPlan struct {
 	steps      map[parser.NodeID]LogicalStep
 	pipeline   []parser.NodeID // Ordered list of steps to be performed
 	ResultStep ResultOp
-	Now        time.Time
+	TimeSpec   transform.TimeSpec
 }
 
 // ResultOp is resonsible for delivering results to the clients
@@ -44,13 +46,22 @@ type ResultOp struct {
 // NewPhysicalPlan is used to generate a physical plan. Its responsibilities include creating consolidation nodes, result nodes,
 // pushing down predicates, changing the ordering for nodes
 // nolint: unparam
-func NewPhysicalPlan(lp LogicalPlan, storage storage.Storage, now time.Time) (PhysicalPlan, error) {
+func NewPhysicalPlan(lp LogicalPlan, storage storage.Storage, params models.RequestParams) (PhysicalPlan, error) {
 	// generate a new physical plan after cloning the logical plan so that any changes here do not update the logical plan
 	cloned := lp.Clone()
 	p := PhysicalPlan{
 		steps:    cloned.Steps,
 		pipeline: cloned.Pipeline,
-		Now:      now,
+		TimeSpec: transform.TimeSpec{
+			Start: params.Start,
+			End:   params.End,
+			Now:   params.Now,
+			Step:  params.Step,
+		},
+	}
+
+	if _, ok := storage.(SubPlanExecutor); ok {
+		p = p.pushDown()
 	}
 
 	pl, err := p.createResultNode()
@@ -61,6 +72,85 @@ func NewPhysicalPlan(lp LogicalPlan, storage storage.Storage, now time.Time) (Ph
 	return pl, nil
 }
 
+// pushDown replaces the aggregations over a fetch, along with the temporal functions between them, with sub
+// plans executed by the storage so that only the aggregated series are sent back by each datacenter
+func (p PhysicalPlan) pushDown() PhysicalPlan {
+	for _, transformID := range p.pipeline {
+		step, ok := p.steps[transformID]
+		if !ok {
+			continue
+		}
+
+		subPlan, replaced, ok := p.subPlan(step)
+		if !ok {
+			continue
+		}
+
+		for _, ID := range replaced {
+			delete(p.steps, ID)
+		}
+
+		p.steps[transformID] = LogicalStep{
+			Transform: parser.Node{ID: transformID, Op: SubPlanOp{SubPlan: subPlan}},
+			Parents:   make([]parser.NodeID, 0),
+			Children:  step.Children,
+		}
+	}
+
+	pipeline := make([]parser.NodeID, 0, len(p.steps))
+	for _, transformID := range p.pipeline {
+		if _, ok := p.steps[transformID]; ok {
+			pipeline = append(pipeline, transformID)
+		}
+	}
+
+	p.pipeline = pipeline
+	return p
+}
+
+// subPlan returns the sub plan of an aggregation over temporal functions of a fetch along with the steps it
+// replaces, steps which also feed other steps are still needed by them so they are not pushed down
+func (p PhysicalPlan) subPlan(step LogicalStep) (SubPlan, []parser.NodeID, bool) {
+	if _, ok := step.Transform.Op.(functions.AggregationOp); !ok {
+		return SubPlan{}, nil, false
+	}
+
+	var (
+		transforms = []parser.Params{step.Transform.Op}
+		replaced   []parser.NodeID
+	)
+
+	for current := step; len(current.Parents) == 1; {
+		parent, ok := p.steps[current.Parents[0]]
+		if !ok || len(parent.Children) != 1 {
+			return SubPlan{}, nil, false
+		}
+
+		replaced = append(replaced, parent.ID())
+		switch op := parent.Transform.Op.(type) {
+		case functions.FetchOp:
+			// Transforms were collected from the aggregation up
+			for i, j := 0, len(transforms)-1; i < j; i, j = i+1, j-1 {
+				transforms[i], transforms[j] = transforms[j], transforms[i]
+			}
+
+			subPlan := SubPlan{Fetch: op, Transforms: transforms, TimeSpec: p.TimeSpec}
+			if err := subPlan.Validate(); err != nil {
+				return SubPlan{}, nil, false
+			}
+
+			return subPlan, replaced, true
+		case functions.TemporalOp:
+			transforms = append(transforms, op)
+			current = parent
+		default:
+			return SubPlan{}, nil, false
+		}
+	}
+
+	return SubPlan{}, nil, false
+}
+
 func (p PhysicalPlan) createResultNode() (PhysicalPlan, error) {
 	leaf, err := p.leafNode()
 	if err != nil {
@@ -21,11 +21,14 @@
 package plan
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -33,7 +36,7 @@ import (
 
 func TestResultNode(t *testing.T) {
 	fetchTransform := parser.NewTransformFromOperation(functions.FetchOp{}, 1)
-	countTransform := parser.NewTransformFromOperation(functions.CountOp{}, 2)
+	countTransform := parser.NewTransformFromOperation(functions.AggregationOp{OperatorType: functions.CountType}, 2)
 	transforms := parser.Nodes{fetchTransform, countTransform}
 	edges := parser.Edges{
 		parser.Edge{
@@ -44,10 +47,81 @@ func TestResultNode(t *testing.T) {
 
 	lp, err := NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := NewPhysicalPlan(lp, nil, time.Now())
+	p, err := NewPhysicalPlan(lp, nil, models.RequestParams{Now: time.Now()})
 	require.NoError(t, err)
 	node, err := p.leafNode()
 	require.NoError(t, err)
 	assert.Equal(t, node.ID(), countTransform.ID)
 	assert.Equal(t, p.ResultStep.Parent, countTransform.ID)
 }
+
+// subPlanStore is a storage executing sub plans
+type subPlanStore struct {
+	storage.Storage
+}
+
+func (s *subPlanStore) ExecuteSubPlan(context.Context, SubPlan, *storage.FetchOptions) (storage.BlockResult, error) {
+	return storage.BlockResult{}, nil
+}
+
+// chainedPlan creates a logical plan where every op feeds the next one
+func chainedPlan(t *testing.T, ops ...parser.Params) LogicalPlan {
+	transforms := make(parser.Nodes, len(ops))
+	edges := make(parser.Edges, 0, len(ops))
+	for i, op := range ops {
+		transforms[i] = parser.NewTransformFromOperation(op, i)
+		if i > 0 {
+			edges = append(edges, parser.Edge{ParentID: transforms[i-1].ID, ChildID: transforms[i].ID})
+		}
+	}
+
+	lp, err := NewLogicalPlan(transforms, edges)
+	require.NoError(t, err)
+	return lp
+}
+
+func TestPhysicalPlanPushesDownAggregations(t *testing.T) {
+	fetch := functions.FetchOp{Name: "up", Range: time.Minute}
+	rate := functions.TemporalOp{OperatorType: functions.RateType, Duration: time.Minute}
+	count := functions.AggregationOp{OperatorType: functions.CountType}
+	abs := functions.MathOp{OperatorType: functions.AbsType}
+	lp := chainedPlan(t, fetch, rate, count, abs)
+
+	now := time.Now()
+	params := models.RequestParams{Start: now.Add(-time.Hour), End: now, Now: now, Step: time.Minute}
+	p, err := NewPhysicalPlan(lp, &subPlanStore{}, params)
+	require.NoError(t, err)
+
+	// The fetch and rate are executed by the storage along with the count
+	assert.Equal(t, []parser.NodeID{"2", "3"}, p.pipeline)
+	step, ok := p.Step("2")
+	require.True(t, ok)
+	assert.Empty(t, step.Parents)
+	assert.Equal(t, []parser.NodeID{"3"}, step.Children)
+	assert.Equal(t, SubPlanOp{SubPlan: SubPlan{
+		Fetch:      fetch,
+		Transforms: []parser.Params{rate, count},
+		TimeSpec:   p.TimeSpec,
+	}}, step.Transform.Op)
+	assert.Equal(t, parser.NodeID("3"), p.ResultStep.Parent)
+
+	// Storages which cannot execute sub plans are sent the fetch
+	p, err = NewPhysicalPlan(lp, nil, params)
+	require.NoError(t, err)
+	assert.Equal(t, []parser.NodeID{"0", "1", "2", "3"}, p.pipeline)
+}
+
+func TestPhysicalPlanKeepsUncombinableAggregations(t *testing.T) {
+	fetch := functions.FetchOp{Name: "up"}
+	for _, lp := range []LogicalPlan{
+		chainedPlan(t, fetch, functions.AggregationOp{OperatorType: functions.AvgType}),
+		chainedPlan(t, fetch, functions.MathOp{OperatorType: functions.AbsType}, functions.AggregationOp{OperatorType: functions.SumType}),
+	} {
+		p, err := NewPhysicalPlan(lp, &subPlanStore{}, models.RequestParams{Now: time.Now()})
+		require.NoError(t, err)
+		assert.Len(t, p.pipeline, len(lp.Pipeline))
+		for _, step := range p.steps {
+			assert.NotEqual(t, SubPlanType, step.Transform.Op.OpType())
+		}
+	}
+}
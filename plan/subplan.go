@@ -0,0 +1,327 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+)
+
+// SubPlanType is the op type of a sub plan executed by the storage
+const SubPlanType = "subplan"
+
+// SubPlan is a fetch along with the transforms pushed down to it, the transforms are applied in order
+// so that a remote coordinator can execute them and return the result instead of every datapoint.
+// The physical plan pushes aggregations down into sub plans when the storage is a SubPlanExecutor.
+type SubPlan struct {
+	Fetch      functions.FetchOp
+	Transforms []parser.Params
+	TimeSpec   transform.TimeSpec
+}
+
+// combinableAggregations are the aggregations whose results on each datacenter can be aggregated again
+// into the result over every series, counts are combined with a sum. Aggregations such as avg or quantile
+// need every series at once and cannot be pushed down.
+var combinableAggregations = map[string]bool{
+	functions.SumType:     true,
+	functions.MinType:     true,
+	functions.MaxType:     true,
+	functions.CountType:   true,
+	functions.TopKType:    true,
+	functions.BottomKType: true,
+}
+
+// Validate checks that the transforms can be pushed down. Temporal functions only need the datapoints of
+// a single series, aggregations must be combinable and come last since their results have to be combined
+// with those of the other datacenters before anything else is applied.
+func (s SubPlan) Validate() error {
+	for i, op := range s.Transforms {
+		switch o := op.(type) {
+		case functions.TemporalOp:
+		case functions.AggregationOp:
+			if !combinableAggregations[o.OperatorType] {
+				return fmt.Errorf("aggregation %s cannot be combined across datacenters", o.OperatorType)
+			}
+
+			if i != len(s.Transforms)-1 {
+				return fmt.Errorf("aggregation %s must be the last transform pushed down", o.OperatorType)
+			}
+		default:
+			return fmt.Errorf("transform %s cannot be pushed down", op.OpType())
+		}
+	}
+
+	return nil
+}
+
+// SubPlanExecutor executes sub plans, remote storages execute them on the remote coordinator and storages
+// spanning several datacenters combine the results of each of them
+type SubPlanExecutor interface {
+	ExecuteSubPlan(ctx context.Context, subPlan SubPlan, options *storage.FetchOptions) (storage.BlockResult, error)
+}
+
+// LogicalPlan creates a plan where the fetch feeds the first transform and every transform feeds the next one
+func (s SubPlan) LogicalPlan() (LogicalPlan, error) {
+	transforms := make(parser.Nodes, 0, len(s.Transforms)+1)
+	edges := make(parser.Edges, 0, len(s.Transforms))
+	transforms = append(transforms, parser.NewTransformFromOperation(s.Fetch, 0))
+	for i, op := range s.Transforms {
+		node := parser.NewTransformFromOperation(op, i+1)
+		edges = append(edges, parser.Edge{
+			ParentID: transforms[i].ID,
+			ChildID:  node.ID,
+		})
+		transforms = append(transforms, node)
+	}
+
+	return NewLogicalPlan(transforms, edges)
+}
+
+// RequestParams are the params to create the physical plan of the sub plan with
+func (s SubPlan) RequestParams() models.RequestParams {
+	return models.RequestParams{
+		Start: s.TimeSpec.Start,
+		End:   s.TimeSpec.End,
+		Now:   s.TimeSpec.Now,
+		Step:  s.TimeSpec.Step,
+	}
+}
+
+// FetchQuery is the query fetching the datapoints the sub plan reads, going back a range from the first step
+// for range selectors
+func (s SubPlan) FetchQuery() *storage.FetchQuery {
+	return &storage.FetchQuery{
+		Start:       s.TimeSpec.Start.Add(-1 * (s.Fetch.Offset + s.Fetch.Range)),
+		End:         s.TimeSpec.End.Add(-1 * s.Fetch.Offset),
+		TagMatchers: s.Fetch.Matchers,
+		Interval:    s.TimeSpec.Step,
+	}
+}
+
+// combineOp is the aggregation applied to the results of the sub plan on every datacenter, counts are summed
+// and the other combinable aggregations are applied again
+func (s SubPlan) combineOp() (functions.AggregationOp, bool) {
+	if len(s.Transforms) == 0 {
+		return functions.AggregationOp{}, false
+	}
+
+	op, ok := s.Transforms[len(s.Transforms)-1].(functions.AggregationOp)
+	if !ok {
+		return functions.AggregationOp{}, false
+	}
+
+	if op.OperatorType == functions.CountType {
+		op.OperatorType = functions.SumType
+	}
+
+	return op, true
+}
+
+// Combine aggregates the results of the sub plan on several datacenters into its result over every series,
+// blocks sharing bounds are combined together. Without an aggregation the series of the datacenters are
+// returned as they are.
+func (s SubPlan) Combine(blocks []storage.Block) ([]storage.Block, error) {
+	op, ok := s.combineOp()
+	if !ok {
+		return blocks, nil
+	}
+
+	var groups [][]storage.Block
+	for _, block := range blocks {
+		groups = groupByBounds(groups, block)
+	}
+
+	for i := 0; i < len(groups); i++ {
+		for j := i + 1; j < len(groups); j++ {
+			if overlaps(groups[i][0].Meta().Bounds, groups[j][0].Meta().Bounds) {
+				closeBlocks(blocks)
+				return nil, errors.ErrBlockBoundsMismatch
+			}
+		}
+	}
+
+	combined := make([]storage.Block, 0, len(groups))
+	sink := &blockSink{}
+	controller := &transform.Controller{ID: SubPlanType}
+	controller.AddTransform(sink)
+	node := op.Node(controller)
+	for i, group := range groups {
+		input, err := concatBlocks(group)
+		if err == nil {
+			if err = node.Process(SubPlanType, input); err != nil {
+				input.Close()
+			}
+		}
+
+		if err != nil {
+			closeBlocks(combined)
+			for _, remaining := range groups[i+1:] {
+				closeBlocks(remaining)
+			}
+
+			return nil, err
+		}
+
+		combined = append(combined, sink.blocks...)
+		sink.blocks = sink.blocks[:0]
+	}
+
+	return combined, nil
+}
+
+func groupByBounds(groups [][]storage.Block, block storage.Block) [][]storage.Block {
+	bounds := block.Meta().Bounds
+	for i, group := range groups {
+		if group[0].Meta().Bounds.Equals(bounds) {
+			groups[i] = append(group, block)
+			return groups
+		}
+	}
+
+	return append(groups, []storage.Block{block})
+}
+
+// overlaps is true when the bounds share a step time, both start and end are inclusive
+func overlaps(a, b storage.Bounds) bool {
+	return !a.Start.After(b.End) && !b.Start.After(a.End)
+}
+
+// concatBlocks creates a single block out of the series of blocks sharing bounds, the blocks are closed
+func concatBlocks(blocks []storage.Block) (storage.Block, error) {
+	defer closeBlocks(blocks)
+
+	var (
+		seriesMeta []storage.SeriesMeta
+		values     [][]float64
+	)
+	for _, block := range blocks {
+		common := block.Meta().Tags
+		for _, meta := range block.SeriesMeta() {
+			tags := make(models.Tags, len(common)+len(meta.Tags))
+			for k, v := range common {
+				tags[k] = v
+			}
+
+			for k, v := range meta.Tags {
+				tags[k] = v
+			}
+
+			seriesMeta = append(seriesMeta, storage.SeriesMeta{Tags: tags, Name: tags.ID()})
+		}
+
+		iter := block.SeriesIter()
+		for iter.Next() {
+			series := iter.Current()
+			vals := make([]float64, series.Len())
+			for i := range vals {
+				vals[i] = series.ValueAt(i)
+			}
+
+			values = append(values, vals)
+		}
+	}
+
+	meta := storage.BlockMetadata{Bounds: blocks[0].Meta().Bounds}
+	return storage.NewSeriesBlock(meta, seriesMeta, values)
+}
+
+// closeBlocks releases blocks on the error path, where the error of the execution is returned rather than
+// the errors closing them
+func closeBlocks(blocks []storage.Block) {
+	for _, block := range blocks {
+		block.Close()
+	}
+}
+
+// blockSink collects the blocks of a controller
+type blockSink struct {
+	blocks []storage.Block
+}
+
+func (s *blockSink) Process(_ parser.NodeID, block storage.Block) error {
+	s.blocks = append(s.blocks, block)
+	return nil
+}
+
+// SubPlanOp executes a sub plan on the storage in place of the steps it was created from
+type SubPlanOp struct {
+	SubPlan SubPlan
+}
+
+// OpType for the operator
+func (o SubPlanOp) OpType() string {
+	return SubPlanType
+}
+
+// String representation
+func (o SubPlanOp) String() string {
+	return fmt.Sprintf("type: %s, fetch: %s, transforms: %v", o.OpType(), o.SubPlan.Fetch, o.SubPlan.Transforms)
+}
+
+// Node creates an execution node
+func (o SubPlanOp) Node(controller *transform.Controller, storage storage.Storage, options transform.Options) parser.Source {
+	return &SubPlanNode{op: o, controller: controller, storage: storage, killChan: options.KillChan}
+}
+
+// SubPlanNode is the execution node
+type SubPlanNode struct {
+	op         SubPlanOp
+	controller *transform.Controller
+	storage    storage.Storage
+	killChan   chan struct{}
+	warnings   []storage.Warning
+}
+
+// Execute runs the sub plan on the storage and processes the resulting blocks
+func (n *SubPlanNode) Execute(ctx context.Context) error {
+	executor, ok := n.storage.(SubPlanExecutor)
+	if !ok {
+		return errors.ErrNotImplemented
+	}
+
+	result, err := executor.ExecuteSubPlan(ctx, n.op.SubPlan, &storage.FetchOptions{KillChan: n.killChan})
+	if err != nil {
+		return err
+	}
+
+	n.warnings = result.Warnings
+	for i, block := range result.Blocks {
+		if err := n.controller.Process(block); err != nil {
+			// Fail on first error, the blocks not processed yet are no longer needed
+			closeBlocks(result.Blocks[i+1:])
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Warnings returns the stores left out of the result of the sub plan
+func (n *SubPlanNode) Warnings() []storage.Warning {
+	return n.warnings
+}
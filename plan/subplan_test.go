@@ -0,0 +1,185 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package plan
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubPlanLogicalPlanChainsTransforms(t *testing.T) {
+	now := time.Now()
+	subPlan := SubPlan{
+		Fetch: functions.FetchOp{Name: "up"},
+		Transforms: []parser.Params{
+			functions.TemporalOp{OperatorType: functions.RateType, Duration: time.Minute},
+			functions.AggregationOp{OperatorType: functions.SumType},
+		},
+		TimeSpec: transform.TimeSpec{Start: now.Add(-time.Hour), End: now, Now: now, Step: time.Minute},
+	}
+
+	lp, err := subPlan.LogicalPlan()
+	require.NoError(t, err)
+	require.Len(t, lp.Pipeline, 3)
+
+	fetch, rate, sum := lp.Steps[lp.Pipeline[0]], lp.Steps[lp.Pipeline[1]], lp.Steps[lp.Pipeline[2]]
+	assert.Equal(t, functions.FetchType, fetch.Transform.Op.OpType())
+	assert.Equal(t, functions.RateType, rate.Transform.Op.OpType())
+	assert.Equal(t, functions.SumType, sum.Transform.Op.OpType())
+	assert.Empty(t, fetch.Parents)
+	assert.Equal(t, []parser.NodeID{rate.ID()}, fetch.Children)
+	assert.Equal(t, []parser.NodeID{fetch.ID()}, rate.Parents)
+	assert.Equal(t, []parser.NodeID{sum.ID()}, rate.Children)
+	assert.Equal(t, []parser.NodeID{rate.ID()}, sum.Parents)
+	assert.Empty(t, sum.Children)
+
+	params := subPlan.RequestParams()
+	assert.Equal(t, subPlan.TimeSpec.Start, params.Start)
+	assert.Equal(t, subPlan.TimeSpec.End, params.End)
+	assert.Equal(t, subPlan.TimeSpec.Now, params.Now)
+	assert.Equal(t, subPlan.TimeSpec.Step, params.Step)
+}
+
+func TestSubPlanLogicalPlanFetchOnly(t *testing.T) {
+	lp, err := SubPlan{Fetch: functions.FetchOp{Name: "up"}}.LogicalPlan()
+	require.NoError(t, err)
+	require.Len(t, lp.Pipeline, 1)
+	assert.Empty(t, lp.Steps[lp.Pipeline[0]].Children)
+}
+
+func TestSubPlanValidate(t *testing.T) {
+	rate := functions.TemporalOp{OperatorType: functions.RateType, Duration: time.Minute}
+	tests := []struct {
+		name       string
+		transforms []parser.Params
+		valid      bool
+	}{
+		{"fetch only", nil, true},
+		{"temporal", []parser.Params{rate}, true},
+		{"combinable aggregation", []parser.Params{rate, functions.AggregationOp{OperatorType: functions.SumType}}, true},
+		{"count", []parser.Params{functions.AggregationOp{OperatorType: functions.CountType}}, true},
+		{"avg", []parser.Params{functions.AggregationOp{OperatorType: functions.AvgType}}, false},
+		{"quantile", []parser.Params{functions.AggregationOp{OperatorType: functions.QuantileType}}, false},
+		{"aggregation before temporal", []parser.Params{functions.AggregationOp{OperatorType: functions.SumType}, rate}, false},
+		{"other transform", []parser.Params{functions.MathOp{OperatorType: functions.AbsType}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SubPlan{Fetch: functions.FetchOp{Name: "up"}, Transforms: tt.transforms}.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func seriesBlock(t *testing.T, bounds storage.Bounds, tags []models.Tags, values ...[]float64) storage.Block {
+	seriesMeta := make([]storage.SeriesMeta, len(tags))
+	for i, tag := range tags {
+		seriesMeta[i] = storage.SeriesMeta{Tags: tag, Name: tag.ID()}
+	}
+
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, values)
+	require.NoError(t, err)
+	return block
+}
+
+func blockValues(block storage.Block) map[string][]float64 {
+	values := make(map[string][]float64)
+	seriesMeta := block.SeriesMeta()
+	iter := block.SeriesIter()
+	for i := 0; iter.Next(); i++ {
+		series := iter.Current()
+		vals := make([]float64, series.Len())
+		for j := range vals {
+			vals[j] = series.ValueAt(j)
+		}
+
+		values[seriesMeta[i].Tags.ID()] = vals
+	}
+
+	return values
+}
+
+func TestSubPlanCombineSumsCounts(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	bounds := storage.Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	api, web := models.Tags{"job": "api"}, models.Tags{"job": "web"}
+	subPlan := SubPlan{
+		Fetch: functions.FetchOp{Name: "up"},
+		Transforms: []parser.Params{
+			functions.AggregationOp{OperatorType: functions.CountType, Params: functions.AggregationParams{MatchingTags: []string{"job"}}},
+		},
+	}
+
+	combined, err := subPlan.Combine([]storage.Block{
+		seriesBlock(t, bounds, []models.Tags{api, web}, []float64{2, 1}, []float64{1, math.NaN()}),
+		seriesBlock(t, bounds, []models.Tags{api}, []float64{3, math.NaN()}),
+	})
+	require.NoError(t, err)
+	require.Len(t, combined, 1)
+
+	// The counts of every datacenter are added up rather than counted again
+	values := blockValues(combined[0])
+	assert.Equal(t, []float64{5, 1}, values[api.ID()])
+	require.Len(t, values[web.ID()], 2)
+	assert.Equal(t, 1.0, values[web.ID()][0])
+	assert.True(t, math.IsNaN(values[web.ID()][1]))
+}
+
+func TestSubPlanCombineWithoutAggregation(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	bounds := storage.Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	blocks := []storage.Block{seriesBlock(t, bounds, []models.Tags{{"job": "api"}}, []float64{1, 2})}
+
+	combined, err := SubPlan{Fetch: functions.FetchOp{Name: "up"}}.Combine(blocks)
+	require.NoError(t, err)
+	assert.Equal(t, blocks, combined)
+}
+
+func TestSubPlanCombineOverlappingBounds(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	tags := []models.Tags{{"job": "api"}}
+	subPlan := SubPlan{
+		Fetch:      functions.FetchOp{Name: "up"},
+		Transforms: []parser.Params{functions.AggregationOp{OperatorType: functions.SumType}},
+	}
+
+	_, err := subPlan.Combine([]storage.Block{
+		seriesBlock(t, storage.Bounds{Start: now, End: now.Add(2 * time.Minute), StepSize: time.Minute}, tags, []float64{1, 2, 3}),
+		seriesBlock(t, storage.Bounds{Start: now.Add(time.Minute), End: now.Add(3 * time.Minute), StepSize: time.Minute}, tags, []float64{1, 2, 3}),
+	})
+	assert.Equal(t, errors.ErrBlockBoundsMismatch, err)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package filter
+
+import (
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/m3db/m3x/clock"
+)
+
+// QueryMatcher matches queries by their attributes, every attribute which is set must match and
+// the zero value matches every query
+type QueryMatcher struct {
+	// Tag matches queries with an equality matcher on the tag, or writes with the tag
+	Tag string
+	// Value restricts the tag to a single value
+	Value string
+	// OlderThan matches fetches ending more than the age ago
+	OlderThan time.Duration
+	// NewerThan matches fetches starting less than the age ago
+	NewerThan time.Duration
+}
+
+// StoreMatcher matches stores by type or name, the zero value matches every store
+type StoreMatcher struct {
+	// Types matches stores of any of the types
+	Types []storage.Type
+	// Names matches named stores with any of the names
+	Names []string
+}
+
+// Rule restricts the queries it matches to the stores it matches
+type Rule struct {
+	Query  QueryMatcher
+	Stores StoreMatcher
+}
+
+// NewRules creates a filter from rules, the first rule matching a query decides which stores serve it
+// and queries matching no rule go through the default filter
+func NewRules(rules []Rule, defaultFilter Storage, nowFn clock.NowFn) Storage {
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+
+	return func(query storage.Query, store storage.Storage) bool {
+		now := nowFn()
+		for _, rule := range rules {
+			if rule.Query.matches(query, now) {
+				return rule.Stores.matches(store)
+			}
+		}
+
+		return defaultFilter(query, store)
+	}
+}
+
+func (m QueryMatcher) matches(query storage.Query, now time.Time) bool {
+	switch q := query.(type) {
+	case *storage.FetchQuery:
+		if m.Tag != "" && !matchesTag(q.TagMatchers, m.Tag, m.Value) {
+			return false
+		}
+
+		if m.OlderThan > 0 && !q.End.Before(now.Add(-m.OlderThan)) {
+			return false
+		}
+
+		if m.NewerThan > 0 && !q.Start.After(now.Add(-m.NewerThan)) {
+			return false
+		}
+
+		return true
+
+	case *storage.WriteQuery:
+		// Writes are always current, they never match age conditions
+		if m.OlderThan > 0 || m.NewerThan > 0 {
+			return false
+		}
+
+		if m.Tag == "" {
+			return true
+		}
+
+		value, ok := q.Tags[m.Tag]
+		return ok && (m.Value == "" || value == m.Value)
+
+	default:
+		return false
+	}
+}
+
+func matchesTag(matchers models.Matchers, tag, value string) bool {
+	for _, matcher := range matchers {
+		if matcher.Type != models.MatchEqual || matcher.Name != tag {
+			continue
+		}
+
+		if value == "" || matcher.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m StoreMatcher) matches(store storage.Storage) bool {
+	if len(m.Types) > 0 && !matchesType(m.Types, store.Type()) {
+		return false
+	}
+
+	if len(m.Names) == 0 {
+		return true
+	}
+
+	named, ok := store.(storage.Named)
+	if !ok {
+		return false
+	}
+
+	for _, name := range m.Names {
+		if named.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesType(types []storage.Type, storeType storage.Type) bool {
+	for _, t := range types {
+		if t == storeType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now     = time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+	eu      = mock.NewMockStorageWithName(storage.TypeRemoteDC, "eu")
+	archive = mock.NewMockStorageWithName(storage.TypeRemoteDC, "archive")
+)
+
+func nowFn() time.Time {
+	return now
+}
+
+func fetchQuery(region string, start, end time.Duration) *storage.FetchQuery {
+	return &storage.FetchQuery{
+		TagMatchers: models.Matchers{{Type: models.MatchEqual, Name: "region", Value: region}},
+		Start:       now.Add(-start),
+		End:         now.Add(-end),
+	}
+}
+
+func TestRules(t *testing.T) {
+	f := NewRules([]Rule{
+		{Query: QueryMatcher{Tag: "region", Value: "eu"}, Stores: StoreMatcher{Names: []string{"eu"}}},
+		{Query: QueryMatcher{OlderThan: 30 * 24 * time.Hour}, Stores: StoreMatcher{Names: []string{"archive"}}},
+		{Query: QueryMatcher{NewerThan: time.Hour}, Stores: StoreMatcher{Types: []storage.Type{storage.TypeLocalDC}}},
+	}, AllowAll, nowFn)
+
+	// Tagged reads only go to the named store
+	euQuery := fetchQuery("eu", time.Hour, 0)
+	assert.True(t, f(euQuery, eu))
+	assert.False(t, f(euQuery, archive))
+	assert.False(t, f(euQuery, local))
+
+	// Old reads only go to the archive
+	oldQuery := fetchQuery("us", 60*24*time.Hour, 40*24*time.Hour)
+	assert.True(t, f(oldQuery, archive))
+	assert.False(t, f(oldQuery, eu))
+	assert.False(t, f(oldQuery, local))
+
+	// Recent reads only go to local stores
+	recentQuery := fetchQuery("us", time.Minute, 0)
+	assert.True(t, f(recentQuery, local))
+	assert.False(t, f(recentQuery, remote))
+
+	// Anything else goes through the default filter
+	otherQuery := fetchQuery("us", 2*time.Hour, time.Hour)
+	assert.True(t, f(otherQuery, local))
+	assert.True(t, f(otherQuery, eu))
+	assert.True(t, f(otherQuery, archive))
+}
+
+func TestRulesWrites(t *testing.T) {
+	f := NewRules([]Rule{
+		{Query: QueryMatcher{OlderThan: time.Hour}, Stores: StoreMatcher{Names: []string{"archive"}}},
+		{Query: QueryMatcher{Tag: "region"}, Stores: StoreMatcher{Types: []storage.Type{storage.TypeRemoteDC}}},
+	}, LocalOnly, nowFn)
+
+	tagged := &storage.WriteQuery{Tags: models.Tags{"region": "eu"}}
+	assert.True(t, f(tagged, eu))
+	assert.True(t, f(tagged, archive))
+	assert.False(t, f(tagged, local))
+
+	untagged := &storage.WriteQuery{Tags: models.Tags{"foo": "bar"}}
+	assert.True(t, f(untagged, local))
+	assert.False(t, f(untagged, eu))
+}
+
+func TestStoreMatcherUnnamed(t *testing.T) {
+	m := StoreMatcher{Names: []string{"eu"}}
+	assert.True(t, m.matches(eu))
+	assert.False(t, m.matches(remote))
+	assert.True(t, StoreMatcher{}.matches(remote))
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/tsdb"
+
+	"github.com/m3db/m3metrics/policy"
+	"github.com/m3db/m3x/clock"
+	xtime "github.com/m3db/m3x/time"
+)
+
+var errNoStoragePolicies = errors.New("no storage policies to resolve")
+
+// Strategy decides which storage policy serves each part of a query
+type Strategy int
+
+const (
+	// PreferFinest serves each part of a query from the finest resolution retaining it
+	PreferFinest Strategy = iota
+	// PreferCoarsestForLongRanges serves queries spanning at least the long range from the coarsest
+	// resolution retaining them, shorter queries are served as with PreferFinest
+	PreferCoarsestForLongRanges
+)
+
+var validStrategies = []Strategy{PreferFinest, PreferCoarsestForLongRanges}
+
+func (s Strategy) String() string {
+	switch s {
+	case PreferFinest:
+		return "prefer_finest"
+	case PreferCoarsestForLongRanges:
+		return "prefer_coarsest_for_long_ranges"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a strategy from its name, the finest resolution is preferred by default
+func (s *Strategy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		*s = PreferFinest
+		return nil
+	}
+
+	for _, valid := range validStrategies {
+		if str == valid.String() {
+			*s = valid
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid resolver strategy %s, valid strategies are: %v", str, validStrategies)
+}
+
+// RangeResolverOptions are the options of a range resolver, the zero value prefers the finest resolution
+type RangeResolverOptions struct {
+	// Strategy picks the storage policy of each part of a query
+	Strategy Strategy
+	// LongRange is the shortest query served from the coarsest resolution with PreferCoarsestForLongRanges
+	LongRange time.Duration
+	// NowFn is the clock retention is measured from, defaults to time.Now
+	NowFn clock.NowFn
+}
+
+type rangeResolver struct {
+	// policies are ordered from the finest to the coarsest resolution
+	policies []policy.StoragePolicy
+	// longest is the policy retaining data for the longest time
+	longest policy.StoragePolicy
+	opts    RangeResolverOptions
+}
+
+// NewRangeResolver creates a policy resolver which splits the query range across storage policies by
+// their retention, the strategy decides which policy serves the parts retained by several of them.
+func NewRangeResolver(policies []policy.StoragePolicy, opts RangeResolverOptions) PolicyResolver {
+	sorted := make([]policy.StoragePolicy, len(policies))
+	copy(sorted, policies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		wi, wj := sorted[i].Resolution().Window, sorted[j].Resolution().Window
+		if wi != wj {
+			return wi < wj
+		}
+
+		return retention(sorted[i]) > retention(sorted[j])
+	})
+
+	if opts.NowFn == nil {
+		opts.NowFn = time.Now
+	}
+
+	r := &rangeResolver{policies: sorted, opts: opts}
+	if len(sorted) > 0 {
+		r.longest = sorted[0]
+	}
+
+	for _, sp := range sorted {
+		if retention(sp) > retention(r.longest) {
+			r.longest = sp
+		}
+	}
+
+	return r
+}
+
+// retention is how long a policy retains data for, a zero retention retains data forever
+func retention(sp policy.StoragePolicy) time.Duration {
+	if d := sp.Retention().Duration(); d > 0 {
+		return d
+	}
+
+	return math.MaxInt64
+}
+
+// earliestRetained is the earliest time a policy retains data for, the zero time when it retains data forever
+func earliestRetained(now time.Time, sp policy.StoragePolicy) time.Time {
+	d := sp.Retention().Duration()
+	if d <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(-d)
+}
+
+// candidates returns the policies in the order they are given parts of the query
+func (r *rangeResolver) candidates(startTime, endTime time.Time) []policy.StoragePolicy {
+	if r.opts.Strategy != PreferCoarsestForLongRanges || endTime.Sub(startTime) < r.opts.LongRange {
+		return r.policies
+	}
+
+	coarsest := make([]policy.StoragePolicy, len(r.policies))
+	for i, sp := range r.policies {
+		coarsest[len(r.policies)-1-i] = sp
+	}
+
+	return coarsest
+}
+
+func (r *rangeResolver) Resolve(
+	// Context needed here to satisfy PolicyResolver interface
+	ctx context.Context, // nolint: unparam
+	tagMatchers models.Matchers,
+	startTime, endTime time.Time,
+) ([]tsdb.FetchRequest, error) {
+	if len(r.policies) == 0 {
+		return nil, errNoStoragePolicies
+	}
+
+	// Walk back from the end of the query, each policy takes the part it retains which is not
+	// already covered by a preceding candidate
+	now := r.opts.NowFn()
+	ranges := make(tsdb.FetchRanges, 0, len(r.policies))
+	cursor := endTime
+	for _, sp := range r.candidates(startTime, endTime) {
+		if !cursor.After(startTime) {
+			break
+		}
+
+		earliest := earliestRetained(now, sp)
+		if !earliest.Before(cursor) {
+			continue
+		}
+
+		rangeStart := startTime
+		if earliest.After(startTime) {
+			rangeStart = earliest
+		}
+
+		ranges = append(ranges, tsdb.FetchRange{
+			Range:         xtime.Range{Start: rangeStart, End: cursor},
+			StoragePolicy: sp,
+		})
+		cursor = rangeStart
+	}
+
+	// Nothing retains the oldest part of the query, it goes to the longest retained policy so that
+	// the ranges still cover the query
+	if cursor.After(startTime) {
+		last := len(ranges) - 1
+		if last >= 0 && ranges[last].StoragePolicy == r.longest {
+			ranges[last].Start = startTime
+		} else {
+			ranges = append(ranges, tsdb.FetchRange{
+				Range:         xtime.Range{Start: startTime, End: cursor},
+				StoragePolicy: r.longest,
+			})
+		}
+	}
+
+	// Ranges are returned in time order
+	for i, j := 0, len(ranges)-1; i < j; i, j = i+1, j-1 {
+		ranges[i], ranges[j] = ranges[j], ranges[i]
+	}
+
+	// The policies only depend on time so a single request covers every series matched
+	return []tsdb.FetchRequest{{Ranges: ranges}}, nil
+}
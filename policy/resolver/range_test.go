@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/tsdb"
+
+	"github.com/m3db/m3metrics/policy"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+var (
+	now = time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	raw    = policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*day)
+	minute = policy.NewStoragePolicy(time.Minute, xtime.Second, 40*day)
+	coarse = policy.NewStoragePolicy(10*time.Minute, xtime.Second, 365*day)
+)
+
+func nowFn() time.Time {
+	return now
+}
+
+func fetchRange(start, end time.Duration, sp policy.StoragePolicy) tsdb.FetchRange {
+	return tsdb.FetchRange{
+		Range:         xtime.Range{Start: now.Add(start), End: now.Add(end)},
+		StoragePolicy: sp,
+	}
+}
+
+func TestRangeResolver(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       RangeResolverOptions
+		start, end time.Duration
+		expected   tsdb.FetchRanges
+	}{
+		{
+			name:     "recent",
+			start:    -time.Hour,
+			expected: tsdb.FetchRanges{fetchRange(-time.Hour, 0, raw)},
+		},
+		{
+			name:     "ends in the future",
+			start:    -time.Hour,
+			end:      time.Hour,
+			expected: tsdb.FetchRanges{fetchRange(-time.Hour, time.Hour, raw)},
+		},
+		{
+			name:  "beyond raw retention",
+			start: -5 * day,
+			expected: tsdb.FetchRanges{
+				fetchRange(-5*day, -2*day, minute),
+				fetchRange(-2*day, 0, raw),
+			},
+		},
+		{
+			name:     "only retained by coarse",
+			start:    -100 * day,
+			end:      -50 * day,
+			expected: tsdb.FetchRanges{fetchRange(-100*day, -50*day, coarse)},
+		},
+		{
+			name:  "beyond every retention",
+			start: -730 * day,
+			expected: tsdb.FetchRanges{
+				fetchRange(-730*day, -40*day, coarse),
+				fetchRange(-40*day, -2*day, minute),
+				fetchRange(-2*day, 0, raw),
+			},
+		},
+		{
+			name:     "coarsest for long range",
+			opts:     RangeResolverOptions{Strategy: PreferCoarsestForLongRanges, LongRange: 7 * day},
+			start:    -30 * day,
+			expected: tsdb.FetchRanges{fetchRange(-30*day, 0, coarse)},
+		},
+		{
+			name:     "finest for short range",
+			opts:     RangeResolverOptions{Strategy: PreferCoarsestForLongRanges, LongRange: 7 * day},
+			start:    -day,
+			expected: tsdb.FetchRanges{fetchRange(-day, 0, raw)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.NowFn = nowFn
+			r := NewRangeResolver([]policy.StoragePolicy{coarse, raw, minute}, opts)
+			requests, err := r.Resolve(context.TODO(), nil, now.Add(tt.start), now.Add(tt.end))
+			require.NoError(t, err)
+			require.Len(t, requests, 1)
+			assert.Equal(t, tt.expected, requests[0].Ranges)
+		})
+	}
+}
+
+func TestRangeResolverLongestRetentionFallback(t *testing.T) {
+	// The coarse policy retains less than the finer one, the uncovered part still goes to the longest retention
+	short := policy.NewStoragePolicy(10*time.Minute, xtime.Second, day)
+	r := NewRangeResolver([]policy.StoragePolicy{raw, short}, RangeResolverOptions{NowFn: nowFn})
+	requests, err := r.Resolve(context.TODO(), nil, now.Add(-5*day), now)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, tsdb.FetchRanges{fetchRange(-5*day, 0, raw)}, requests[0].Ranges)
+}
+
+func TestRangeResolverUnboundedRetention(t *testing.T) {
+	// A zero retention retains data forever
+	unbounded := policy.NewStoragePolicy(time.Minute, xtime.Millisecond, 0)
+	r := NewRangeResolver([]policy.StoragePolicy{unbounded}, RangeResolverOptions{NowFn: nowFn})
+	requests, err := r.Resolve(context.TODO(), nil, now.Add(-5*day), now.Add(-day))
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, tsdb.FetchRanges{fetchRange(-5*day, -day, unbounded)}, requests[0].Ranges)
+
+	// It is preferred to the finer policy for the part the finer one does not retain
+	r = NewRangeResolver([]policy.StoragePolicy{raw, unbounded}, RangeResolverOptions{NowFn: nowFn})
+	requests, err = r.Resolve(context.TODO(), nil, now.Add(-5*day), now)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, tsdb.FetchRanges{
+		fetchRange(-5*day, -2*day, unbounded),
+		fetchRange(-2*day, 0, raw),
+	}, requests[0].Ranges)
+}
+
+func TestRangeResolverNoPolicies(t *testing.T) {
+	r := NewRangeResolver(nil, RangeResolverOptions{NowFn: nowFn})
+	_, err := r.Resolve(context.TODO(), nil, now.Add(-time.Hour), now)
+	assert.Equal(t, errNoStoragePolicies, err)
+}
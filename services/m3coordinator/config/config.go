@@ -20,9 +20,26 @@
 
 package config
 
-import "github.com/m3db/m3db/client"
+import (
+	"time"
+
+	"github.com/m3db/m3db/client"
+)
 
 // Configuration is the configuration for an instance of m3coordinator.
 type Configuration struct {
 	M3DBClientCfg client.Configuration `yaml:"client"`
+
+	// Storages are the stores queries fan out to.
+	Storages StoragesConfiguration `yaml:"storages"`
+
+	// RemoteRead is the configuration of the Prometheus remote read endpoint.
+	RemoteRead RemoteReadConfiguration `yaml:"remoteRead"`
+}
+
+// RemoteReadConfiguration is the configuration of the Prometheus remote read endpoint.
+type RemoteReadConfiguration struct {
+	// LookbackDuration must match the query.lookback-delta of the Prometheus servers reading from the
+	// coordinator so that downsampled series hold the datapoints Prometheus selects, defaults to 5m.
+	LookbackDuration time.Duration `yaml:"lookbackDuration"`
 }
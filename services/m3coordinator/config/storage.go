@@ -0,0 +1,366 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/m3db/m3coordinator/policy/filter"
+	"github.com/m3db/m3coordinator/policy/resolver"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/fanout"
+	"github.com/m3db/m3coordinator/storage/local"
+	"github.com/m3db/m3coordinator/storage/remote"
+	tsdbRemote "github.com/m3db/m3coordinator/tsdb/remote"
+
+	"github.com/m3db/m3db/client"
+	"github.com/m3db/m3metrics/policy"
+	xtime "github.com/m3db/m3x/time"
+)
+
+const (
+	defaultNamespace  = "metrics"
+	defaultResolution = time.Minute
+)
+
+var storageFilters = map[string]filter.Storage{
+	"local_only": filter.LocalOnly,
+	"allow_all":  filter.AllowAll,
+	"allow_none": filter.AllowNone,
+}
+
+var storageTypes = map[string]storage.Type{
+	"local":  storage.TypeLocalDC,
+	"remote": storage.TypeRemoteDC,
+	"multi":  storage.TypeMultiDC,
+}
+
+// StoragesConfiguration is the configuration of the stores queries fan out to.
+type StoragesConfiguration struct {
+	// Local is the local M3DB storage.
+	Local LocalConfiguration `yaml:"local"`
+
+	// Remotes are the remote coordinators reached over gRPC.
+	Remotes []RemoteConfiguration `yaml:"remotes"`
+
+	// Filters decide which stores serve reads and writes.
+	Filters FiltersConfiguration `yaml:"filters"`
+
+	// Fanout is the configuration of reads across the stores.
+	Fanout FanoutConfiguration `yaml:"fanout"`
+}
+
+// FanoutConfiguration is the configuration of reads across the stores.
+type FanoutConfiguration struct {
+	// PartialResults returns the results of the stores which succeeded along with warnings for
+	// the ones which failed, instead of failing the read.
+	PartialResults bool `yaml:"partialResults"`
+
+	// StoreTimeout bounds every store read with partial results so that slow stores are left out.
+	StoreTimeout time.Duration `yaml:"storeTimeout"`
+
+	// ConflictPolicy decides the values kept when several stores return the same series, defaults
+	// to prefer_local.
+	ConflictPolicy fanout.ConflictPolicy `yaml:"conflictPolicy"`
+
+	// PushDownAggregations executes aggregations on every datacenter and combines their results, only
+	// valid when datacenters hold distinct series since replicas would be aggregated once per datacenter.
+	PushDownAggregations bool `yaml:"pushDownAggregations"`
+}
+
+// Options creates the fanout options.
+func (c FanoutConfiguration) Options() fanout.Options {
+	return fanout.Options{
+		PartialResults:       c.PartialResults,
+		StoreTimeout:         c.StoreTimeout,
+		ConflictPolicy:       c.ConflictPolicy,
+		PushDownAggregations: c.PushDownAggregations,
+	}
+}
+
+// LocalConfiguration is the configuration of the local M3DB storage.
+type LocalConfiguration struct {
+	// Namespaces are the namespaces read from, defaults to a single unaggregated metrics namespace.
+	Namespaces []NamespaceConfiguration `yaml:"namespaces"`
+
+	// Resolver picks the namespace serving each part of a query.
+	Resolver ResolverConfiguration `yaml:"resolver"`
+}
+
+// NamespaceConfiguration is the configuration of a single M3DB namespace.
+type NamespaceConfiguration struct {
+	// Name is the name of the namespace.
+	Name string `yaml:"name" validate:"nonzero"`
+
+	// Resolution is the resolution of the datapoints in the namespace.
+	Resolution time.Duration `yaml:"resolution" validate:"nonzero"`
+
+	// Retention is how long the namespace keeps datapoints, datapoints are kept forever when unset.
+	Retention time.Duration `yaml:"retention"`
+
+	// Type is whether the namespace is aggregated or unaggregated, defaults to unaggregated.
+	Type local.NamespaceType `yaml:"type"`
+}
+
+// ResolverConfiguration is the configuration of the namespace resolver.
+type ResolverConfiguration struct {
+	// Strategy is the resolver strategy, defaults to prefer_finest.
+	Strategy resolver.Strategy `yaml:"strategy"`
+
+	// LongRange is the shortest query served from the coarsest namespace with prefer_coarsest_for_long_ranges.
+	LongRange time.Duration `yaml:"longRange"`
+}
+
+// RemoteConfiguration is the configuration of a remote coordinator.
+type RemoteConfiguration struct {
+	// Name identifies the remote coordinator.
+	Name string `yaml:"name"`
+
+	// Addresses are the gRPC addresses of the remote coordinator.
+	Addresses []string `yaml:"addresses" validate:"nonzero"`
+
+	// TLS enables TLS connections to the remote coordinator.
+	TLS *TLSConfiguration `yaml:"tls"`
+
+	// FetchTimeout bounds reads from the remote coordinator.
+	FetchTimeout time.Duration `yaml:"fetchTimeout"`
+
+	// WriteTimeout bounds writes to the remote coordinator.
+	WriteTimeout time.Duration `yaml:"writeTimeout"`
+}
+
+// TLSConfiguration is the TLS configuration of a remote coordinator connection.
+type TLSConfiguration struct {
+	// CAFile is the CA certificate verifying the remote, defaults to the system roots.
+	CAFile string `yaml:"caFile"`
+
+	// CertFile is the client certificate.
+	CertFile string `yaml:"certFile"`
+
+	// KeyFile is the key of the client certificate.
+	KeyFile string `yaml:"keyFile"`
+
+	// ServerName overrides the server name verified.
+	ServerName string `yaml:"serverName"`
+
+	// InsecureSkipVerify disables verifying the remote certificate.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+}
+
+// FiltersConfiguration is the configuration of the fanout filters.
+type FiltersConfiguration struct {
+	// Read is local_only, allow_all or allow_none, defaults to allow_all.
+	Read string `yaml:"read"`
+
+	// ReadRules route reads by their attributes, reads matching no rule go through the read filter.
+	ReadRules []FilterRuleConfiguration `yaml:"readRules"`
+
+	// Write is local_only, allow_all or allow_none, defaults to local_only.
+	Write string `yaml:"write"`
+
+	// WriteRules route writes by their tags, writes matching no rule go through the write filter.
+	WriteRules []FilterRuleConfiguration `yaml:"writeRules"`
+}
+
+// FilterRuleConfiguration is the configuration of a filter rule, the first rule matching a query
+// restricts it to the stores the rule matches.
+type FilterRuleConfiguration struct {
+	// Tag matches queries on the tag.
+	Tag string `yaml:"tag"`
+
+	// Value restricts the tag to a single value.
+	Value string `yaml:"value"`
+
+	// OlderThan matches reads ending more than the age ago.
+	OlderThan time.Duration `yaml:"olderThan"`
+
+	// NewerThan matches reads starting less than the age ago.
+	NewerThan time.Duration `yaml:"newerThan"`
+
+	// StoreTypes are the types of stores serving the queries matched, any of local, remote or multi.
+	StoreTypes []string `yaml:"storeTypes"`
+
+	// StoreNames are the names of stores serving the queries matched.
+	StoreNames []string `yaml:"storeNames"`
+}
+
+// NewStorage creates the local storage reading from the configured namespaces.
+func (c LocalConfiguration) NewStorage(session client.Session) (storage.Storage, error) {
+	namespaces := c.namespaces()
+	localNamespaces := make([]local.Namespace, len(namespaces))
+	policies := make([]policy.StoragePolicy, len(namespaces))
+	for i, ns := range namespaces {
+		sp := policy.NewStoragePolicy(ns.Resolution, xtime.Millisecond, ns.Retention)
+		localNamespaces[i] = local.Namespace{Name: ns.Name, StoragePolicy: sp, Type: ns.Type}
+		policies[i] = sp
+	}
+
+	policyResolver := resolver.NewRangeResolver(policies, resolver.RangeResolverOptions{
+		Strategy:  c.Resolver.Strategy,
+		LongRange: c.Resolver.LongRange,
+	})
+	return local.NewMultiNamespaceStorage(session, localNamespaces, policyResolver)
+}
+
+// namespaces returns the configured namespaces, or the unaggregated metrics namespace when none are configured.
+// Its retention is left unbounded so that every query is served from it, whatever the namespace retains.
+func (c LocalConfiguration) namespaces() []NamespaceConfiguration {
+	if len(c.Namespaces) > 0 {
+		return c.Namespaces
+	}
+
+	return []NamespaceConfiguration{{
+		Name:       defaultNamespace,
+		Resolution: defaultResolution,
+	}}
+}
+
+// NewStorage creates the storage of the remote coordinator.
+func (c RemoteConfiguration) NewStorage() (storage.Storage, error) {
+	remoteClient, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.NewStorage(remoteClient, remote.Options{
+		Name:         c.Name,
+		FetchTimeout: c.FetchTimeout,
+		WriteTimeout: c.WriteTimeout,
+	}), nil
+}
+
+func (c RemoteConfiguration) newClient() (tsdbRemote.Client, error) {
+	if c.TLS == nil {
+		return tsdbRemote.NewGrpcClient(c.Addresses)
+	}
+
+	tlsConfig, err := c.TLS.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return tsdbRemote.NewTLSGrpcClient(c.Addresses, tlsConfig)
+}
+
+// NewConfig creates the TLS config of the connection.
+func (c TLSConfiguration) NewConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		ca, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewFilters creates the read and write filters.
+func (c FiltersConfiguration) NewFilters() (filter.Storage, filter.Storage, error) {
+	read, err := newFilter(c.Read, filter.AllowAll, c.ReadRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	write, err := newFilter(c.Write, filter.LocalOnly, c.WriteRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return read, write, nil
+}
+
+func newFilter(name string, defaultFilter filter.Storage, rules []FilterRuleConfiguration) (filter.Storage, error) {
+	f := defaultFilter
+	if name != "" {
+		named, ok := storageFilters[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid storage filter %s", name)
+		}
+
+		f = named
+	}
+
+	if len(rules) == 0 {
+		return f, nil
+	}
+
+	filterRules := make([]filter.Rule, len(rules))
+	for i, rule := range rules {
+		filterRule, err := rule.newRule()
+		if err != nil {
+			return nil, err
+		}
+
+		filterRules[i] = filterRule
+	}
+
+	return filter.NewRules(filterRules, f, nil), nil
+}
+
+func (c FilterRuleConfiguration) newRule() (filter.Rule, error) {
+	types := make([]storage.Type, len(c.StoreTypes))
+	for i, name := range c.StoreTypes {
+		t, ok := storageTypes[name]
+		if !ok {
+			return filter.Rule{}, fmt.Errorf("invalid storage type %s", name)
+		}
+
+		types[i] = t
+	}
+
+	return filter.Rule{
+		Query: filter.QueryMatcher{
+			Tag:       c.Tag,
+			Value:     c.Value,
+			OlderThan: c.OlderThan,
+			NewerThan: c.NewerThan,
+		},
+		Stores: filter.StoreMatcher{
+			Types: types,
+			Names: c.StoreNames,
+		},
+	}, nil
+}
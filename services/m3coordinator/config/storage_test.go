@@ -0,0 +1,177 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/policy/resolver"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/fanout"
+	"github.com/m3db/m3coordinator/storage/local"
+	"github.com/m3db/m3coordinator/storage/mock"
+
+	"github.com/m3db/m3db/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const storagesConfig = `
+local:
+  namespaces:
+    - name: raw
+      resolution: 10s
+      retention: 48h
+    - name: downsampled
+      resolution: 1m
+      retention: 960h
+      type: aggregated
+  resolver:
+    strategy: prefer_coarsest_for_long_ranges
+    longRange: 168h
+remotes:
+  - name: eu
+    addresses: ["eu-coordinator:7288"]
+    fetchTimeout: 10s
+    tls:
+      serverName: coordinator
+filters:
+  read: local_only
+  readRules:
+    - tag: region
+      value: eu
+      storeNames: [eu]
+    - olderThan: 720h
+      storeTypes: [remote]
+fanout:
+  partialResults: true
+  storeTimeout: 5s
+  conflictPolicy: average
+  pushDownAggregations: true
+`
+
+func TestStoragesConfiguration(t *testing.T) {
+	var cfg StoragesConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(storagesConfig), &cfg))
+
+	require.Len(t, cfg.Local.Namespaces, 2)
+	assert.Equal(t, NamespaceConfiguration{
+		Name:       "raw",
+		Resolution: 10 * time.Second,
+		Retention:  48 * time.Hour,
+		Type:       local.UnaggregatedNamespace,
+	}, cfg.Local.Namespaces[0])
+	assert.Equal(t, local.AggregatedNamespace, cfg.Local.Namespaces[1].Type)
+	assert.Equal(t, resolver.PreferCoarsestForLongRanges, cfg.Local.Resolver.Strategy)
+	assert.Equal(t, 168*time.Hour, cfg.Local.Resolver.LongRange)
+
+	assert.True(t, cfg.Fanout.PartialResults)
+	assert.Equal(t, 5*time.Second, cfg.Fanout.Options().StoreTimeout)
+	assert.Equal(t, fanout.Average, cfg.Fanout.Options().ConflictPolicy)
+	assert.True(t, cfg.Fanout.Options().PushDownAggregations)
+
+	require.Len(t, cfg.Remotes, 1)
+	assert.Equal(t, []string{"eu-coordinator:7288"}, cfg.Remotes[0].Addresses)
+	assert.Equal(t, 10*time.Second, cfg.Remotes[0].FetchTimeout)
+	require.NotNil(t, cfg.Remotes[0].TLS)
+	assert.Equal(t, "coordinator", cfg.Remotes[0].TLS.ServerName)
+
+	ctrl := gomock.NewController(t)
+	store, err := cfg.Local.NewStorage(client.NewMockSession(ctrl))
+	require.NoError(t, err)
+	assert.Equal(t, storage.TypeLocalDC, store.Type())
+}
+
+func TestInvalidStoragesConfiguration(t *testing.T) {
+	var cfg StoragesConfiguration
+	assert.Error(t, yaml.Unmarshal([]byte("local:\n  namespaces:\n    - name: raw\n      type: rolled_up\n"), &cfg))
+	assert.Error(t, yaml.Unmarshal([]byte("local:\n  resolver:\n    strategy: coarsest\n"), &cfg))
+	assert.Error(t, yaml.Unmarshal([]byte("fanout:\n  conflictPolicy: prefer_remote\n"), &cfg))
+}
+
+func TestDefaultLocalNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	_, err := LocalConfiguration{}.NewStorage(client.NewMockSession(ctrl))
+	assert.NoError(t, err)
+
+	// The default namespace keeps datapoints forever so no query falls outside of it
+	assert.Equal(t, []NamespaceConfiguration{{Name: "metrics", Resolution: time.Minute}}, LocalConfiguration{}.namespaces())
+}
+
+func TestNamespaceWithoutRetention(t *testing.T) {
+	var cfg StoragesConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte("local:\n  namespaces:\n    - name: raw\n      resolution: 10s\n"), &cfg))
+	require.Len(t, cfg.Local.Namespaces, 1)
+	assert.Zero(t, cfg.Local.Namespaces[0].Retention)
+
+	ctrl := gomock.NewController(t)
+	_, err := cfg.Local.NewStorage(client.NewMockSession(ctrl))
+	assert.NoError(t, err)
+}
+
+func TestFiltersConfiguration(t *testing.T) {
+	var cfg StoragesConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(storagesConfig), &cfg))
+	require.Len(t, cfg.Filters.ReadRules, 2)
+	assert.Equal(t, []string{"eu"}, cfg.Filters.ReadRules[0].StoreNames)
+	assert.Equal(t, 720*time.Hour, cfg.Filters.ReadRules[1].OlderThan)
+
+	read, write, err := cfg.Filters.NewFilters()
+	require.NoError(t, err)
+
+	euStore := mock.NewMockStorageWithName(storage.TypeRemoteDC, "eu")
+	localStore := mock.NewMockStorageWithType(storage.TypeLocalDC)
+	euQuery := &storage.FetchQuery{
+		TagMatchers: models.Matchers{{Type: models.MatchEqual, Name: "region", Value: "eu"}},
+		Start:       time.Now().Add(-time.Hour),
+		End:         time.Now(),
+	}
+	assert.True(t, read(euQuery, euStore))
+	assert.False(t, read(euQuery, localStore))
+
+	// Reads matching no rule go through the local only filter
+	otherQuery := &storage.FetchQuery{Start: time.Now().Add(-time.Hour), End: time.Now()}
+	assert.False(t, read(otherQuery, euStore))
+	assert.True(t, read(otherQuery, localStore))
+
+	// Writes default to local only
+	assert.True(t, write(&storage.WriteQuery{}, localStore))
+	assert.False(t, write(&storage.WriteQuery{}, euStore))
+}
+
+func TestDefaultFilters(t *testing.T) {
+	read, _, err := FiltersConfiguration{}.NewFilters()
+	require.NoError(t, err)
+	assert.True(t, read(&storage.FetchQuery{}, mock.NewMockStorageWithType(storage.TypeRemoteDC)))
+}
+
+func TestInvalidFiltersConfiguration(t *testing.T) {
+	_, _, err := FiltersConfiguration{Write: "everywhere"}.NewFilters()
+	assert.Error(t, err)
+
+	_, _, err = FiltersConfiguration{ReadRules: []FilterRuleConfiguration{{StoreTypes: []string{"elsewhere"}}}}.NewFilters()
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
+
+	"go.uber.org/zap"
+)
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+type errorType string
+
+const (
+	errorBadData  errorType = "bad_data"
+	errorExec     errorType = "execution"
+	errorTimeout  errorType = "timeout"
+	errorCanceled errorType = "canceled"
+	errorInternal errorType = "internal"
+)
+
+var errorCodes = map[errorType]int{
+	errorBadData:  http.StatusBadRequest,
+	errorExec:     http.StatusUnprocessableEntity,
+	errorTimeout:  http.StatusServiceUnavailable,
+	errorCanceled: http.StatusServiceUnavailable,
+	errorInternal: http.StatusInternalServerError,
+}
+
+// Response is the envelope of every Prometheus HTTP API response
+type Response struct {
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	ErrorType errorType   `json:"errorType,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Warnings  []string    `json:"warnings,omitempty"`
+}
+
+func respond(w http.ResponseWriter, data interface{}, logger *zap.Logger) {
+	respondWithWarnings(w, data, nil, logger)
+}
+
+// respondWithWarnings responds with the warnings of the stores left out of a partial result
+func respondWithWarnings(w http.ResponseWriter, data interface{}, warnings []string, logger *zap.Logger) {
+	handler.WriteJSONResponse(w, &Response{Status: statusSuccess, Data: data, Warnings: warnings}, logger)
+}
+
+func respondError(w http.ResponseWriter, errType errorType, err error, logger *zap.Logger) {
+	data, mErr := json.Marshal(&Response{Status: statusError, ErrorType: errType, Error: err.Error()})
+	if mErr != nil {
+		logger.Error("unable to marshal json", zap.Any("error", mErr))
+		handler.Error(w, mErr, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errorCodes[errType])
+	if _, err := w.Write(data); err != nil {
+		logger.Error("unable to write error response", zap.Any("error", err))
+	}
+}
+
+// executionErrorType classifies an error from executing a request
+func executionErrorType(err error) errorType {
+	switch err {
+	case context.DeadlineExceeded:
+		return errorTimeout
+	case context.Canceled:
+		return errorCanceled
+	default:
+		return errorExec
+	}
+}
+
+// parseTime parses the named form value, returning the default if it is not set
+func parseTime(r *http.Request, name string, defaultTime time.Time) (time.Time, error) {
+	value := r.FormValue(name)
+	if value == "" {
+		return defaultTime, nil
+	}
+
+	t, err := prometheus.ParseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid '%s': %v", name, err)
+	}
+
+	return t, nil
+}
+
+// parseTimeout parses the timeout of the request
+func parseTimeout(r *http.Request) (time.Duration, error) {
+	params, err := prometheus.ParseRequestParams(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return params.Timeout, nil
+}
+
+// point is a single value, encoded as [unix seconds, "value"]
+type point struct {
+	T time.Time
+	V float64
+}
+
+// MarshalJSON encodes the point the way Prometheus does, timestamps have millisecond precision
+func (p point) MarshalJSON() ([]byte, error) {
+	millis := p.T.UnixNano() / int64(time.Millisecond)
+	timestamp := strconv.FormatFloat(float64(millis)/1000, 'f', -1, 64)
+	value, err := json.Marshal(formatValue(p.V))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte("[" + timestamp + "," + string(value) + "]"), nil
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// metric returns the tags of a series, never nil so it is encoded as an object
+func metric(tags models.Tags) models.Tags {
+	if tags == nil {
+		return models.Tags{}
+	}
+
+	return tags
+}
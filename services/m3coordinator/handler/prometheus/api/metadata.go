@@ -0,0 +1,270 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser/promql"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// SeriesURL is the url to find series matching selectors
+	SeriesURL = "/api/v1/series"
+	// LabelNamesURL is the url to list label names
+	LabelNamesURL = "/api/v1/labels"
+	// LabelValuesURL is the url to list the values of a label
+	LabelValuesURL = "/api/v1/label/{" + labelNameVar + "}/values"
+
+	matchParam   = "match[]"
+	labelNameVar = "name"
+)
+
+var errNoMatch = errors.New("no match[] parameter provided")
+
+// defaultMetadataStart is used when no start is given, which covers everything still retained
+var defaultMetadataStart = time.Unix(0, 0)
+
+// SeriesHandler serves the series matching a set of selectors
+type SeriesHandler struct {
+	store storage.Storage
+}
+
+// NewSeriesHandler returns a new instance of handler
+func NewSeriesHandler(store storage.Storage) http.Handler {
+	return &SeriesHandler{store: store}
+}
+
+func (h *SeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	selectors := r.Form[matchParam]
+	if len(selectors) == 0 {
+		respondError(w, errorBadData, errNoMatch, logger)
+		return
+	}
+
+	matchers := make([]models.Matchers, len(selectors))
+	for i, selector := range selectors {
+		m, err := promql.ParseMatchers(selector)
+		if err != nil {
+			respondError(w, errorBadData, err, logger)
+			return
+		}
+
+		matchers[i] = m
+	}
+
+	timeRange, timeout, err := parseMetadataParams(r)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	seen := make(map[string]struct{})
+	result := make([]models.Tags, 0)
+	for _, m := range matchers {
+		tags, err := fetchTags(ctx, h.store, m, timeRange)
+		if err != nil {
+			respondError(w, executionErrorType(err), err, logger)
+			return
+		}
+
+		for _, t := range tags {
+			id := t.ID()
+			if _, ok := seen[id]; ok {
+				continue
+			}
+
+			seen[id] = struct{}{}
+			result = append(result, t)
+		}
+	}
+
+	respond(w, result, logger)
+}
+
+// LabelNamesHandler serves the names of all labels
+type LabelNamesHandler struct {
+	store storage.Storage
+}
+
+// NewLabelNamesHandler returns a new instance of handler
+func NewLabelNamesHandler(store storage.Storage) http.Handler {
+	return &LabelNamesHandler{store: store}
+}
+
+func (h *LabelNamesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	timeRange, timeout, err := parseMetadataParams(r)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// Every series written through Prometheus has a metric name
+	tags, err := fetchLabel(ctx, h.store, model.MetricNameLabel, timeRange)
+	if err != nil {
+		respondError(w, executionErrorType(err), err, logger)
+		return
+	}
+
+	names := make(map[string]struct{})
+	for _, t := range tags {
+		for name := range t {
+			names[name] = struct{}{}
+		}
+	}
+
+	respond(w, sortedKeys(names), logger)
+}
+
+// LabelValuesHandler serves the values of a single label
+type LabelValuesHandler struct {
+	store storage.Storage
+}
+
+// NewLabelValuesHandler returns a new instance of handler
+func NewLabelValuesHandler(store storage.Storage) http.Handler {
+	return &LabelValuesHandler{store: store}
+}
+
+func (h *LabelValuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	name := mux.Vars(r)[labelNameVar]
+	if !model.LabelName(name).IsValid() {
+		respondError(w, errorBadData, fmt.Errorf("invalid label name: %q", name), logger)
+		return
+	}
+
+	timeRange, timeout, err := parseMetadataParams(r)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	tags, err := fetchLabel(ctx, h.store, name, timeRange)
+	if err != nil {
+		respondError(w, executionErrorType(err), err, logger)
+		return
+	}
+
+	values := make(map[string]struct{})
+	for _, t := range tags {
+		values[t[name]] = struct{}{}
+	}
+
+	respond(w, sortedKeys(values), logger)
+}
+
+// metadataTimeRange is the time range of series to search
+type metadataTimeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func parseMetadataParams(r *http.Request) (metadataTimeRange, time.Duration, error) {
+	start, err := parseTime(r, startParam, defaultMetadataStart)
+	if err != nil {
+		return metadataTimeRange{}, 0, err
+	}
+
+	end, err := parseTime(r, endParam, time.Now())
+	if err != nil {
+		return metadataTimeRange{}, 0, err
+	}
+
+	if end.Before(start) {
+		return metadataTimeRange{}, 0, fmt.Errorf("invalid '%s': end timestamp must not be before start time", endParam)
+	}
+
+	timeout, err := parseTimeout(r)
+	if err != nil {
+		return metadataTimeRange{}, 0, err
+	}
+
+	return metadataTimeRange{start: start, end: end}, timeout, nil
+}
+
+// fetchLabel fetches the tags of every series which has the label
+func fetchLabel(ctx context.Context, store storage.Storage, name string, timeRange metadataTimeRange) ([]models.Tags, error) {
+	matcher, err := models.NewMatcher(models.MatchRegexp, name, ".+")
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchTags(ctx, store, models.Matchers{matcher}, timeRange)
+}
+
+func fetchTags(ctx context.Context, store storage.Storage, matchers models.Matchers, timeRange metadataTimeRange) ([]models.Tags, error) {
+	result, err := store.FetchTags(ctx, &storage.FetchQuery{
+		TagMatchers: matchers,
+		Start:       timeRange.start,
+		End:         timeRange.end,
+	}, &storage.FetchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]models.Tags, 0, len(result.Metrics))
+	for _, m := range result.Metrics {
+		tags = append(tags, metric(m.Tags))
+	}
+
+	return tags, nil
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage/mock"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetadataStorage() *fakeStorage {
+	return &fakeStorage{
+		Storage: mock.NewMockStorage(),
+		metrics: models.Metrics{
+			{ID: "1", Tags: models.Tags{"__name__": "up", "job": "api", "instance": "a"}},
+			{ID: "2", Tags: models.Tags{"__name__": "up", "job": "db"}},
+			{ID: "1", Tags: models.Tags{"__name__": "up", "job": "api", "instance": "a"}},
+		},
+	}
+}
+
+func TestSeries(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := newMetadataStorage()
+	h := NewSeriesHandler(store)
+
+	res := serve(h, SeriesURL+"?match[]="+url.QueryEscape(`up{job=~"api|db"}`)+"&start=1500000000&end=1500000060")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":[
+		{"__name__":"up","job":"api","instance":"a"},
+		{"__name__":"up","job":"db"}
+	]}`, res.Body.String())
+
+	require.Len(t, store.queries, 1)
+	assert.True(t, queryTime.Equal(store.queries[0].Start))
+	assert.Len(t, store.queries[0].TagMatchers, 2)
+
+	res = serve(h, SeriesURL)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	res = serve(h, SeriesURL+"?match[]=up{")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestLabelNames(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := newMetadataStorage()
+	res := serve(NewLabelNamesHandler(store), LabelNamesURL)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":["__name__","instance","job"]}`, res.Body.String())
+
+	require.Len(t, store.queries, 1)
+	require.Len(t, store.queries[0].TagMatchers, 1)
+	assert.Equal(t, "__name__", store.queries[0].TagMatchers[0].Name)
+	assert.Equal(t, models.MatchRegexp, store.queries[0].TagMatchers[0].Type)
+}
+
+func TestLabelValues(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := newMetadataStorage()
+	router := mux.NewRouter()
+	router.Handle(LabelValuesURL, NewLabelValuesHandler(store))
+
+	res := serve(router, "/api/v1/label/job/values")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":["api","db"]}`, res.Body.String())
+	require.Len(t, store.queries, 1)
+	assert.Equal(t, "job", store.queries[0].TagMatchers[0].Name)
+
+	res = serve(router, "/api/v1/label/in-valid/values")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser/promql"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	pql "github.com/prometheus/prometheus/promql"
+)
+
+const (
+	// QueryURL is the url for instant queries
+	QueryURL = "/api/v1/query"
+	// QueryRangeURL is the url for range queries
+	QueryRangeURL = "/api/v1/query_range"
+
+	queryParam = "query"
+	timeParam  = "time"
+	startParam = "start"
+	endParam   = "end"
+	stepParam  = "step"
+
+	// instantStep is the resolution at which data is consolidated for instant queries
+	instantStep = 15 * time.Second
+	// maxPoints matches the Prometheus limit on the number of steps of a range query
+	maxPoints = 11000
+)
+
+var (
+	errNoQuery       = errors.New("missing 'query' parameter")
+	errMatrixInstant = errors.New("range vector selectors are not supported in instant queries")
+)
+
+// queryData is the data of a query response
+type queryData struct {
+	ResultType pql.ValueType `json:"resultType"`
+	Result     interface{}   `json:"result"`
+}
+
+type vectorResult struct {
+	Metric models.Tags `json:"metric"`
+	Value  point       `json:"value"`
+}
+
+type matrixResult struct {
+	Metric models.Tags `json:"metric"`
+	Values []point     `json:"values"`
+}
+
+// QueryHandler serves Prometheus instant queries
+type QueryHandler struct {
+	engine *executor.Engine
+}
+
+// NewQueryHandler returns a new instance of handler
+func NewQueryHandler(engine *executor.Engine) http.Handler {
+	return &QueryHandler{engine: engine}
+}
+
+func (h *QueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	query := r.FormValue(queryParam)
+	if query == "" {
+		respondError(w, errorBadData, errNoQuery, logger)
+		return
+	}
+
+	now := time.Now()
+	t, err := parseTime(r, timeParam, now)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	timeout, err := parseTimeout(r)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	expr, err := pql.ParseExpr(query)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	switch expr.Type() {
+	case pql.ValueTypeString:
+		respond(w, &queryData{ResultType: pql.ValueTypeString, Result: stringResult(expr, t)}, logger)
+		return
+	case pql.ValueTypeMatrix:
+		respondError(w, errorBadData, errMatrixInstant, logger)
+		return
+	}
+
+	p, err := promql.Parse(query)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	series, warnings, err := prometheus.ExecuteQuery(r.Context(), w, h.engine, p, models.RequestParams{
+		Start:   t,
+		End:     t,
+		Now:     now,
+		Step:    instantStep,
+		Timeout: timeout,
+		Target:  query,
+	})
+	if err != nil {
+		respondError(w, executionErrorType(err), err, logger)
+		return
+	}
+
+	if expr.Type() == pql.ValueTypeScalar {
+		value := math.NaN()
+		if len(series) > 0 && series[0].Len() > 0 {
+			value = series[0].ValueAt(series[0].Len() - 1)
+		}
+
+		respondWithWarnings(w, &queryData{ResultType: pql.ValueTypeScalar, Result: point{T: t, V: value}}, storage.WarningStrings(warnings), logger)
+		return
+	}
+
+	result := make([]vectorResult, 0, len(series))
+	for _, s := range series {
+		if s.Len() == 0 {
+			continue
+		}
+
+		// Series without a value at the query time are absent from the vector
+		value := s.ValueAt(s.Len() - 1)
+		if math.IsNaN(value) {
+			continue
+		}
+
+		result = append(result, vectorResult{Metric: metric(s.Tags), Value: point{T: t, V: value}})
+	}
+
+	respondWithWarnings(w, &queryData{ResultType: pql.ValueTypeVector, Result: result}, storage.WarningStrings(warnings), logger)
+}
+
+// QueryRangeHandler serves Prometheus range queries
+type QueryRangeHandler struct {
+	engine *executor.Engine
+}
+
+// NewQueryRangeHandler returns a new instance of handler
+func NewQueryRangeHandler(engine *executor.Engine) http.Handler {
+	return &QueryRangeHandler{engine: engine}
+}
+
+func (h *QueryRangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	params, err := parseRangeParams(r)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	expr, err := pql.ParseExpr(params.Target)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	if expr.Type() != pql.ValueTypeVector && expr.Type() != pql.ValueTypeScalar {
+		respondError(w, errorBadData, fmt.Errorf("invalid expression type %q for range query, must be scalar or instant vector", expr.Type()), logger)
+		return
+	}
+
+	p, err := promql.Parse(params.Target)
+	if err != nil {
+		respondError(w, errorBadData, err, logger)
+		return
+	}
+
+	series, warnings, err := prometheus.ExecuteQuery(r.Context(), w, h.engine, p, params)
+	if err != nil {
+		respondError(w, executionErrorType(err), err, logger)
+		return
+	}
+
+	respondWithWarnings(w, &queryData{ResultType: pql.ValueTypeMatrix, Result: seriesToMatrix(series)}, storage.WarningStrings(warnings), logger)
+}
+
+func parseRangeParams(r *http.Request) (models.RequestParams, error) {
+	params := models.RequestParams{
+		Now:    time.Now(),
+		Target: r.FormValue(queryParam),
+	}
+
+	if params.Target == "" {
+		return params, errNoQuery
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *time.Time
+	}{{startParam, &params.Start}, {endParam, &params.End}} {
+		if r.FormValue(p.name) == "" {
+			return params, fmt.Errorf("missing '%s' parameter", p.name)
+		}
+
+		t, err := parseTime(r, p.name, time.Time{})
+		if err != nil {
+			return params, err
+		}
+
+		*p.value = t
+	}
+
+	if params.End.Before(params.Start) {
+		return params, fmt.Errorf("invalid '%s': end timestamp must not be before start time", endParam)
+	}
+
+	step, err := prometheus.ParseDuration(r.FormValue(stepParam))
+	if err != nil {
+		return params, fmt.Errorf("invalid '%s': %v", stepParam, err)
+	}
+
+	if step <= 0 {
+		return params, fmt.Errorf("invalid '%s': zero or negative query resolution step widths are not accepted", stepParam)
+	}
+
+	if params.End.Sub(params.Start)/step > maxPoints {
+		return params, fmt.Errorf("exceeded maximum resolution of %d points per timeseries, try decreasing the query resolution", maxPoints)
+	}
+
+	params.Step = step
+	params.Timeout, err = parseTimeout(r)
+	return params, err
+}
+
+// seriesToMatrix converts series to matrix results, dropping missing values and empty series
+func seriesToMatrix(series []ts.Series) []matrixResult {
+	result := make([]matrixResult, 0, len(series))
+	for _, s := range series {
+		var points []point
+		for i := 0; i < s.Len(); i++ {
+			if v := s.ValueAt(i); !math.IsNaN(v) {
+				points = append(points, point{T: s.StartTimeForStep(i), V: v})
+			}
+		}
+
+		if len(points) == 0 {
+			continue
+		}
+
+		result = append(result, matrixResult{Metric: metric(s.Tags), Values: points})
+	}
+
+	return result
+}
+
+// stringResult is the result of a string literal, which does not need to be executed
+func stringResult(expr pql.Expr, t time.Time) []interface{} {
+	for {
+		paren, ok := expr.(*pql.ParenExpr)
+		if !ok {
+			break
+		}
+
+		expr = paren.Expr
+	}
+
+	var value string
+	if literal, ok := expr.(*pql.StringLiteral); ok {
+		value = literal.Val
+	}
+
+	millis := t.UnixNano() / int64(time.Millisecond)
+	return []interface{}{float64(millis) / 1000, value}
+}
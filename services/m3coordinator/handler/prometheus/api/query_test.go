@@ -0,0 +1,166 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var queryTime = time.Unix(1500000000, 0)
+
+// fakeStorage returns the given blocks and metrics and records the queries
+type fakeStorage struct {
+	storage.Storage
+	blocks  []storage.Block
+	metrics models.Metrics
+	err     error
+	queries []*storage.FetchQuery
+}
+
+func (s *fakeStorage) FetchBlocks(
+	_ context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (storage.BlockResult, error) {
+	s.queries = append(s.queries, query)
+	return storage.BlockResult{Blocks: s.blocks}, s.err
+}
+
+func (s *fakeStorage) FetchTags(
+	_ context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.SearchResults, error) {
+	s.queries = append(s.queries, query)
+	return &storage.SearchResults{Metrics: s.metrics}, s.err
+}
+
+func newBlockStorage(t *testing.T, steps int, values [][]float64) *fakeStorage {
+	bounds := storage.Bounds{
+		Start:    queryTime,
+		End:      queryTime.Add(time.Duration(steps-1) * 15 * time.Second),
+		StepSize: 15 * time.Second,
+	}
+
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, []storage.SeriesMeta{
+		{Name: "up_api", Tags: models.Tags{"__name__": "up", "job": "api"}},
+		{Name: "up_db", Tags: models.Tags{"__name__": "up", "job": "db"}},
+	}, values)
+	require.NoError(t, err)
+	return &fakeStorage{Storage: mock.NewMockStorage(), blocks: []storage.Block{block}}
+}
+
+func serve(h http.Handler, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	return res
+}
+
+func TestInstantQuery(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := newBlockStorage(t, 1, [][]float64{{1}, {math.NaN()}})
+	h := NewQueryHandler(executor.NewEngine(store))
+
+	res := serve(h, QueryURL+"?query=up&time=1500000000")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"__name__":"up","job":"api"},"value":[1500000000,"1"]}
+	]}}`, res.Body.String())
+
+	require.Len(t, store.queries, 1)
+	assert.True(t, queryTime.Equal(store.queries[0].Start))
+	assert.True(t, queryTime.Equal(store.queries[0].End))
+}
+
+func TestInstantQueryScalarAndString(t *testing.T) {
+	logging.InitWithCores(nil)
+	h := NewQueryHandler(executor.NewEngine(mock.NewMockStorage()))
+
+	res := serve(h, QueryURL+"?query="+url.QueryEscape("1 + 1")+"&time=1500000000")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":{"resultType":"scalar","result":[1500000000,"2"]}}`, res.Body.String())
+
+	res = serve(h, QueryURL+"?query="+url.QueryEscape(`"foo"`)+"&time=1500000000.5")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":{"resultType":"string","result":[1500000000.5,"foo"]}}`, res.Body.String())
+}
+
+func TestRangeQuery(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := newBlockStorage(t, 3, [][]float64{{1, math.NaN(), 3}, {math.NaN(), math.NaN(), math.NaN()}})
+	h := NewQueryRangeHandler(executor.NewEngine(store))
+
+	res := serve(h, QueryRangeURL+"?query=up&start=1500000000&end=1500000030&step=15s")
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.JSONEq(t, `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"__name__":"up","job":"api"},"values":[[1500000000,"1"],[1500000030,"3"]]}
+	]}}`, res.Body.String())
+
+	require.Len(t, store.queries, 1)
+	assert.Equal(t, 15*time.Second, store.queries[0].Interval)
+	assert.True(t, queryTime.Add(30*time.Second).Equal(store.queries[0].End))
+}
+
+func TestQueryErrors(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &fakeStorage{Storage: mock.NewMockStorage(), err: fmt.Errorf("storage error")}
+	engine := executor.NewEngine(store)
+
+	tests := []struct {
+		handler http.Handler
+		target  string
+		code    int
+		errType errorType
+	}{
+		{NewQueryHandler(engine), QueryURL, http.StatusBadRequest, errorBadData},
+		{NewQueryHandler(engine), QueryURL + "?query=up{", http.StatusBadRequest, errorBadData},
+		{NewQueryHandler(engine), QueryURL + "?query=up&time=foo", http.StatusBadRequest, errorBadData},
+		{NewQueryHandler(engine), QueryURL + "?query=up[5m]", http.StatusBadRequest, errorBadData},
+		{NewQueryHandler(engine), QueryURL + "?query=up", http.StatusUnprocessableEntity, errorExec},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up&end=10&step=1", http.StatusBadRequest, errorBadData},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up&start=10&end=0&step=1", http.StatusBadRequest, errorBadData},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up&start=0&end=10&step=0", http.StatusBadRequest, errorBadData},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up&start=0&end=100000&step=1", http.StatusBadRequest, errorBadData},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up[5m]&start=0&end=10&step=1", http.StatusBadRequest, errorBadData},
+		{NewQueryRangeHandler(engine), QueryRangeURL + "?query=up&start=0&end=10&step=1", http.StatusUnprocessableEntity, errorExec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			res := serve(tt.handler, tt.target)
+			require.Equal(t, tt.code, res.Code, res.Body.String())
+			assert.Contains(t, res.Body.String(), `"status":"error"`)
+			assert.Contains(t, res.Body.String(), fmt.Sprintf(`"errorType":"%s"`, tt.errType))
+		})
+	}
+}
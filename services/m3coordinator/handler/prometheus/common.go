@@ -23,12 +23,15 @@ package prometheus
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
 
 	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
 )
 
 const (
@@ -71,6 +74,9 @@ func ParsePromCompressedRequest(r *http.Request) ([]byte, *handler.ParseError) {
 func ParseRequestParams(r *http.Request) (*RequestParams, error) {
 	var params RequestParams
 	timeout := r.Header.Get("timeout")
+	if timeout == "" {
+		timeout = r.URL.Query().Get("timeout")
+	}
 	if timeout != "" {
 		duration, err := time.ParseDuration(timeout)
 		if err != nil {
@@ -88,3 +94,30 @@ func ParseRequestParams(r *http.Request) (*RequestParams, error) {
 
 	return &params, nil
 }
+
+// ParseTime parses a time which is either a unix timestamp in seconds or an RFC3339 string
+func ParseTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(t)
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// ParseDuration parses a duration which is either a number of seconds or a Prometheus duration string
+func ParseDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
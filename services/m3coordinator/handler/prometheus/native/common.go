@@ -0,0 +1,136 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
+	"github.com/m3db/m3coordinator/ts"
+)
+
+const (
+	endParam    = "end"
+	startParam  = "start"
+	stepParam   = "step"
+	defaultStep = 15 * time.Second
+)
+
+// parseParams parses all params from the GET request, missing times default to an instant query at the current time
+func parseParams(r *http.Request) (models.RequestParams, *handler.ParseError) {
+	params := models.RequestParams{
+		Now:  time.Now(),
+		Step: defaultStep,
+	}
+
+	t, err := prometheus.ParseRequestParams(r)
+	if err != nil {
+		return params, handler.NewParseError(err, http.StatusBadRequest)
+	}
+
+	params.Timeout = t.Timeout
+	params.End = params.Now
+	if end := r.FormValue(endParam); end != "" {
+		params.End, err = prometheus.ParseTime(end)
+		if err != nil {
+			return params, handler.NewParseError(fmt.Errorf("%s: invalid '%s': %v", handler.ErrInvalidParams, endParam, err), http.StatusBadRequest)
+		}
+	}
+
+	params.Start = params.End
+	if start := r.FormValue(startParam); start != "" {
+		params.Start, err = prometheus.ParseTime(start)
+		if err != nil {
+			return params, handler.NewParseError(fmt.Errorf("%s: invalid '%s': %v", handler.ErrInvalidParams, startParam, err), http.StatusBadRequest)
+		}
+	}
+
+	if params.End.Before(params.Start) {
+		return params, handler.NewParseError(fmt.Errorf("%s: '%s' is before '%s'", handler.ErrInvalidParams, endParam, startParam), http.StatusBadRequest)
+	}
+
+	if step := r.FormValue(stepParam); step != "" {
+		params.Step, err = prometheus.ParseDuration(step)
+		if err != nil {
+			return params, handler.NewParseError(fmt.Errorf("%s: invalid '%s': %v", handler.ErrInvalidParams, stepParam, err), http.StatusBadRequest)
+		}
+
+		if params.Step <= 0 {
+			return params, handler.NewParseError(fmt.Errorf("%s: '%s' must be positive", handler.ErrInvalidParams, stepParam), http.StatusBadRequest)
+		}
+	}
+
+	return params, nil
+}
+
+// SeriesResult is a single series in the read response
+type SeriesResult struct {
+	Target     string      `json:"target"`
+	Tags       models.Tags `json:"tags"`
+	StepSizeMs int         `json:"step_size_ms"`
+	Datapoints []Datapoint `json:"datapoints"`
+}
+
+// Datapoint is a value along with its timestamp
+type Datapoint struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// MarshalJSON encodes the datapoint as [value, unix seconds], NaN values are encoded as null
+func (d Datapoint) MarshalJSON() ([]byte, error) {
+	value := "null"
+	if !math.IsNaN(d.Value) {
+		value = strconv.FormatFloat(d.Value, 'f', -1, 64)
+	}
+
+	millis := d.Timestamp.UnixNano() / int64(time.Millisecond)
+	timestamp := strconv.FormatFloat(float64(millis)/1000, 'f', -1, 64)
+	return []byte("[" + value + "," + timestamp + "]"), nil
+}
+
+func seriesToResults(series []ts.Series) []SeriesResult {
+	results := make([]SeriesResult, len(series))
+	for i, s := range series {
+		datapoints := make([]Datapoint, s.Len())
+		for j := range datapoints {
+			datapoints[j] = Datapoint{
+				Value:     s.ValueAt(j),
+				Timestamp: s.StartTimeForStep(j),
+			}
+		}
+
+		results[i] = SeriesResult{
+			Target:     s.Name(),
+			Tags:       s.Tags,
+			StepSizeMs: s.MillisPerStep(),
+			Datapoints: datapoints,
+		}
+	}
+
+	return results
+}
@@ -25,11 +25,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/parser/promql"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
+	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
 	"github.com/m3db/m3coordinator/util/logging"
 
@@ -56,7 +59,7 @@ type PromReadHandler struct {
 
 // ReadResponse is the response that gets returned to the user
 type ReadResponse struct {
-	Results []ts.Series `json:"results,omitempty"`
+	Results []SeriesResult `json:"results,omitempty"`
 }
 
 // NewPromReadHandler returns a new instance of handler.
@@ -74,13 +77,14 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	params, err := prometheus.ParseRequestParams(r)
-	if err != nil {
-		handler.Error(w, err, http.StatusBadRequest)
+	params, rErr := parseParams(r)
+	if rErr != nil {
+		handler.Error(w, rErr.Error(), rErr.Code())
 		return
 	}
 
-	result, err := h.read(ctx, w, req, params)
+	params.Target = req
+	result, warnings, err := h.read(ctx, w, params)
 	if err != nil {
 		logger.Error("unable to fetch data", zap.Any("error", err))
 		handler.Error(w, err, http.StatusInternalServerError)
@@ -88,7 +92,7 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := &ReadResponse{
-		Results: result,
+		Results: seriesToResults(result),
 	}
 
 	data, err := json.Marshal(resp)
@@ -99,6 +103,9 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if len(warnings) > 0 {
+		w.Header().Set(handler.WarningsHeader, strings.Join(storage.WarningStrings(warnings), ", "))
+	}
 
 	if _, err := w.Write(data); err != nil {
 		logger.Error("unable to write results", zap.Any("err", err))
@@ -125,20 +132,11 @@ func (h *PromReadHandler) parseRequest(r *http.Request) (string, *handler.ParseE
 	return targetQueries[0], nil
 }
 
-func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, req string, params *prometheus.RequestParams) ([]ts.Series, error) {
-	ctx, cancel := context.WithTimeout(reqCtx, params.Timeout)
-	defer cancel()
-
-	opts := &executor.EngineOptions{}
-	// Detect clients closing connections
-	abortCh, _ := handler.CloseWatcher(ctx, w)
-	opts.AbortCh = abortCh
-
-	_, err := promql.Parse(req)
+func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, params models.RequestParams) ([]ts.Series, []storage.Warning, error) {
+	parser, err := promql.Parse(params.Target)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// todo(braskin): implement query execution
-	return nil, errors.New("not implemented")
+	return prometheus.ExecuteQuery(reqCtx, w, h.engine, parser, params)
 }
@@ -23,6 +23,7 @@ package native
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -30,11 +31,15 @@ import (
 	"time"
 
 	"github.com/m3db/m3coordinator/executor"
-	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
 	"github.com/m3db/m3coordinator/test/local"
+	"github.com/m3db/m3coordinator/ts"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,34 +61,120 @@ func TestPromReadParsing(t *testing.T) {
 	require.Equal(t, promQuery, r)
 }
 
-func TestPromReadNotImplemented(t *testing.T) {
-	logging.InitWithCores(nil)
-	ctrl := gomock.NewController(t)
-	storage, _ := local.NewStorageAndSession(ctrl)
-	promRead := &PromReadHandler{engine: executor.NewEngine(storage)}
+func TestParseParams(t *testing.T) {
+	req, _ := http.NewRequest("GET", PromReadURL+"?start=1500000000&end=2017-07-14T02:50:00Z&step=30s", nil)
+	params, err := parseParams(req)
+	require.Nil(t, err)
+	assert.Equal(t, time.Unix(1500000000, 0), params.Start)
+	assert.True(t, time.Unix(1500000600, 0).Equal(params.End))
+	assert.Equal(t, 30*time.Second, params.Step)
+	assert.Equal(t, 15*time.Second, params.Timeout)
+}
+
+func TestParseParamsDefaults(t *testing.T) {
 	req, _ := http.NewRequest("GET", createURL().String(), nil)
+	params, err := parseParams(req)
+	require.Nil(t, err)
+	assert.Equal(t, params.Now, params.Start)
+	assert.Equal(t, params.Now, params.End)
+	assert.Equal(t, defaultStep, params.Step)
+}
 
-	r, parseErr := promRead.parseRequest(req)
-	require.Nil(t, parseErr, "unable to parse request")
-	_, err := promRead.read(context.TODO(), httptest.NewRecorder(), r, &prometheus.RequestParams{Timeout: time.Hour})
-	require.NotNil(t, err, "not implemented")
+func TestParseParamsInvalid(t *testing.T) {
+	for _, query := range []string{
+		"?start=foo",
+		"?end=foo",
+		"?step=foo",
+		"?step=-10",
+		"?start=1500000600&end=1500000000",
+	} {
+		req, _ := http.NewRequest("GET", PromReadURL+query, nil)
+		_, err := parseParams(req)
+		require.NotNil(t, err, query)
+		assert.Equal(t, http.StatusBadRequest, err.Code(), query)
+	}
+}
+
+func TestPromReadStorageError(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &blockStorage{Storage: mock.NewMockStorage(), err: fmt.Errorf("storage error")}
+	promRead := &PromReadHandler{engine: executor.NewEngine(store)}
+
+	_, _, err := promRead.read(context.TODO(), httptest.NewRecorder(), models.RequestParams{
+		Target:  promQuery,
+		Timeout: time.Hour,
+	})
+	assert.EqualError(t, err, "storage error")
 }
 
-// NB(braskin): will replace this test once the server actually returns something
 func TestPromReadEndpoint(t *testing.T) {
 	logging.InitWithCores(nil)
-	ctrl := gomock.NewController(t)
-	// No calls expected on session object
-	req, _ := http.NewRequest("GET", createURL().String(), nil)
-	res := httptest.NewRecorder()
-	storage, _ := local.NewStorageAndSession(ctrl)
-	engine := executor.NewEngine(storage)
-	promRead := &PromReadHandler{engine: engine}
+	start := time.Unix(1500000000, 0)
+	values := ts.NewValues(context.TODO(), 10000, 2)
+	values.SetValueAt(0, 1.5)
+	series := ts.NewSeries(context.TODO(), "http_requests_total", start, values, models.Tags{"job": "prometheus"})
+	store := &blockStorage{
+		Storage: mock.NewMockStorage(),
+		blocks:  []storage.Block{&seriesBlock{series: []ts.Series{*series}}},
+	}
 
+	req, _ := http.NewRequest("GET", createURL().String()+"&start=1500000000&end=1500000010&step=10s", nil)
+	res := httptest.NewRecorder()
+	promRead := &PromReadHandler{engine: executor.NewEngine(store)}
 	promRead.ServeHTTP(res, req)
-	require.Equal(t, "not implemented\n", res.Body.String())
+
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"results":[{
+		"target":"http_requests_total",
+		"tags":{"job":"prometheus"},
+		"step_size_ms":10000,
+		"datapoints":[[1.5,1500000000],[null,1500000010]]
+	}]}`, res.Body.String())
+
+	require.NotNil(t, store.query)
+	assert.True(t, start.Equal(store.query.Start))
+	assert.True(t, start.Add(10*time.Second).Equal(store.query.End))
+	assert.Equal(t, 10*time.Second, store.query.Interval)
+}
+
+// blockStorage returns the given blocks from FetchBlocks and records the query
+type blockStorage struct {
+	storage.Storage
+	blocks []storage.Block
+	err    error
+	query  *storage.FetchQuery
+}
+
+func (s *blockStorage) FetchBlocks(
+	_ context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (storage.BlockResult, error) {
+	s.query = query
+	return storage.BlockResult{Blocks: s.blocks}, s.err
 }
 
+type seriesBlock struct {
+	series []ts.Series
+}
+
+func (b *seriesBlock) Meta() storage.BlockMetadata      { return storage.BlockMetadata{} }
+func (b *seriesBlock) StepIter() storage.StepIter       { return nil }
+func (b *seriesBlock) SeriesIter() storage.SeriesIter   { return &seriesIter{series: b.series, idx: -1} }
+func (b *seriesBlock) SeriesMeta() []storage.SeriesMeta { return nil }
+func (b *seriesBlock) StepMeta() []storage.StepMeta     { return nil }
+func (b *seriesBlock) Close() error                     { return nil }
+
+type seriesIter struct {
+	series []ts.Series
+	idx    int
+}
+
+func (i *seriesIter) Next() bool {
+	i.idx++
+	return i.idx < len(i.series)
+}
+
+func (i *seriesIter) Current() ts.Series { return i.series[i.idx] }
+
 func createURL() *bytes.Buffer {
 	var buffer bytes.Buffer
 
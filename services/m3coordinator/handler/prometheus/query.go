@@ -0,0 +1,80 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+)
+
+// ExecuteQuery executes a parsed query and returns every series of the result along with the stores left
+// out of it, the query is aborted once the timeout of the params expires or the client closes the connection
+func ExecuteQuery(
+	reqCtx context.Context,
+	w http.ResponseWriter,
+	engine *executor.Engine,
+	p parser.Parser,
+	params models.RequestParams,
+) ([]ts.Series, []storage.Warning, error) {
+	ctx, cancel := context.WithTimeout(reqCtx, params.Timeout)
+	defer cancel()
+
+	opts := &executor.EngineOptions{}
+	// Detect clients closing connections
+	abortCh, closingCh := handler.CloseWatcher(ctx, w)
+	opts.AbortCh = abortCh
+
+	// Results is closed by execute
+	results := make(chan executor.Query)
+	go engine.ExecuteExpr(ctx, p, opts, params, closingCh, results)
+
+	var (
+		series   []ts.Series
+		warnings []storage.Warning
+	)
+	for result := range results {
+		if result.Err != nil {
+			return nil, nil, result.Err
+		}
+
+		warnings = append(warnings, result.Warnings...)
+		for _, block := range result.Result.Blocks() {
+			iter := block.SeriesIter()
+			for iter.Next() {
+				series = append(series, iter.Current())
+			}
+
+			// Series are copied out of the block so its memory can be reused
+			if err := block.Close(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return series, warnings, nil
+}
@@ -22,14 +22,17 @@ package remote
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/m3db/m3coordinator/executor"
 	"github.com/m3db/m3coordinator/generated/proto/prompb"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
 	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/util/execution"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/golang/protobuf/proto"
@@ -45,11 +48,15 @@ const (
 // PromReadHandler represents a handler for prometheus read endpoint.
 type PromReadHandler struct {
 	engine *executor.Engine
+	store  storage.Storage
+	// lookback is the lookback of the prometheus servers, stores downsample with the default one when unset
+	lookback time.Duration
 }
 
-// NewPromReadHandler returns a new instance of handler.
-func NewPromReadHandler(engine *executor.Engine) http.Handler {
-	return &PromReadHandler{engine: engine}
+// NewPromReadHandler returns a new instance of handler, clients accepting streamed chunks are served
+// straight from the store when it can stream raw series.
+func NewPromReadHandler(engine *executor.Engine, store storage.Storage, lookback time.Duration) http.Handler {
+	return &PromReadHandler{engine: engine, store: store, lookback: lookback}
 }
 
 func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +76,12 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.read(ctx, w, req, params)
+	if h.negotiateResponseType(req.AcceptedResponseTypes) == prompb.ReadRequest_STREAMED_XOR_CHUNKS {
+		h.serveStreamed(ctx, w, req, params)
+		return
+	}
+
+	result, warnings, err := h.read(ctx, w, req, params)
 	if err != nil {
 		logger.Error("unable to fetch data", zap.Any("error", err))
 		handler.Error(w, err, http.StatusInternalServerError)
@@ -89,6 +101,10 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/x-protobuf")
 	w.Header().Set("Content-Encoding", "snappy")
+	// Stores left out of a partial result are reported in a header, the protobuf has no field for them
+	if len(warnings) > 0 {
+		w.Header().Set(handler.WarningsHeader, strings.Join(storage.WarningStrings(warnings), ", "))
+	}
 
 	compressed := snappy.Encode(nil, data)
 	if _, err := w.Write(compressed); err != nil {
@@ -98,6 +114,62 @@ func (h *PromReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *PromReadHandler) serveStreamed(reqCtx context.Context, w http.ResponseWriter, r *prompb.ReadRequest, params *prometheus.RequestParams) {
+	logger := logging.WithContext(reqCtx)
+	queries := make([]*storage.FetchQuery, len(r.Queries))
+	for i, promQuery := range r.Queries {
+		query, err := h.fetchQuery(promQuery)
+		if err != nil {
+			handler.Error(w, err, http.StatusBadRequest)
+			return
+		}
+
+		queries[i] = query
+	}
+
+	ctx, cancel := context.WithTimeout(reqCtx, params.Timeout)
+	defer cancel()
+
+	// Detect clients closing connections, the queries are killed along with the stream
+	abortCh, closingCh := handler.CloseWatcher(ctx, w)
+	opts := &executor.EngineOptions{AbortCh: abortCh}
+
+	// NB: set before streaming since clients check the content type even when no series match
+	w.Header().Set("Content-Type", streamedContentType)
+	// Stores left out of a partial result are only known once every series is streamed, so they are
+	// reported in a trailer which must be declared before the first frame
+	w.Header().Set("Trailer", handler.WarningsHeader)
+	writer := newChunkedWriter(w)
+	warnings, err := h.streamChunks(ctx, writer, queries, opts, closingCh)
+	if err != nil {
+		logger.Error("unable to stream read results", zap.Any("error", err))
+		// Once a frame is written the status is sent, the client notices the truncated stream instead
+		if writer.frames == 0 {
+			handler.Error(w, err, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	if len(warnings) > 0 {
+		w.Header().Set(handler.WarningsHeader, strings.Join(storage.WarningStrings(warnings), ", "))
+	}
+}
+
+// fetchQuery converts a prometheus query, the hints get the configured lookback since prometheus does not send it
+func (h *PromReadHandler) fetchQuery(promQuery *prompb.Query) (*storage.FetchQuery, error) {
+	query, err := storage.PromReadQueryToM3(promQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Hints != nil {
+		query.Hints.Lookback = h.lookback
+	}
+
+	return query, nil
+}
+
 func (h *PromReadHandler) parseRequest(r *http.Request) (*prompb.ReadRequest, *handler.ParseError) {
 	reqBuf, err := prometheus.ParsePromCompressedRequest(r)
 	if err != nil {
@@ -112,39 +184,114 @@ func (h *PromReadHandler) parseRequest(r *http.Request) (*prompb.ReadRequest, *h
 	return &req, nil
 }
 
-func (h *PromReadHandler) read(reqCtx context.Context, w http.ResponseWriter, r *prompb.ReadRequest, params *prometheus.RequestParams) ([]*prompb.QueryResult, error) {
-	// TODO: Handle multi query use case
-	if len(r.Queries) != 1 {
-		return nil, fmt.Errorf("prometheus read endpoint currently only supports one query at a time")
-	}
-
+func (h *PromReadHandler) read(
+	reqCtx context.Context,
+	w http.ResponseWriter,
+	r *prompb.ReadRequest,
+	params *prometheus.RequestParams,
+) ([]*prompb.QueryResult, []storage.Warning, error) {
+	// All queries share the timeout and are cancelled on the first error
 	ctx, cancel := context.WithTimeout(reqCtx, params.Timeout)
 	defer cancel()
-	promQuery := r.Queries[0]
-	query, err := storage.PromReadQueryToM3(promQuery)
-	if err != nil {
-		return nil, err
-	}
-
-	// Results is closed by execute
-	results := make(chan *storage.QueryResult)
 
 	opts := &executor.EngineOptions{}
 	// Detect clients closing connections
 	abortCh, closingCh := handler.CloseWatcher(ctx, w)
 	opts.AbortCh = abortCh
 
-	go h.engine.Execute(ctx, query, opts, closingCh, results)
+	promResults := make([]*prompb.QueryResult, len(r.Queries))
+	warnings := make([][]storage.Warning, len(r.Queries))
+	requests := make([]execution.Request, len(r.Queries))
+	for i, promQuery := range r.Queries {
+		query, err := h.fetchQuery(promQuery)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		requests[i] = &readRequest{
+			engine:   h.engine,
+			store:    h.store,
+			query:    query,
+			opts:     opts,
+			closing:  closingCh,
+			results:  promResults,
+			warnings: warnings,
+			idx:      i,
+		}
+	}
+
+	if err := execution.ExecuteParallel(ctx, requests); err != nil {
+		return nil, nil, err
+	}
+
+	var partial []storage.Warning
+	for _, w := range warnings {
+		partial = append(partial, w...)
+	}
+
+	return promResults, partial, nil
+}
+
+// readRequest executes a single query of a read request, its result and the stores left out of it are
+// stored at the index of the query so results are returned in request order
+type readRequest struct {
+	engine   *executor.Engine
+	store    storage.Storage
+	query    *storage.FetchQuery
+	opts     *executor.EngineOptions
+	closing  <-chan bool
+	results  []*prompb.QueryResult
+	warnings [][]storage.Warning
+	idx      int
+}
+
+func (r *readRequest) Process(ctx context.Context) error {
+	// Raw series keep their original timestamps, only series downsampled for the hints go through the engine
+	if raw, ok := r.store.(storage.RawQuerier); ok && !r.query.Hints.CanDownsample() {
+		return r.processRaw(ctx, raw)
+	}
+
+	// Results is closed by execute
+	results := make(chan *storage.QueryResult)
+	go r.engine.Execute(ctx, r.query, r.opts, r.closing, results)
 
-	promResults := make([]*prompb.QueryResult, 0, 1)
+	var err error
+	promResult := &prompb.QueryResult{}
 	for result := range results {
+		// Keep draining so execute can finish
 		if result.Err != nil {
-			return nil, result.Err
+			err = result.Err
+			continue
 		}
 
+		r.warnings[r.idx] = append(r.warnings[r.idx], result.FetchResult.Warnings...)
 		promRes := storage.FetchResultToPromResult(result.FetchResult)
-		promResults = append(promResults, promRes)
+		promResult.Timeseries = append(promResult.Timeseries, promRes.Timeseries...)
+	}
+
+	r.results[r.idx] = promResult
+	return err
+}
+
+func (r *readRequest) processRaw(ctx context.Context, raw storage.RawQuerier) error {
+	promResult := &prompb.QueryResult{}
+	result, err := r.engine.ExecuteRaw(ctx, raw, r.query, r.closing, func(tags models.Tags, datapoints storage.DatapointIter) error {
+		samples, err := storage.DatapointsToPromSamples(datapoints)
+		if err != nil {
+			return err
+		}
+
+		promResult.Timeseries = append(promResult.Timeseries, &prompb.TimeSeries{
+			Labels:  storage.TagsToPromLabels(tags),
+			Samples: samples,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return promResults, nil
+	r.results[r.idx] = promResult
+	r.warnings[r.idx] = result.Warnings
+	return nil
 }
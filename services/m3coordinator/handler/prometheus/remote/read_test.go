@@ -33,10 +33,13 @@ import (
 
 	"github.com/m3db/m3coordinator/executor"
 	"github.com/m3db/m3coordinator/generated/proto/prompb"
+	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus"
 	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
 	"github.com/m3db/m3coordinator/test"
 	"github.com/m3db/m3coordinator/test/local"
+	"github.com/m3db/m3coordinator/ts"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/golang/mock/gomock"
@@ -115,7 +118,7 @@ func TestPromReadStorageWithFetchError(t *testing.T) {
 	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, true, fmt.Errorf("unable to get data"))
 	promRead := &PromReadHandler{engine: executor.NewEngine(storage)}
 	req := generatePromReadRequest()
-	_, err := promRead.read(context.TODO(), httptest.NewRecorder(), req, &prometheus.RequestParams{Timeout: time.Hour})
+	_, _, err := promRead.read(context.TODO(), httptest.NewRecorder(), req, &prometheus.RequestParams{Timeout: time.Hour})
 	require.NotNil(t, err, "unable to read from storage")
 }
 
@@ -155,3 +158,86 @@ func TestQueryKillOnTimeout(t *testing.T) {
 	require.NotNil(t, resp)
 	assert.Equal(t, resp.StatusCode, 500, "Status code not 500")
 }
+
+// delayedStorage answers a fetch with a single series tagged with the value of the first matcher,
+// after the delay for that value
+type delayedStorage struct {
+	storage.Storage
+	delays map[string]time.Duration
+	errs   map[string]error
+}
+
+func (s *delayedStorage) Fetch(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.FetchResult, error) {
+	value := query.TagMatchers[0].Value
+	time.Sleep(s.delays[value])
+	if err := s.errs[value]; err != nil {
+		return nil, err
+	}
+
+	values := ts.NewValues(ctx, 1000, 1)
+	values.SetValueAt(0, 1)
+	series := ts.NewSeries(ctx, value, query.Start, values, models.Tags{"eq": value})
+	return &storage.FetchResult{SeriesList: []*ts.Series{series}}, nil
+}
+
+func generateMultiQueryRequest(values ...string) *prompb.ReadRequest {
+	req := &prompb.ReadRequest{}
+	for _, value := range values {
+		query := *generatePromReadRequest().Queries[0]
+		query.Matchers = []*prompb.LabelMatcher{{Type: prompb.LabelMatcher_EQ, Name: "eq", Value: value}}
+		req.Queries = append(req.Queries, &query)
+	}
+
+	return req
+}
+
+func TestPromReadMultipleQueries(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &delayedStorage{
+		Storage: mock.NewMockStorage(),
+		// Earlier queries finish last
+		delays: map[string]time.Duration{"a": 30 * time.Millisecond, "b": 15 * time.Millisecond},
+	}
+
+	promRead := &PromReadHandler{engine: executor.NewEngine(store)}
+	results, _, err := promRead.read(context.TODO(), httptest.NewRecorder(), generateMultiQueryRequest("a", "b", "c"),
+		&prometheus.RequestParams{Timeout: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, value := range []string{"a", "b", "c"} {
+		require.Len(t, results[i].Timeseries, 1)
+		assert.Equal(t, []*prompb.Label{{Name: "eq", Value: value}}, results[i].Timeseries[0].Labels)
+	}
+}
+
+func TestPromReadMultipleQueriesError(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &delayedStorage{
+		Storage: mock.NewMockStorage(),
+		errs:    map[string]error{"b": fmt.Errorf("unable to get data")},
+	}
+
+	promRead := &PromReadHandler{engine: executor.NewEngine(store)}
+	_, _, err := promRead.read(context.TODO(), httptest.NewRecorder(), generateMultiQueryRequest("a", "b"),
+		&prometheus.RequestParams{Timeout: time.Minute})
+	assert.EqualError(t, err, "unable to get data")
+}
+
+func TestPromReadRawKeepsTimestamps(t *testing.T) {
+	logging.InitWithCores(nil)
+	start := time.Now().Truncate(time.Hour)
+	store := &rawStorage{Storage: mock.NewMockStorage(), start: start}
+	promRead := &PromReadHandler{engine: executor.NewEngine(store), store: store}
+	results, _, err := promRead.read(context.TODO(), httptest.NewRecorder(), generateMultiQueryRequest("a"),
+		&prometheus.RequestParams{Timeout: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Timeseries, 1)
+
+	samples := results[0].Timeseries[0].Samples
+	require.Len(t, samples, 3)
+	for i, sample := range samples {
+		assert.Equal(t, storage.TimeToTimestamp(start.Add(time.Duration(i)*time.Second)), sample.Timestamp)
+		assert.Equal(t, float64(i), sample.Value)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"net/http"
+	"sort"
+
+	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/generated/proto/prompb"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/prometheus/tsdb/chunkenc"
+)
+
+const (
+	// maxSamplesPerChunk matches the number of samples prometheus stores in a chunk
+	maxSamplesPerChunk = 120
+	// streamedContentType is the content type of a STREAMED_XOR_CHUNKS response
+	streamedContentType = "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse"
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// negotiateResponseType returns the first response type accepted by the client which the handler supports,
+// older clients do not send accepted types and only support samples
+func (h *PromReadHandler) negotiateResponseType(accepted []prompb.ReadRequest_ResponseType) prompb.ReadRequest_ResponseType {
+	_, canStream := h.store.(storage.RawQuerier)
+	for _, responseType := range accepted {
+		switch responseType {
+		case prompb.ReadRequest_SAMPLES:
+			return responseType
+		case prompb.ReadRequest_STREAMED_XOR_CHUNKS:
+			if canStream {
+				return responseType
+			}
+		}
+	}
+
+	return prompb.ReadRequest_SAMPLES
+}
+
+// streamChunks executes the queries one after the other through the engine, writing a frame for every series
+// as soon as its datapoints are re-encoded into XOR chunks, the stores left out of the streamed series are returned
+func (h *PromReadHandler) streamChunks(
+	ctx context.Context,
+	writer *chunkedWriter,
+	queries []*storage.FetchQuery,
+	opts *executor.EngineOptions,
+	closing <-chan bool,
+) ([]storage.Warning, error) {
+	raw, ok := h.store.(storage.RawQuerier)
+	if !ok {
+		return nil, errors.ErrNotImplemented
+	}
+
+	var warnings []storage.Warning
+
+	for i, query := range queries {
+		queryIndex := int64(i)
+		writeSeries := func(tags models.Tags, datapoints storage.DatapointIter) error {
+			chunks, err := encodeChunks(datapoints)
+			if err != nil {
+				return err
+			}
+
+			return writer.write(&prompb.ChunkedReadResponse{
+				ChunkedSeries: []*prompb.ChunkedSeries{{Labels: sortedLabels(tags), Chunks: chunks}},
+				QueryIndex:    queryIndex,
+			})
+		}
+
+		var (
+			partial []storage.Warning
+			err     error
+		)
+		if query.Hints.CanDownsample() {
+			partial, err = h.streamDownsampled(ctx, query, opts, closing, writeSeries)
+		} else {
+			var result storage.RawResult
+			result, err = h.engine.ExecuteRaw(ctx, raw, query, closing, writeSeries)
+			partial = result.Warnings
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		warnings = append(warnings, partial...)
+	}
+
+	return warnings, nil
+}
+
+// streamDownsampled fetches a query downsampled for its hints, series are only streamed once every series
+// is fetched since the store downsamples them as a whole
+func (h *PromReadHandler) streamDownsampled(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	opts *executor.EngineOptions,
+	closing <-chan bool,
+	fn storage.RawSeriesFn,
+) ([]storage.Warning, error) {
+	// Results is closed by execute
+	results := make(chan *storage.QueryResult)
+	go h.engine.Execute(ctx, query, opts, closing, results)
+
+	var (
+		warnings []storage.Warning
+		err      error
+	)
+	for result := range results {
+		// Keep draining so execute can finish
+		if err != nil {
+			continue
+		}
+
+		if result.Err != nil {
+			err = result.Err
+			continue
+		}
+
+		warnings = append(warnings, result.FetchResult.Warnings...)
+		for _, series := range result.FetchResult.SeriesList {
+			if err = fn(series.Tags, storage.SeriesToDatapointIter(series)); err != nil {
+				break
+			}
+		}
+	}
+
+	return warnings, err
+}
+
+// encodeChunks re-encodes datapoints into XOR chunks of at most maxSamplesPerChunk samples
+func encodeChunks(datapoints storage.DatapointIter) ([]*prompb.Chunk, error) {
+	var (
+		chunks     []*prompb.Chunk
+		chunk      *chunkenc.XORChunk
+		appender   chunkenc.Appender
+		minT, maxT int64
+	)
+
+	cut := func() {
+		if chunk != nil {
+			chunks = append(chunks, &prompb.Chunk{
+				MinTimeMs: minT,
+				MaxTimeMs: maxT,
+				Type:      prompb.Chunk_XOR,
+				Data:      chunk.Bytes(),
+			})
+		}
+	}
+
+	for datapoints.Next() {
+		dp := datapoints.Current()
+		t := storage.TimeToTimestamp(dp.Timestamp)
+		// NB: XOR chunks require strictly increasing timestamps
+		if chunk != nil && t <= maxT {
+			continue
+		}
+
+		if chunk == nil || chunk.NumSamples() == maxSamplesPerChunk {
+			cut()
+			chunk = chunkenc.NewXORChunk()
+			var err error
+			if appender, err = chunk.Appender(); err != nil {
+				return nil, err
+			}
+
+			minT = t
+		}
+
+		appender.Append(t, dp.Value)
+		maxT = t
+	}
+
+	if err := datapoints.Err(); err != nil {
+		return nil, err
+	}
+
+	cut()
+	return chunks, nil
+}
+
+// sortedLabels converts tags to labels sorted by name as prometheus expects for chunked series
+func sortedLabels(tags models.Tags) []*prompb.Label {
+	labels := storage.TagsToPromLabels(tags)
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+
+	return labels
+}
+
+// chunkedWriter writes delimited frames, each one is the uvarint size of the message, its big endian
+// CRC32 castagnoli checksum and then the message itself. Frames are flushed so the client can decode
+// them while the rest of the response is produced
+type chunkedWriter struct {
+	w      http.ResponseWriter
+	frames int
+}
+
+func newChunkedWriter(w http.ResponseWriter) *chunkedWriter {
+	return &chunkedWriter{w: w}
+}
+
+func (c *chunkedWriter) write(resp *prompb.ChunkedReadResponse) error {
+	data, err := resp.Marshal()
+	if err != nil {
+		return err
+	}
+
+	var header [binary.MaxVarintLen64 + crc32.Size]byte
+	n := binary.PutUvarint(header[:], uint64(len(data)))
+	binary.BigEndian.PutUint32(header[n:], crc32.Checksum(data, castagnoliTable))
+	n += crc32.Size
+	if _, err := c.w.Write(header[:n]); err != nil {
+		return err
+	}
+
+	if _, err := c.w.Write(data); err != nil {
+		return err
+	}
+
+	c.frames++
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/executor"
+	"github.com/m3db/m3coordinator/generated/proto/prompb"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
+	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/tsdb/chunkenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawStorage streams a series tagged with the value of the first matcher of each query
+type rawStorage struct {
+	storage.Storage
+	start    time.Time
+	err      error
+	warnings []storage.Warning
+	options  []*storage.FetchOptions
+}
+
+func (s *rawStorage) FetchRaw(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	s.options = append(s.options, options)
+	if s.err != nil {
+		return storage.RawResult{}, s.err
+	}
+
+	values := ts.NewValues(ctx, 1000, 3)
+	for i := 0; i < 3; i++ {
+		values.SetValueAt(i, float64(i))
+	}
+
+	tags := models.Tags{"eq": query.TagMatchers[0].Value, "a": "b"}
+	series := ts.NewSeries(ctx, tags.ID(), s.start, values, tags)
+	return storage.RawResult{Warnings: s.warnings}, fn(tags, storage.SeriesToDatapointIter(series))
+}
+
+// datapoints is a storage.DatapointIter over a slice
+type datapoints struct {
+	points []ts.Datapoint
+	idx    int
+}
+
+func (d *datapoints) Next() bool            { d.idx++; return d.idx <= len(d.points) }
+func (d *datapoints) Current() ts.Datapoint { return d.points[d.idx-1] }
+func (d *datapoints) Err() error            { return nil }
+
+func readFrame(r *bufio.Reader) (*prompb.ChunkedReadResponse, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var checksum [crc32.Size]byte
+	if _, err := io.ReadFull(r, checksum[:]); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	if crc32.Checksum(data, castagnoliTable) != binary.BigEndian.Uint32(checksum[:]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	resp := &prompb.ChunkedReadResponse{}
+	return resp, resp.Unmarshal(data)
+}
+
+func decodeChunk(t *testing.T, chunk *prompb.Chunk) ([]int64, []float64) {
+	require.Equal(t, prompb.Chunk_XOR, chunk.Type)
+	decoded, err := chunkenc.FromData(chunkenc.EncXOR, chunk.Data)
+	require.NoError(t, err)
+
+	var (
+		times  []int64
+		values []float64
+	)
+
+	iter := decoded.Iterator()
+	for iter.Next() {
+		tm, v := iter.At()
+		times = append(times, tm)
+		values = append(values, v)
+	}
+
+	require.NoError(t, iter.Err())
+	return times, values
+}
+
+func generateStreamedRequest(t *testing.T, values ...string) *http.Request {
+	promReq := generateMultiQueryRequest(values...)
+	promReq.AcceptedResponseTypes = []prompb.ReadRequest_ResponseType{prompb.ReadRequest_STREAMED_XOR_CHUNKS}
+	data, err := promReq.Marshal()
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", PromReadURL, bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	return req
+}
+
+func TestEncodeChunks(t *testing.T) {
+	start := time.Now().Truncate(time.Hour)
+	points := make([]ts.Datapoint, 0, 251)
+	for i := 0; i < 250; i++ {
+		points = append(points, ts.Datapoint{Timestamp: start.Add(time.Duration(i) * time.Second), Value: float64(i)})
+		if i == 10 {
+			// Duplicate timestamps are dropped
+			points = append(points, ts.Datapoint{Timestamp: start.Add(10 * time.Second), Value: 100})
+		}
+	}
+
+	chunks, err := encodeChunks(&datapoints{points: points})
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+
+	expected := 0
+	for i, numSamples := range []int{120, 120, 10} {
+		times, values := decodeChunk(t, chunks[i])
+		require.Len(t, values, numSamples)
+		assert.Equal(t, times[0], chunks[i].MinTimeMs)
+		assert.Equal(t, times[numSamples-1], chunks[i].MaxTimeMs)
+		for j, v := range values {
+			assert.Equal(t, float64(expected), v)
+			assert.Equal(t, storage.TimeToTimestamp(start.Add(time.Duration(expected)*time.Second)), times[j])
+			expected++
+		}
+	}
+}
+
+func TestNegotiateResponseType(t *testing.T) {
+	streamed := prompb.ReadRequest_STREAMED_XOR_CHUNKS
+	samples := prompb.ReadRequest_SAMPLES
+	raw := &PromReadHandler{store: &rawStorage{Storage: mock.NewMockStorage()}}
+	assert.Equal(t, samples, raw.negotiateResponseType(nil))
+	assert.Equal(t, streamed, raw.negotiateResponseType([]prompb.ReadRequest_ResponseType{streamed, samples}))
+	assert.Equal(t, samples, raw.negotiateResponseType([]prompb.ReadRequest_ResponseType{samples, streamed}))
+
+	// Fall back to samples when the store cannot stream raw series
+	sampled := &PromReadHandler{store: mock.NewMockStorage()}
+	assert.Equal(t, samples, sampled.negotiateResponseType([]prompb.ReadRequest_ResponseType{streamed}))
+}
+
+func TestPromReadStreamedChunks(t *testing.T) {
+	logging.InitWithCores(nil)
+	start := time.Now().Truncate(time.Hour)
+	store := &rawStorage{Storage: mock.NewMockStorage(), start: start}
+	promRead := &PromReadHandler{engine: executor.NewEngine(store), store: store}
+	recorder := httptest.NewRecorder()
+	promRead.ServeHTTP(recorder, generateStreamedRequest(t, "x", "y"))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, streamedContentType, recorder.Header().Get("Content-Type"))
+
+	reader := bufio.NewReader(recorder.Body)
+	for i, value := range []string{"x", "y"} {
+		resp, err := readFrame(reader)
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), resp.QueryIndex)
+		require.Len(t, resp.ChunkedSeries, 1)
+		series := resp.ChunkedSeries[0]
+		assert.Equal(t, []*prompb.Label{{Name: "a", Value: "b"}, {Name: "eq", Value: value}}, series.Labels)
+		require.Len(t, series.Chunks, 1)
+		times, values := decodeChunk(t, series.Chunks[0])
+		assert.Equal(t, []float64{0, 1, 2}, values)
+		assert.Equal(t, storage.TimeToTimestamp(start), times[0])
+	}
+
+	_, err := readFrame(reader)
+	assert.Equal(t, io.EOF, err)
+
+	// Queries are tracked by the engine so they are killed along with the request
+	require.Len(t, store.options, 2)
+	for _, options := range store.options {
+		assert.NotNil(t, options.KillChan)
+	}
+}
+
+func TestPromReadStreamedChunksError(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &rawStorage{Storage: mock.NewMockStorage(), err: fmt.Errorf("unable to get data")}
+	promRead := &PromReadHandler{engine: executor.NewEngine(store), store: store}
+	recorder := httptest.NewRecorder()
+	promRead.ServeHTTP(recorder, generateStreamedRequest(t, "x"))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestPromReadStreamedChunksWarnings(t *testing.T) {
+	logging.InitWithCores(nil)
+	store := &rawStorage{
+		Storage:  mock.NewMockStorage(),
+		start:    time.Now().Truncate(time.Hour),
+		warnings: []storage.Warning{{Store: "remote", Message: "unavailable"}},
+	}
+	promRead := &PromReadHandler{engine: executor.NewEngine(store), store: store}
+	recorder := httptest.NewRecorder()
+	// The store is left out of both queries but reported once
+	promRead.ServeHTTP(recorder, generateStreamedRequest(t, "x", "y"))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	resp := recorder.Result()
+	_, err := readFrame(bufio.NewReader(resp.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "remote: unavailable", resp.Trailer.Get(handler.WarningsHeader))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/namespace"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/placement"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/api"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/native"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/remote"
 	"github.com/m3db/m3coordinator/storage"
@@ -79,11 +80,18 @@ func NewHandler(storage storage.Storage, engine *executor.Engine, clusterClient
 func (h *Handler) RegisterRoutes() error {
 	logged := logging.WithResponseTimeLogging
 
-	h.Router.HandleFunc(remote.PromReadURL, logged(remote.NewPromReadHandler(h.engine)).ServeHTTP).Methods("POST")
+	h.Router.HandleFunc(remote.PromReadURL, logged(remote.NewPromReadHandler(h.engine, h.storage, h.config.RemoteRead.LookbackDuration)).ServeHTTP).Methods("POST")
 	h.Router.HandleFunc(remote.PromWriteURL, logged(remote.NewPromWriteHandler(h.storage)).ServeHTTP).Methods("POST")
 	h.Router.HandleFunc(native.PromReadURL, logged(native.NewPromReadHandler(h.engine)).ServeHTTP).Methods("GET")
 	h.Router.HandleFunc(handler.SearchURL, logged(handler.NewSearchHandler(h.storage)).ServeHTTP).Methods("POST")
 
+	// Prometheus HTTP API, so Grafana can use the coordinator as a Prometheus datasource
+	h.Router.HandleFunc(api.QueryURL, logged(api.NewQueryHandler(h.engine)).ServeHTTP).Methods("GET", "POST")
+	h.Router.HandleFunc(api.QueryRangeURL, logged(api.NewQueryRangeHandler(h.engine)).ServeHTTP).Methods("GET", "POST")
+	h.Router.HandleFunc(api.SeriesURL, logged(api.NewSeriesHandler(h.storage)).ServeHTTP).Methods("GET", "POST")
+	h.Router.HandleFunc(api.LabelNamesURL, logged(api.NewLabelNamesHandler(h.storage)).ServeHTTP).Methods("GET")
+	h.Router.HandleFunc(api.LabelValuesURL, logged(api.NewLabelValuesHandler(h.storage)).ServeHTTP).Methods("GET")
+
 	h.registerProfileEndpoints()
 
 	if h.clusterClient != nil {
@@ -27,6 +27,7 @@ import (
 
 	"github.com/m3db/m3coordinator/executor"
 	"github.com/m3db/m3coordinator/services/m3coordinator/config"
+	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/api"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/native"
 	"github.com/m3db/m3coordinator/services/m3coordinator/handler/prometheus/remote"
 	"github.com/m3db/m3coordinator/test/local"
@@ -97,3 +98,25 @@ func TestPromNativeReadPost(t *testing.T) {
 	h.Router.ServeHTTP(res, req)
 	require.Equal(t, res.Code, http.StatusMethodNotAllowed, "POST method not defined")
 }
+
+func TestPromQueryAPIRoutes(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	storage, _ := local.NewStorageAndSession(ctrl)
+	h, err := NewHandler(storage, executor.NewEngine(storage), nil, config.Configuration{})
+	require.NoError(t, err, "unable to setup handler")
+	require.NoError(t, h.RegisterRoutes(), "unable to register routes")
+
+	for _, method := range []string{"GET", "POST"} {
+		req, _ := http.NewRequest(method, api.QueryRangeURL, nil)
+		res := httptest.NewRecorder()
+		h.Router.ServeHTTP(res, req)
+		require.Equal(t, http.StatusBadRequest, res.Code, "missing query")
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/label/in-valid/values", nil)
+	res := httptest.NewRecorder()
+	h.Router.ServeHTTP(res, req)
+	require.Equal(t, http.StatusBadRequest, res.Code, "invalid label name")
+}
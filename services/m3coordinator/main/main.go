@@ -31,13 +31,10 @@ import (
 	"time"
 
 	"github.com/m3db/m3coordinator/executor"
-	"github.com/m3db/m3coordinator/policy/filter"
 	"github.com/m3db/m3coordinator/services/m3coordinator/config"
 	"github.com/m3db/m3coordinator/services/m3coordinator/httpd"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/storage/fanout"
-	"github.com/m3db/m3coordinator/storage/local"
-	"github.com/m3db/m3coordinator/storage/remote"
 	"github.com/m3db/m3coordinator/stores/m3db"
 	tsdbRemote "github.com/m3db/m3coordinator/tsdb/remote"
 	"github.com/m3db/m3coordinator/util/logging"
@@ -53,8 +50,6 @@ import (
 )
 
 var (
-	namespace      = "metrics"
-	resolution     = time.Minute
 	configLoadOpts = xconfig.Options{
 		DisableUnmarshalStrict: false,
 		DisableValidate:        false,
@@ -66,7 +61,6 @@ type m3config struct {
 	listenAddress        string
 	rpcEnabled           bool
 	rpcAddress           string
-	remotes              []string
 	maxConcurrentQueries int
 	queryTimeout         time.Duration
 }
@@ -107,7 +101,7 @@ func main() {
 
 	session := m3db.NewAsyncSession(m3dbClient, nil)
 
-	fanoutStorage, storageCleanup := setupStorages(logger, session, flags)
+	fanoutStorage, storageCleanup := setupStorages(logger, session, flags, cfg.Storages)
 	defer storageCleanup()
 
 	handler, err := httpd.NewHandler(fanoutStorage, executor.NewEngine(fanoutStorage), clusterClient, cfg)
@@ -154,9 +148,6 @@ func parseFlags(logger *zap.Logger) *m3config {
 	a.Flag("rpc.port", "Address which the remote gRPC server will listen on for outbound connections.").
 		Default("0.0.0.0:7288").StringVar(&cfg.rpcAddress)
 
-	a.Flag("rpc.remotes", "Address which the remote gRPC server will listen on for outbound connections.").
-		Default("[]").StringsVar(&cfg.remotes)
-
 	_, err := a.Parse(os.Args[1:])
 	if err != nil {
 		logger.Error("unable to parse command line arguments", zap.Any("error", err))
@@ -183,9 +174,13 @@ func startGrpcServer(logger *zap.Logger, storage storage.Storage, flags *m3confi
 }
 
 // Setup all the storages
-func setupStorages(logger *zap.Logger, session client.Session, flags *m3config) (storage.Storage, func()) {
+func setupStorages(logger *zap.Logger, session client.Session, flags *m3config, cfg config.StoragesConfiguration) (storage.Storage, func()) {
 	cleanup := func() {}
-	localStorage := local.NewStorage(session, namespace, resolution)
+	localStorage, err := cfg.Local.NewStorage(session)
+	if err != nil {
+		logger.Fatal("unable to create local storage", zap.Any("error", err))
+	}
+
 	stores := []storage.Storage{localStorage}
 	if flags.rpcEnabled {
 		logger.Info("rpc enabled")
@@ -193,14 +188,22 @@ func setupStorages(logger *zap.Logger, session client.Session, flags *m3config)
 		cleanup = func() {
 			server.GracefulStop()
 		}
-		if len(flags.remotes) > 0 {
-			client, err := tsdbRemote.NewGrpcClient(flags.remotes)
-			if err != nil {
-				logger.Fatal("unable to start remote clients for addresses", zap.Any("error", err))
-			}
-			stores = append(stores, remote.NewStorage(client))
+	}
+
+	for _, remoteCfg := range cfg.Remotes {
+		remoteStorage, err := remoteCfg.NewStorage()
+		if err != nil {
+			logger.Fatal("unable to start remote clients for addresses", zap.Strings("addresses", remoteCfg.Addresses), zap.Any("error", err))
 		}
+
+		stores = append(stores, remoteStorage)
+	}
+
+	fetchFilter, writeFilter, err := cfg.Filters.NewFilters()
+	if err != nil {
+		logger.Fatal("unable to create storage filters", zap.Any("error", err))
 	}
-	fanoutStorage := fanout.NewStorage(stores, filter.LocalOnly, filter.LocalOnly)
+
+	fanoutStorage := fanout.NewStorageWithOptions(stores, fetchFilter, writeFilter, cfg.Fanout.Options())
 	return fanoutStorage, cleanup
 }
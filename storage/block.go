@@ -14,23 +14,44 @@ type Block interface {
 	SeriesIter() SeriesIter
 	SeriesMeta() []SeriesMeta
 	StepMeta() []StepMeta
+	// Close frees up any resources held by the block
+	Close() error
 }
 
 // SeriesMeta is metadata data for the series
 type SeriesMeta struct {
 	Tags models.Tags
+	Name string
 }
 
 // StepMeta is metadata data for a single time step
 type StepMeta struct {
 }
 
-// Bounds are the time bounds
-// nolint: structcheck, megacheck
+// Bounds are the time bounds, both start and end are inclusive steps
 type Bounds struct {
-	start    time.Time
-	end      time.Time
-	stepSize time.Duration
+	Start    time.Time
+	End      time.Time
+	StepSize time.Duration
+}
+
+// Steps calculates the number of steps for the bounds
+func (b Bounds) Steps() int {
+	if b.StepSize <= 0 || b.End.Before(b.Start) {
+		return 0
+	}
+
+	return int(b.End.Sub(b.Start)/b.StepSize) + 1
+}
+
+// TimeForIndex returns the start time for a given index
+func (b Bounds) TimeForIndex(idx int) time.Time {
+	return b.Start.Add(time.Duration(idx) * b.StepSize)
+}
+
+// Equals is true if the bounds are the same
+func (b Bounds) Equals(other Bounds) bool {
+	return b.Start.Equal(other.Start) && b.End.Equal(other.End) && b.StepSize == other.StepSize
 }
 
 // SeriesIter iterates through a CompressedSeriesIterator horizontally
@@ -51,6 +72,18 @@ type Step interface {
 	Values() []float64
 }
 
+// BlockBuilder builds a new block
+type BlockBuilder interface {
+	// AppendValue adds a value to the column at the index
+	AppendValue(idx int, value float64) error
+	// AppendValues adds a slice of values to the column at the index
+	AppendValues(idx int, values []float64) error
+	// AddCols adds the given number of new columns
+	AddCols(num int) error
+	// Build builds the block, every column must hold a value per series
+	Build() (Block, error)
+}
+
 // BlockMetadata is metadata for a block
 type BlockMetadata struct {
 	Bounds Bounds
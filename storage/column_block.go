@@ -0,0 +1,225 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3coordinator/ts"
+)
+
+// columnPool reuses the columns, and their values, of closed blocks across queries
+var columnPool = sync.Pool{
+	New: func() interface{} {
+		return &column{}
+	},
+}
+
+// column holds the values of every series for a single step
+type column struct {
+	values []float64
+}
+
+func newColumns(num int) []*column {
+	columns := make([]*column, num)
+	for i := range columns {
+		col := columnPool.Get().(*column)
+		col.values = col.values[:0]
+		columns[i] = col
+	}
+
+	return columns
+}
+
+// columnBlock is a step aligned block which keeps the values of each step together
+type columnBlock struct {
+	meta       BlockMetadata
+	seriesMeta []SeriesMeta
+	columns    []*column
+}
+
+func (c *columnBlock) Meta() BlockMetadata {
+	return c.meta
+}
+
+func (c *columnBlock) SeriesMeta() []SeriesMeta {
+	return c.seriesMeta
+}
+
+func (c *columnBlock) StepMeta() []StepMeta {
+	return make([]StepMeta, len(c.columns))
+}
+
+func (c *columnBlock) StepIter() StepIter {
+	return &colBlockStepIter{block: c, idx: -1}
+}
+
+func (c *columnBlock) SeriesIter() SeriesIter {
+	return &colBlockSeriesIter{block: c, idx: -1}
+}
+
+// Close returns the columns to the pool, the block must not be used afterwards
+func (c *columnBlock) Close() error {
+	for _, col := range c.columns {
+		columnPool.Put(col)
+	}
+
+	c.columns = nil
+	return nil
+}
+
+type colBlockStepIter struct {
+	block *columnBlock
+	idx   int
+}
+
+func (it *colBlockStepIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.block.columns)
+}
+
+// Current returns the step, its values are owned by the block and must not be modified
+func (it *colBlockStepIter) Current() Step {
+	return step{
+		time:   it.block.meta.Bounds.TimeForIndex(it.idx),
+		values: it.block.columns[it.idx].values,
+	}
+}
+
+type colBlockSeriesIter struct {
+	block *columnBlock
+	idx   int
+}
+
+func (it *colBlockSeriesIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.block.seriesMeta)
+}
+
+func (it *colBlockSeriesIter) Current() ts.Series {
+	bounds := it.block.meta.Bounds
+	meta := it.block.seriesMeta[it.idx]
+	vals := ts.NewValues(context.Background(), int(bounds.StepSize/time.Millisecond), len(it.block.columns))
+	for i, col := range it.block.columns {
+		vals.SetValueAt(i, col.values[it.idx])
+	}
+
+	return *ts.NewSeries(context.Background(), meta.Name, bounds.Start, vals, meta.Tags)
+}
+
+type step struct {
+	time   time.Time
+	values []float64
+}
+
+func (s step) Time() time.Time {
+	return s.time
+}
+
+func (s step) Values() []float64 {
+	return s.values
+}
+
+// columnBlockBuilder builds a column block, each column must end up with a value per series
+type columnBlockBuilder struct {
+	block *columnBlock
+}
+
+// NewColumnBlockBuilder creates a builder with a column for every step in the bounds
+func NewColumnBlockBuilder(meta BlockMetadata, seriesMeta []SeriesMeta) BlockBuilder {
+	return &columnBlockBuilder{
+		block: &columnBlock{
+			meta:       meta,
+			seriesMeta: seriesMeta,
+			columns:    newColumns(meta.Bounds.Steps()),
+		},
+	}
+}
+
+func (b *columnBlockBuilder) AppendValue(idx int, value float64) error {
+	columns := b.block.columns
+	if idx < 0 || idx >= len(columns) {
+		return fmt.Errorf("idx out of range for append: %d", idx)
+	}
+
+	columns[idx].values = append(columns[idx].values, value)
+	return nil
+}
+
+func (b *columnBlockBuilder) AppendValues(idx int, values []float64) error {
+	columns := b.block.columns
+	if idx < 0 || idx >= len(columns) {
+		return fmt.Errorf("idx out of range for append: %d", idx)
+	}
+
+	columns[idx].values = append(columns[idx].values, values...)
+	return nil
+}
+
+func (b *columnBlockBuilder) AddCols(num int) error {
+	if num < 0 {
+		return fmt.Errorf("cannot add a negative number of columns: %d", num)
+	}
+
+	b.block.columns = append(b.block.columns, newColumns(num)...)
+	return nil
+}
+
+func (b *columnBlockBuilder) Build() (Block, error) {
+	numSeries := len(b.block.seriesMeta)
+	for i, col := range b.block.columns {
+		if len(col.values) != numSeries {
+			// The block is never returned so its columns go back to the pool
+			b.block.Close()
+			return nil, fmt.Errorf("column %d has %d values but the block has %d series", i, len(col.values), numSeries)
+		}
+	}
+
+	return b.block, nil
+}
+
+// NewSeriesBlock creates a column block from series values, values has an entry per series with a value for every step in the bounds
+func NewSeriesBlock(meta BlockMetadata, seriesMeta []SeriesMeta, values [][]float64) (Block, error) {
+	if len(seriesMeta) != len(values) {
+		return nil, fmt.Errorf("series metadata and values mismatch, %d - %d", len(seriesMeta), len(values))
+	}
+
+	steps := meta.Bounds.Steps()
+	for i, v := range values {
+		if len(v) != steps {
+			return nil, fmt.Errorf("series %d has %d values but bounds have %d steps", i, len(v), steps)
+		}
+	}
+
+	builder := NewColumnBlockBuilder(meta, seriesMeta)
+	for _, v := range values {
+		for idx, value := range v {
+			if err := builder.AppendValue(idx, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return builder.Build()
+}
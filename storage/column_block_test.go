@@ -0,0 +1,215 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundsSteps(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, 1, Bounds{Start: now, End: now, StepSize: time.Minute}.Steps())
+	assert.Equal(t, 3, Bounds{Start: now, End: now.Add(2 * time.Minute), StepSize: time.Minute}.Steps())
+	assert.Equal(t, 2, Bounds{Start: now, End: now.Add(90 * time.Second), StepSize: time.Minute}.Steps())
+	assert.Equal(t, 0, Bounds{Start: now, End: now.Add(-time.Minute), StepSize: time.Minute}.Steps())
+	assert.Equal(t, 0, Bounds{Start: now, End: now}.Steps())
+}
+
+func TestNewSeriesBlock(t *testing.T) {
+	now := time.Now()
+	bounds := Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	seriesMeta := []SeriesMeta{
+		{Name: "foo", Tags: models.Tags{"a": "b"}},
+		{Name: "bar", Tags: models.Tags{"c": "d"}},
+	}
+
+	block, err := NewSeriesBlock(BlockMetadata{Bounds: bounds}, seriesMeta, [][]float64{{1, 2}, {3, 4}})
+	require.NoError(t, err)
+	assert.Equal(t, seriesMeta, block.SeriesMeta())
+	assert.Len(t, block.StepMeta(), 2)
+
+	steps := block.StepIter()
+	require.True(t, steps.Next())
+	assert.Equal(t, now, steps.Current().Time())
+	assert.Equal(t, []float64{1, 3}, steps.Current().Values())
+	require.True(t, steps.Next())
+	assert.Equal(t, now.Add(time.Minute), steps.Current().Time())
+	assert.Equal(t, []float64{2, 4}, steps.Current().Values())
+	assert.False(t, steps.Next())
+
+	series := block.SeriesIter()
+	require.True(t, series.Next())
+	current := series.Current()
+	assert.Equal(t, "foo", current.Name())
+	assert.Equal(t, models.Tags{"a": "b"}, current.Tags)
+	assert.Equal(t, 60000, current.MillisPerStep())
+	assert.Equal(t, 2.0, current.ValueAt(1))
+	require.True(t, series.Next())
+	current = series.Current()
+	assert.Equal(t, "bar", current.Name())
+	assert.False(t, series.Next())
+}
+
+func TestNewSeriesBlockMismatch(t *testing.T) {
+	now := time.Now()
+	meta := BlockMetadata{Bounds: Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}}
+	_, err := NewSeriesBlock(meta, []SeriesMeta{{Name: "foo"}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewSeriesBlock(meta, []SeriesMeta{{Name: "foo"}}, [][]float64{{1}})
+	assert.Error(t, err)
+}
+
+func TestColumnBlockBuilder(t *testing.T) {
+	now := time.Now()
+	bounds := Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	seriesMeta := []SeriesMeta{{Name: "foo"}, {Name: "bar"}}
+
+	builder := NewColumnBlockBuilder(BlockMetadata{Bounds: bounds}, seriesMeta)
+	require.NoError(t, builder.AppendValues(0, []float64{1, 2}))
+	require.NoError(t, builder.AppendValue(1, 3))
+	require.NoError(t, builder.AppendValue(1, 4))
+	assert.Error(t, builder.AppendValue(2, 5))
+	assert.Error(t, builder.AppendValues(-1, []float64{5}))
+	assert.Error(t, builder.AddCols(-1))
+
+	block, err := builder.Build()
+	require.NoError(t, err)
+	steps := block.StepIter()
+	require.True(t, steps.Next())
+	assert.Equal(t, []float64{1, 2}, steps.Current().Values())
+	require.True(t, steps.Next())
+	assert.Equal(t, []float64{3, 4}, steps.Current().Values())
+	assert.False(t, steps.Next())
+
+	series := block.SeriesIter()
+	require.True(t, series.Next())
+	current := series.Current()
+	assert.Equal(t, 3.0, current.ValueAt(1))
+	require.True(t, series.Next())
+	current = series.Current()
+	assert.Equal(t, 2.0, current.ValueAt(0))
+	assert.False(t, series.Next())
+	assert.NoError(t, block.Close())
+}
+
+func TestColumnBlockBuilderAddCols(t *testing.T) {
+	now := time.Now()
+	builder := NewColumnBlockBuilder(BlockMetadata{Bounds: Bounds{Start: now, End: now, StepSize: time.Minute}}, []SeriesMeta{{}})
+	require.NoError(t, builder.AddCols(2))
+	for idx := 0; idx < 3; idx++ {
+		require.NoError(t, builder.AppendValue(idx, 7))
+	}
+
+	block, err := builder.Build()
+	require.NoError(t, err)
+	assert.Len(t, block.StepMeta(), 3)
+}
+
+func TestColumnBlockBuilderShortColumn(t *testing.T) {
+	now := time.Now()
+	bounds := Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	builder := NewColumnBlockBuilder(BlockMetadata{Bounds: bounds}, []SeriesMeta{{}, {}})
+	require.NoError(t, builder.AppendValues(0, []float64{1, 2}))
+	require.NoError(t, builder.AppendValue(1, 3))
+
+	_, err := builder.Build()
+	assert.EqualError(t, err, "column 1 has 1 values but the block has 2 series")
+}
+
+func TestColumnBlockReusesMemory(t *testing.T) {
+	now := time.Now()
+	meta := BlockMetadata{Bounds: Bounds{Start: now, End: now, StepSize: time.Minute}}
+
+	builder := NewColumnBlockBuilder(meta, []SeriesMeta{{}})
+	require.NoError(t, builder.AppendValue(0, 1))
+	block, err := builder.Build()
+	require.NoError(t, err)
+	require.NoError(t, block.Close())
+
+	// Reused columns start out empty
+	builder = NewColumnBlockBuilder(meta, []SeriesMeta{{}})
+	require.NoError(t, builder.AppendValue(0, 2))
+	block, err = builder.Build()
+	require.NoError(t, err)
+	steps := block.StepIter()
+	require.True(t, steps.Next())
+	assert.Equal(t, []float64{2}, steps.Current().Values())
+}
+
+func newBenchmarkBlock(b *testing.B, numSeries, numSteps int) Block {
+	now := time.Now()
+	bounds := Bounds{Start: now, End: now.Add(time.Duration(numSteps-1) * time.Second), StepSize: time.Second}
+	builder := NewColumnBlockBuilder(BlockMetadata{Bounds: bounds}, make([]SeriesMeta, numSeries))
+	for i := 0; i < numSteps; i++ {
+		for j := 0; j < numSeries; j++ {
+			if err := builder.AppendValue(i, float64(j)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	block, err := builder.Build()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return block
+}
+
+func BenchmarkColumnBlockStepIteration(b *testing.B) {
+	block := newBenchmarkBlock(b, 1000, 720)
+	defer block.Close()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sum := 0.0
+		iter := block.StepIter()
+		for iter.Next() {
+			for _, v := range iter.Current().Values() {
+				sum += v
+			}
+		}
+	}
+}
+
+func BenchmarkColumnBlockSeriesIteration(b *testing.B) {
+	block := newBenchmarkBlock(b, 1000, 720)
+	defer block.Close()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sum := 0.0
+		iter := block.SeriesIter()
+		for iter.Next() {
+			series := iter.Current()
+			for i := 0; i < series.Len(); i++ {
+				sum += series.ValueAt(i)
+			}
+		}
+	}
+}
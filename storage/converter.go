@@ -22,12 +22,15 @@ package storage
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/m3db/m3coordinator/generated/proto/prompb"
 	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/ts"
 
+	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 )
 
@@ -58,6 +61,23 @@ func PromLabelsToM3Tags(labels []*prompb.Label) models.Tags {
 	return tags
 }
 
+// TagsToIdentTagIterator converts coordinator tags to an ident tag iterator,
+// ordered by tag name so that M3DB sees a deterministic tag ordering
+func TagsToIdentTagIterator(tags models.Tags) ident.TagIterator {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	identTags := make(ident.Tags, 0, len(tags))
+	for _, name := range names {
+		identTags = append(identTags, ident.StringTag(name, tags[name]))
+	}
+
+	return ident.NewTagSliceIterator(identTags)
+}
+
 // PromSamplesToM3Datapoints converts Prometheus samples to M3 datapoints
 func PromSamplesToM3Datapoints(samples []*prompb.Sample) ts.Datapoints {
 	datapoints := make(ts.Datapoints, 0, len(samples))
@@ -80,9 +100,28 @@ func PromReadQueryToM3(query *prompb.Query) (*FetchQuery, error) {
 		TagMatchers: tagMatchers,
 		Start:       TimestampToTime(query.StartTimestampMs),
 		End:         TimestampToTime(query.EndTimestampMs),
+		Hints:       PromReadHintsToM3(query.Hints),
 	}, nil
 }
 
+// PromReadHintsToM3 converts prometheus read hints to fetch hints, older prometheus versions
+// do not send hints in which case nil is returned
+func PromReadHintsToM3(hints *prompb.ReadHints) *FetchHints {
+	if hints == nil {
+		return nil
+	}
+
+	return &FetchHints{
+		Start:    TimestampToTime(hints.StartMs),
+		End:      TimestampToTime(hints.EndMs),
+		Step:     time.Duration(hints.StepMs) * time.Millisecond,
+		Func:     hints.Func,
+		Grouping: hints.Grouping,
+		By:       hints.By,
+		Range:    time.Duration(hints.RangeMs) * time.Millisecond,
+	}
+}
+
 // PromMatchersToM3 converts prometheus label matchers to m3 matchers
 func PromMatchersToM3(matchers []*prompb.LabelMatcher) (models.Matchers, error) {
 	tagMatchers := make(models.Matchers, len(matchers))
@@ -166,14 +205,58 @@ func TagsToPromLabels(tags models.Tags) []*prompb.Label {
 	return labels
 }
 
-// SeriesToPromSamples series datapoints to prometheus samples
+// SeriesToPromSamples series datapoints to prometheus samples, steps without a value are skipped
 func SeriesToPromSamples(series *ts.Series) []*prompb.Sample {
-	samples := make([]*prompb.Sample, series.Len())
+	samples := make([]*prompb.Sample, 0, series.Len())
 	for i := 0; i < series.Len(); i++ {
-		samples[i] = &prompb.Sample{
-			Timestamp: series.StartTimeForStep(i).UnixNano() / int64(time.Millisecond),
-			Value:     series.ValueAt(i),
+		value := series.ValueAt(i)
+		if math.IsNaN(value) {
+			continue
 		}
+
+		samples = append(samples, &prompb.Sample{
+			Timestamp: series.StartTimeForStep(i).UnixNano() / int64(time.Millisecond),
+			Value:     value,
+		})
 	}
 	return samples
 }
+
+// DatapointsToPromSamples converts datapoints to prometheus samples keeping their original timestamps
+func DatapointsToPromSamples(datapoints DatapointIter) ([]*prompb.Sample, error) {
+	samples := make([]*prompb.Sample, 0)
+	for datapoints.Next() {
+		dp := datapoints.Current()
+		samples = append(samples, &prompb.Sample{Timestamp: TimeToTimestamp(dp.Timestamp), Value: dp.Value})
+	}
+
+	return samples, datapoints.Err()
+}
+
+// SeriesToDatapointIter iterates over the datapoints of a series, steps without a value are skipped
+func SeriesToDatapointIter(series *ts.Series) DatapointIter {
+	return &seriesDatapointIter{series: series, idx: -1}
+}
+
+type seriesDatapointIter struct {
+	series *ts.Series
+	idx    int
+}
+
+func (it *seriesDatapointIter) Next() bool {
+	for it.idx++; it.idx < it.series.Len(); it.idx++ {
+		if !math.IsNaN(it.series.ValueAt(it.idx)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (it *seriesDatapointIter) Current() ts.Datapoint {
+	return ts.Datapoint{Timestamp: it.series.StartTimeForStep(it.idx), Value: it.series.ValueAt(it.idx)}
+}
+
+func (it *seriesDatapointIter) Err() error {
+	return nil
+}
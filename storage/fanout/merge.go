@@ -0,0 +1,460 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+)
+
+// ConflictPolicy decides which value is kept when several stores return a replica of the same series
+type ConflictPolicy int
+
+const (
+	// PreferLocal keeps the values of local stores over those of remote stores
+	PreferLocal ConflictPolicy = iota
+	// PreferNewest keeps the values of the replica holding the most recent datapoint
+	PreferNewest
+	// Average averages the values of every replica
+	Average
+)
+
+var validConflictPolicies = []ConflictPolicy{PreferLocal, PreferNewest, Average}
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case PreferLocal:
+		return "prefer_local"
+	case PreferNewest:
+		return "prefer_newest"
+	case Average:
+		return "average"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a conflict policy from its name, local values are preferred by default
+func (p *ConflictPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		*p = PreferLocal
+		return nil
+	}
+
+	for _, valid := range validConflictPolicies {
+		if str == valid.String() {
+			*p = valid
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid conflict policy %s, valid policies are: %v", str, validConflictPolicies)
+}
+
+// seriesKey identifies a series across stores by its tags, series without tags are identified by name
+func seriesKey(name string, tags models.Tags) string {
+	if len(tags) == 0 {
+		return name
+	}
+
+	return tags.ID()
+}
+
+// replica is the copy of a series returned by a single store
+type replica struct {
+	start  time.Time
+	step   time.Duration
+	values []float64
+	local  bool
+}
+
+// newest is the time of the last datapoint of the replica, or the zero time when it has none
+func (r replica) newest() time.Time {
+	for i := len(r.values) - 1; i >= 0; i-- {
+		if !math.IsNaN(r.values[i]) {
+			return r.start.Add(time.Duration(i) * r.step)
+		}
+	}
+
+	return time.Time{}
+}
+
+// order returns the indices of the replicas from the most to the least preferred, replicas which
+// are equally preferred keep the order they were received in
+func (p ConflictPolicy) order(replicas []replica) []int {
+	return p.orderBy(len(replicas), func(i int) bool {
+		return replicas[i].local
+	}, func(i int) time.Time {
+		return replicas[i].newest()
+	})
+}
+
+// orderBy orders n replicas given whether each one is local and the time of its last datapoint
+func (p ConflictPolicy) orderBy(n int, local func(i int) bool, newest func(i int) time.Time) []int {
+	ordered := make([]int, n)
+	for i := range ordered {
+		ordered[i] = i
+	}
+
+	switch p {
+	case PreferLocal:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return local(ordered[i]) && !local(ordered[j])
+		})
+	case PreferNewest:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return newest(ordered[i]).After(newest(ordered[j]))
+		})
+	}
+
+	return ordered
+}
+
+// merge combines step aligned replicas of a series, steps missing from the preferred replica are
+// filled in from the next one
+func (p ConflictPolicy) merge(replicas []replica) []float64 {
+	merged := make([]float64, len(replicas[0].values))
+	if p == Average {
+		for i := range merged {
+			sum, count := 0.0, 0
+			for _, r := range replicas {
+				if !math.IsNaN(r.values[i]) {
+					sum += r.values[i]
+					count++
+				}
+			}
+
+			merged[i] = math.NaN()
+			if count > 0 {
+				merged[i] = sum / float64(count)
+			}
+		}
+
+		return merged
+	}
+
+	ordered := p.order(replicas)
+	for i := range merged {
+		merged[i] = math.NaN()
+		for _, idx := range ordered {
+			if v := replicas[idx].values[i]; !math.IsNaN(v) {
+				merged[i] = v
+				break
+			}
+		}
+	}
+
+	return merged
+}
+
+// seriesReplica is a series along with whether it comes from a local store
+type seriesReplica struct {
+	series *ts.Series
+	local  bool
+}
+
+// mergeSeries groups the series of every store by tags and merges the replicas of each series,
+// keeping the order series were first received in
+func (p ConflictPolicy) mergeSeries(ctx context.Context, series []seriesReplica) []*ts.Series {
+	var (
+		keys    []string
+		grouped = make(map[string][]seriesReplica, len(series))
+	)
+
+	for _, s := range series {
+		key := seriesKey(s.series.Name(), s.series.Tags)
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		grouped[key] = append(grouped[key], s)
+	}
+
+	merged := make([]*ts.Series, len(keys))
+	for i, key := range keys {
+		merged[i] = p.mergeReplicas(ctx, grouped[key])
+	}
+
+	return merged
+}
+
+func (p ConflictPolicy) mergeReplicas(ctx context.Context, series []seriesReplica) *ts.Series {
+	first := series[0].series
+	if len(series) == 1 {
+		return first
+	}
+
+	replicas := make([]replica, len(series))
+	aligned := true
+	for i, s := range series {
+		values := make([]float64, s.series.Len())
+		for j := range values {
+			values[j] = s.series.ValueAt(j)
+		}
+
+		replicas[i] = replica{
+			start:  s.series.StartTime(),
+			step:   time.Duration(s.series.MillisPerStep()) * time.Millisecond,
+			values: values,
+			local:  s.local,
+		}
+		aligned = aligned && s.series.StartTime().Equal(first.StartTime()) &&
+			s.series.MillisPerStep() == first.MillisPerStep() && s.series.Len() == first.Len()
+	}
+
+	// Replicas fetched at different resolutions cannot be merged step by step, the preferred one is kept
+	// and the first one received when averaging
+	if !aligned {
+		return series[p.order(replicas)[0]].series
+	}
+
+	values := ts.NewValues(ctx, first.MillisPerStep(), first.Len())
+	for i, v := range p.merge(replicas) {
+		values.SetValueAt(i, v)
+	}
+
+	return ts.NewSeries(ctx, first.Name(), first.StartTime(), values, first.Tags)
+}
+
+// rawReplica is the raw datapoints of a series returned by a single store
+type rawReplica struct {
+	tags       models.Tags
+	datapoints []ts.Datapoint
+	local      bool
+}
+
+// newest is the time of the last datapoint of the replica, or the zero time when it has none
+func (r rawReplica) newest() time.Time {
+	for i := len(r.datapoints) - 1; i >= 0; i-- {
+		if !math.IsNaN(r.datapoints[i].Value) {
+			return r.datapoints[i].Timestamp
+		}
+	}
+
+	return time.Time{}
+}
+
+// rankedDatapoint is a datapoint along with the preference of the replica it comes from
+type rankedDatapoint struct {
+	ts.Datapoint
+	rank int
+}
+
+// mergeDatapoints combines the raw datapoints of the replicas of a series by timestamp. Where several
+// replicas hold a datapoint at the same time the value of the preferred one is kept, or the values are
+// averaged, and datapoints held by a single replica are always kept.
+func (p ConflictPolicy) mergeDatapoints(replicas []rawReplica) []ts.Datapoint {
+	if len(replicas) == 1 {
+		return replicas[0].datapoints
+	}
+
+	ordered := p.orderBy(len(replicas), func(i int) bool {
+		return replicas[i].local
+	}, func(i int) time.Time {
+		return replicas[i].newest()
+	})
+
+	var ranked []rankedDatapoint
+	for rank, idx := range ordered {
+		for _, dp := range replicas[idx].datapoints {
+			ranked = append(ranked, rankedDatapoint{Datapoint: dp, rank: rank})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if !ranked[i].Timestamp.Equal(ranked[j].Timestamp) {
+			return ranked[i].Timestamp.Before(ranked[j].Timestamp)
+		}
+
+		return ranked[i].rank < ranked[j].rank
+	})
+
+	merged := make([]ts.Datapoint, 0, len(ranked))
+	for i := 0; i < len(ranked); {
+		timestamp := ranked[i].Timestamp
+		value, sum, count := math.NaN(), 0.0, 0
+		for ; i < len(ranked) && ranked[i].Timestamp.Equal(timestamp); i++ {
+			if math.IsNaN(ranked[i].Value) {
+				continue
+			}
+
+			if count == 0 {
+				value = ranked[i].Value
+			}
+
+			sum += ranked[i].Value
+			count++
+		}
+
+		if p == Average && count > 0 {
+			value = sum / float64(count)
+		}
+
+		merged = append(merged, ts.Datapoint{Timestamp: timestamp, Value: value})
+	}
+
+	return merged
+}
+
+// rawMerger merges the raw series streamed by several stores. A series is passed on as soon as every store
+// has either returned it or finished, so only the series still waited on are buffered. Series are passed
+// on one at a time, the ones left once a store finishes in the order they were first received in.
+type rawMerger struct {
+	sync.Mutex
+	policy  ConflictPolicy
+	partial bool
+	fn      storage.RawSeriesFn
+	done    []bool
+	keys    []string
+	pending map[string]*pendingSeries
+	err     error
+}
+
+// pendingSeries is a series waiting on the replicas of stores which are still streaming
+type pendingSeries struct {
+	replicas []rawReplica
+	stores   []bool
+}
+
+func newRawMerger(policy ConflictPolicy, stores int, partial bool, fn storage.RawSeriesFn) *rawMerger {
+	return &rawMerger{
+		policy:  policy,
+		partial: partial,
+		fn:      fn,
+		done:    make([]bool, stores),
+		pending: make(map[string]*pendingSeries),
+	}
+}
+
+// add buffers the replica of a series returned by a store, the series is passed on once it is complete
+func (m *rawMerger) add(store int, replica rawReplica) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+
+	key := replica.tags.ID()
+	series, ok := m.pending[key]
+	if !ok {
+		series = &pendingSeries{stores: make([]bool, len(m.done))}
+		m.pending[key] = series
+		m.keys = append(m.keys, key)
+	}
+
+	series.replicas = append(series.replicas, replica)
+	series.stores[store] = true
+	if m.complete(series) {
+		m.emit(key)
+	}
+
+	return m.err
+}
+
+// finish marks a store as done, the series it did not return no longer wait on it. Outside of partial mode
+// a failed store fails the fetch and nothing else is passed on.
+func (m *rawMerger) finish(store int, err error) {
+	m.Lock()
+	defer m.Unlock()
+	if err != nil && !m.partial {
+		if m.err == nil {
+			m.err = err
+		}
+
+		return
+	}
+
+	m.done[store] = true
+	waiting := m.keys[:0]
+	for _, key := range m.keys {
+		series, ok := m.pending[key]
+		if !ok {
+			continue
+		}
+
+		if m.err == nil && m.complete(series) {
+			m.emit(key)
+			continue
+		}
+
+		waiting = append(waiting, key)
+	}
+
+	m.keys = waiting
+}
+
+// Err is the first error of the callback, or of a store outside of partial mode
+func (m *rawMerger) Err() error {
+	m.Lock()
+	defer m.Unlock()
+	return m.err
+}
+
+func (m *rawMerger) complete(series *pendingSeries) bool {
+	for store, done := range m.done {
+		if !done && !series.stores[store] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *rawMerger) emit(key string) {
+	series := m.pending[key]
+	delete(m.pending, key)
+	merged := m.policy.mergeDatapoints(series.replicas)
+	if err := m.fn(series.replicas[0].tags, &datapointIter{datapoints: merged, idx: -1}); err != nil {
+		m.err = err
+	}
+}
+
+// datapointIter iterates over buffered datapoints
+type datapointIter struct {
+	datapoints []ts.Datapoint
+	idx        int
+}
+
+func (it *datapointIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.datapoints)
+}
+
+func (it *datapointIter) Current() ts.Datapoint {
+	return it.datapoints[it.idx]
+}
+
+func (it *datapointIter) Err() error {
+	return nil
+}
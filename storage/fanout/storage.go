@@ -22,9 +22,14 @@ package fanout
 
 import (
 	"context"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor"
 	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/policy/filter"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
@@ -34,15 +39,145 @@ import (
 	"go.uber.org/zap"
 )
 
+// Options are the options of a fanout storage
+type Options struct {
+	// PartialResults returns the results of the stores which succeeded when others fail, reads
+	// only fail when every store failed
+	PartialResults bool
+	// StoreTimeout bounds every store read in partial mode so that slow stores are left out
+	StoreTimeout time.Duration
+	// ConflictPolicy decides the values kept when several stores return the same series
+	ConflictPolicy ConflictPolicy
+	// PushDownAggregations executes aggregations on every store and combines their results, stores must
+	// hold distinct series since replicas would be aggregated once per store
+	PushDownAggregations bool
+}
+
 type fanoutStorage struct {
 	stores      []storage.Storage
 	fetchFilter filter.Storage
 	writeFilter filter.Storage
+	opts        Options
 }
 
 // NewStorage creates a new remote Storage instance.
 func NewStorage(stores []storage.Storage, fetchFilter filter.Storage, writeFilter filter.Storage) storage.Storage {
-	return &fanoutStorage{stores: stores, fetchFilter: fetchFilter, writeFilter: writeFilter}
+	return NewStorageWithOptions(stores, fetchFilter, writeFilter, Options{})
+}
+
+// NewStorageWithOptions creates a new fanout Storage instance with options.
+func NewStorageWithOptions(stores []storage.Storage, fetchFilter filter.Storage, writeFilter filter.Storage, opts Options) storage.Storage {
+	s := &fanoutStorage{stores: stores, fetchFilter: fetchFilter, writeFilter: writeFilter, opts: opts}
+	if opts.PushDownAggregations {
+		return &pushDownStorage{fanoutStorage: s}
+	}
+
+	return s
+}
+
+// pushDownStorage is a fanout storage executing the sub plans pushed down to it on every store
+type pushDownStorage struct {
+	*fanoutStorage
+}
+
+// ExecuteSubPlan executes the sub plan on the stores in parallel and combines their results into the result
+// over the series of every store
+func (s *pushDownStorage) ExecuteSubPlan(
+	ctx context.Context, subPlan plan.SubPlan, options *storage.FetchOptions) (storage.BlockResult, error) {
+	stores := filterStores(s.stores, s.fetchFilter, subPlan.FetchQuery())
+	requests := make([]execution.Request, len(stores))
+	for idx, store := range stores {
+		requests[idx] = newSubPlanRequest(store, subPlan, options)
+	}
+
+	succeeded, warnings, err := s.execute(ctx, stores, requests)
+	if err != nil {
+		closeSubPlanBlocks(requests)
+		return storage.BlockResult{}, err
+	}
+
+	var blocks []storage.Block
+	for _, req := range succeeded {
+		subPlanReq, ok := req.(*subPlanRequest)
+		if !ok {
+			closeSubPlanBlocks(requests)
+			return storage.BlockResult{}, errors.ErrFetchRequestType
+		}
+
+		blocks = append(blocks, subPlanReq.result.Blocks...)
+		warnings = append(warnings, subPlanReq.result.Warnings...)
+	}
+
+	combined, err := subPlan.Combine(blocks)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: combined, Partial: len(warnings) > 0, Warnings: warnings}, nil
+}
+
+// closeSubPlanBlocks releases the blocks of the sub plans once the execution failed
+func closeSubPlanBlocks(requests []execution.Request) {
+	for _, req := range requests {
+		if subPlanReq, ok := req.(*subPlanRequest); ok {
+			closeBlocks(subPlanReq.result.Blocks)
+		}
+	}
+}
+
+// execute processes the read requests of the stores in parallel. In partial mode the requests which
+// succeeded are returned along with warnings for the failed ones, unless every request failed.
+func (s *fanoutStorage) execute(ctx context.Context, stores []storage.Storage, requests []execution.Request) ([]execution.Request, []storage.Warning, error) {
+	if !s.opts.PartialResults {
+		return requests, nil, execution.ExecuteParallel(ctx, requests)
+	}
+
+	errs := make([]error, len(requests))
+	var wg sync.WaitGroup
+	for idx, req := range requests {
+		wg.Add(1)
+		go func(idx int, req execution.Request) {
+			defer wg.Done()
+			reqCtx, cancel := s.storeContext(ctx)
+			defer cancel()
+			errs[idx] = req.Process(reqCtx)
+		}(idx, req)
+	}
+
+	wg.Wait()
+
+	var (
+		succeeded []execution.Request
+		warnings  []storage.Warning
+	)
+	for idx, err := range errs {
+		if err != nil {
+			warning := storage.NewWarning(stores[idx], err)
+			// Warnings only say how the store failed, the error itself is logged
+			logging.WithContext(ctx).Warn("store left out of partial result",
+				zap.String("store", warning.Store), zap.Any("error", err))
+			warnings = append(warnings, warning)
+			continue
+		}
+
+		succeeded = append(succeeded, requests[idx])
+	}
+
+	// Only fail when every store failed
+	if len(requests) > 0 && len(succeeded) == 0 {
+		return nil, nil, errs[0]
+	}
+
+	return succeeded, warnings, nil
+}
+
+// storeContext bounds the context of a store read in partial mode
+func (s *fanoutStorage) storeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.opts.StoreTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.opts.StoreTimeout)
 }
 
 func (s *fanoutStorage) Fetch(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.FetchResult, error) {
@@ -52,17 +187,26 @@ func (s *fanoutStorage) Fetch(ctx context.Context, query *storage.FetchQuery, op
 		requests[idx] = newFetchRequest(store, query, options)
 	}
 
-	err := execution.ExecuteParallel(ctx, requests)
+	succeeded, warnings, err := s.execute(ctx, stores, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := handleFetchResponses(ctx, succeeded, s.opts.ConflictPolicy)
 	if err != nil {
 		return nil, err
 	}
 
-	return handleFetchResponses(requests)
+	// Stores left out by the stores themselves, such as nested fanouts, are reported along with the failed stores
+	result.Partial = result.Partial || len(warnings) > 0
+	result.Warnings = append(warnings, result.Warnings...)
+	return result, nil
 }
 
-func handleFetchResponses(requests []execution.Request) (*storage.FetchResult, error) {
-	seriesList := make([]*ts.Series, 0, len(requests))
-	result := &storage.FetchResult{SeriesList: seriesList, LocalOnly: true}
+// handleFetchResponses merges the replicas of a series returned by several stores into a single series
+func handleFetchResponses(ctx context.Context, requests []execution.Request, policy ConflictPolicy) (*storage.FetchResult, error) {
+	var series []seriesReplica
+	result := &storage.FetchResult{LocalOnly: true}
 	for _, req := range requests {
 		fetchreq, ok := req.(*fetchRequest)
 		if !ok {
@@ -73,29 +217,148 @@ func handleFetchResponses(requests []execution.Request) (*storage.FetchResult, e
 			return nil, errors.ErrInvalidFetchResult
 		}
 
-		if fetchreq.store.Type() != storage.TypeLocalDC {
+		local := fetchreq.store.Type() == storage.TypeLocalDC
+		if !local {
 			result.LocalOnly = false
 		}
 
-		result.SeriesList = append(result.SeriesList, fetchreq.result.SeriesList...)
+		result.Partial = result.Partial || fetchreq.result.Partial
+		result.Warnings = append(result.Warnings, fetchreq.result.Warnings...)
+
+		for _, s := range fetchreq.result.SeriesList {
+			series = append(series, seriesReplica{series: s, local: local})
+		}
 	}
 
+	result.SeriesList = policy.mergeSeries(ctx, series)
 	return result, nil
 }
 
-func (s *fanoutStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
-	var metrics models.Metrics
-
+// FetchRaw streams the raw series of the stores. A single store is streamed as its series are received, the
+// replicas of a series returned by several stores are merged with the conflict policy once every store has
+// returned it or finished. In partial mode a failed store is reported as a warning and the remaining stores
+// are still returned.
+func (s *fanoutStorage) FetchRaw(
+	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
 	stores := filterStores(s.stores, s.fetchFilter, query)
-	for _, store := range stores {
-		results, err := store.FetchTags(ctx, query, options)
-		if err != nil {
-			return nil, err
+	if len(stores) == 1 {
+		if s.opts.PartialResults {
+			var cancel context.CancelFunc
+			ctx, cancel = s.storeContext(ctx)
+			defer cancel()
+		}
+
+		return fetchRaw(ctx, stores[0], query, options, fn)
+	}
+
+	merger := newRawMerger(s.opts.ConflictPolicy, len(stores), s.opts.PartialResults, fn)
+	requests := make([]execution.Request, len(stores))
+	for idx, store := range stores {
+		requests[idx] = newFetchRawRequest(store, idx, query, options, merger)
+	}
+
+	succeeded, warnings, err := s.execute(ctx, stores, requests)
+	if err != nil {
+		return storage.RawResult{}, err
+	}
+
+	// NB: errors of the callback fail the fetch even in partial mode
+	if err := merger.Err(); err != nil {
+		return storage.RawResult{}, err
+	}
+
+	for _, req := range succeeded {
+		fetchreq, ok := req.(*fetchRawRequest)
+		if !ok {
+			return storage.RawResult{}, errors.ErrFetchRequestType
+		}
+
+		warnings = append(warnings, fetchreq.result.Warnings...)
+	}
+
+	return storage.RawResult{Warnings: warnings}, nil
+}
+
+// fetchRaw streams the raw series of a store, stores which cannot stream raw series are fetched in full
+// and their series streamed afterwards
+func fetchRaw(
+	ctx context.Context,
+	store storage.Storage,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+	fn storage.RawSeriesFn,
+) (storage.RawResult, error) {
+	if raw, ok := store.(storage.RawQuerier); ok {
+		return raw.FetchRaw(ctx, query, options, fn)
+	}
+
+	result, err := store.Fetch(ctx, query, options)
+	if err != nil {
+		return storage.RawResult{}, err
+	}
+
+	for _, series := range result.SeriesList {
+		if err := fn(series.Tags, storage.SeriesToDatapointIter(series)); err != nil {
+			return storage.RawResult{}, err
 		}
-		metrics = append(metrics, results.Metrics...)
 	}
 
-	result := &storage.SearchResults{Metrics: metrics}
+	return storage.RawResult{Warnings: result.Warnings}, nil
+}
+
+// FetchTags searches the stores in parallel, metrics returned by several stores are only returned once
+// and the limit applies to the metrics of every store together
+func (s *fanoutStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	requests := make([]execution.Request, len(stores))
+	for idx, store := range stores {
+		requests[idx] = newFetchTagsRequest(store, query, options)
+	}
+
+	succeeded, warnings, err := s.execute(ctx, stores, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := handleFetchTagsResponses(succeeded)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Warnings = append(warnings, result.Warnings...)
+
+	if options != nil {
+		result.ApplyLimit(options.Limit)
+	}
+
+	return result, nil
+}
+
+func handleFetchTagsResponses(requests []execution.Request) (*storage.SearchResults, error) {
+	result := &storage.SearchResults{}
+	seen := make(map[string]struct{})
+	for _, req := range requests {
+		fetchreq, ok := req.(*fetchTagsRequest)
+		if !ok {
+			return nil, errors.ErrFetchRequestType
+		}
+
+		if fetchreq.result == nil {
+			return nil, errors.ErrInvalidFetchResult
+		}
+
+		result.Truncated = result.Truncated || fetchreq.result.Truncated
+		result.Warnings = append(result.Warnings, fetchreq.result.Warnings...)
+		for _, metric := range fetchreq.result.Metrics {
+			key := seriesKey(metric.ID, metric.Tags)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			result.Metrics = append(result.Metrics, metric)
+		}
+	}
 
 	return result, nil
 }
@@ -116,7 +379,235 @@ func (s *fanoutStorage) Type() storage.Type {
 
 func (s *fanoutStorage) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
-	return storage.BlockResult{}, errors.ErrNotImplemented
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	requests := make([]execution.Request, len(stores))
+	for idx, store := range stores {
+		requests[idx] = newFetchBlocksRequest(store, query, options)
+	}
+
+	succeeded, warnings, err := s.execute(ctx, stores, requests)
+	if err != nil {
+		// Stores which succeeded before another one failed still hold blocks
+		closeFetchedBlocks(requests)
+		return storage.BlockResult{}, err
+	}
+
+	result, err := handleFetchBlocksResponses(succeeded, s.opts.ConflictPolicy)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	// Stores left out by the stores themselves, such as nested fanouts, are reported along with the failed stores
+	result.Partial = result.Partial || len(warnings) > 0
+	result.Warnings = append(warnings, result.Warnings...)
+	return result, nil
+}
+
+// storeBlock is a block along with whether it comes from a local store
+type storeBlock struct {
+	block storage.Block
+	local bool
+}
+
+// blockGroup is a set of blocks whose bounds overlap, bounds span every block of the group
+type blockGroup struct {
+	bounds storage.Bounds
+	blocks []storeBlock
+}
+
+func (g blockGroup) overlaps(bounds storage.Bounds) bool {
+	return !bounds.Start.After(g.bounds.End) && !g.bounds.Start.After(bounds.End)
+}
+
+// spanBounds returns bounds from the earliest start to the latest end of both bounds
+func spanBounds(a, b storage.Bounds) storage.Bounds {
+	if b.Start.Before(a.Start) {
+		a.Start = b.Start
+	}
+
+	if b.End.After(a.End) {
+		a.End = b.End
+	}
+
+	return a
+}
+
+// groupOverlapping adds a block to the group it overlaps, groups which the block overlaps together are
+// merged into the earliest one
+func groupOverlapping(groups []blockGroup, block storeBlock) []blockGroup {
+	bounds := block.block.Meta().Bounds
+	merged := blockGroup{bounds: bounds}
+	first := -1
+	kept := groups[:0]
+	for _, group := range groups {
+		if !group.overlaps(bounds) {
+			kept = append(kept, group)
+			continue
+		}
+
+		if first < 0 {
+			first = len(kept)
+			kept = append(kept, blockGroup{})
+		}
+
+		merged.bounds = spanBounds(merged.bounds, group.bounds)
+		merged.blocks = append(merged.blocks, group.blocks...)
+	}
+
+	merged.blocks = append(merged.blocks, block)
+	if first < 0 {
+		return append(kept, merged)
+	}
+
+	kept[first] = merged
+	return kept
+}
+
+// handleFetchBlocksResponses merges the blocks whose bounds overlap across stores so that the returned blocks
+// never overlap, keeping the order they were first received in
+func handleFetchBlocksResponses(requests []execution.Request, policy ConflictPolicy) (storage.BlockResult, error) {
+	var (
+		result storage.BlockResult
+		groups []blockGroup
+	)
+
+	for _, req := range requests {
+		fetchreq, ok := req.(*fetchBlocksRequest)
+		if !ok {
+			closeFetchedBlocks(requests)
+			return storage.BlockResult{}, errors.ErrFetchRequestType
+		}
+
+		result.Partial = result.Partial || fetchreq.result.Partial
+		result.Warnings = append(result.Warnings, fetchreq.result.Warnings...)
+		local := fetchreq.store.Type() == storage.TypeLocalDC
+		for _, block := range fetchreq.result.Blocks {
+			groups = groupOverlapping(groups, storeBlock{block: block, local: local})
+		}
+	}
+
+	blocks := make([]storage.Block, 0, len(groups))
+	for i, group := range groups {
+		block, err := mergeBlocks(group.blocks, policy)
+		if err != nil {
+			// The blocks merged so far are released along with the ones left to merge
+			closeBlocks(blocks)
+			for _, remaining := range groups[i:] {
+				for _, b := range remaining.blocks {
+					b.block.Close()
+				}
+			}
+
+			return storage.BlockResult{}, err
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	result.Blocks = blocks
+	return result, nil
+}
+
+// closeFetchedBlocks releases the blocks fetched by the requests once the fetch failed
+func closeFetchedBlocks(requests []execution.Request) {
+	for _, req := range requests {
+		if fetchreq, ok := req.(*fetchBlocksRequest); ok {
+			closeBlocks(fetchreq.result.Blocks)
+		}
+	}
+}
+
+// closeBlocks releases blocks on the error path, where the error of the fetch is returned rather than the
+// errors closing them
+func closeBlocks(blocks []storage.Block) {
+	for _, block := range blocks {
+		block.Close()
+	}
+}
+
+// mergeBlocks combines the series of overlapping blocks into a single block spanning all of them, the
+// replicas of a series returned by several stores are merged into a single series. Steps missing from
+// a block are empty in its replicas so they are filled in from the other blocks.
+func mergeBlocks(blocks []storeBlock, policy ConflictPolicy) (storage.Block, error) {
+	if len(blocks) == 1 {
+		return blocks[0].block, nil
+	}
+
+	bounds := blocks[0].block.Meta().Bounds
+	for _, b := range blocks[1:] {
+		bounds = spanBounds(bounds, b.block.Meta().Bounds)
+	}
+
+	steps := bounds.Steps()
+	var (
+		seriesMeta []storage.SeriesMeta
+		replicas   [][]replica
+		keys       = make(map[string]int)
+	)
+
+	for _, b := range blocks {
+		offset, ok := stepOffset(bounds, b.block.Meta().Bounds)
+		if !ok {
+			return nil, errors.ErrBlockBoundsMismatch
+		}
+
+		iter := b.block.SeriesIter()
+		for iter.Next() {
+			series := iter.Current()
+			vals := make([]float64, steps)
+			for i := range vals {
+				vals[i] = math.NaN()
+			}
+
+			for i := 0; i < series.Len() && offset+i < steps; i++ {
+				vals[offset+i] = series.ValueAt(i)
+			}
+
+			r := replica{start: bounds.Start, step: bounds.StepSize, values: vals, local: b.local}
+			key := seriesKey(series.Name(), series.Tags)
+			if idx, ok := keys[key]; ok {
+				replicas[idx] = append(replicas[idx], r)
+				continue
+			}
+
+			keys[key] = len(seriesMeta)
+			seriesMeta = append(seriesMeta, storage.SeriesMeta{Name: series.Name(), Tags: series.Tags})
+			replicas = append(replicas, []replica{r})
+		}
+	}
+
+	// The merged block holds copies of every series so the originals can be released
+	for _, b := range blocks {
+		if err := b.block.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([][]float64, len(replicas))
+	for i, r := range replicas {
+		values[i] = r[0].values
+		if len(r) > 1 {
+			values[i] = policy.merge(r)
+		}
+	}
+
+	// Common tags may differ between stores so they are not carried over
+	return storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, values)
+}
+
+// stepOffset returns the step of the spanning bounds at which the bounds of a block start, the block must
+// share the step size of the spanning bounds and start on one of its steps
+func stepOffset(span, bounds storage.Bounds) (int, bool) {
+	if bounds.StepSize != span.StepSize || span.StepSize <= 0 {
+		return 0, false
+	}
+
+	diff := bounds.Start.Sub(span.Start)
+	if diff%span.StepSize != 0 {
+		return 0, false
+	}
+
+	return int(diff / span.StepSize), true
 }
 
 func (s *fanoutStorage) Close() error {
@@ -167,6 +658,126 @@ func (f *fetchRequest) Process(ctx context.Context) error {
 	return nil
 }
 
+type fetchBlocksRequest struct {
+	store   storage.Storage
+	query   *storage.FetchQuery
+	options *storage.FetchOptions
+	result  storage.BlockResult
+}
+
+func newFetchBlocksRequest(store storage.Storage, query *storage.FetchQuery, options *storage.FetchOptions) execution.Request {
+	return &fetchBlocksRequest{
+		store:   store,
+		query:   query,
+		options: options,
+	}
+}
+
+func (f *fetchBlocksRequest) Process(ctx context.Context) error {
+	result, err := f.store.FetchBlocks(ctx, f.query, f.options)
+	if err != nil {
+		return err
+	}
+
+	f.result = result
+	return nil
+}
+
+type subPlanRequest struct {
+	store   storage.Storage
+	subPlan plan.SubPlan
+	options *storage.FetchOptions
+	result  storage.BlockResult
+}
+
+func newSubPlanRequest(store storage.Storage, subPlan plan.SubPlan, options *storage.FetchOptions) execution.Request {
+	return &subPlanRequest{
+		store:   store,
+		subPlan: subPlan,
+		options: options,
+	}
+}
+
+func (f *subPlanRequest) Process(ctx context.Context) error {
+	result, err := executor.ExecuteSubPlan(ctx, f.store, f.subPlan, f.options)
+	if err != nil {
+		return err
+	}
+
+	f.result = result
+	return nil
+}
+
+type fetchTagsRequest struct {
+	store   storage.Storage
+	query   *storage.FetchQuery
+	options *storage.FetchOptions
+	result  *storage.SearchResults
+}
+
+func newFetchTagsRequest(store storage.Storage, query *storage.FetchQuery, options *storage.FetchOptions) execution.Request {
+	return &fetchTagsRequest{
+		store:   store,
+		query:   query,
+		options: options,
+	}
+}
+
+func (f *fetchTagsRequest) Process(ctx context.Context) error {
+	result, err := f.store.FetchTags(ctx, f.query, f.options)
+	if err != nil {
+		return err
+	}
+
+	f.result = result
+	return nil
+}
+
+type fetchRawRequest struct {
+	store   storage.Storage
+	idx     int
+	query   *storage.FetchQuery
+	options *storage.FetchOptions
+	merger  *rawMerger
+	result  storage.RawResult
+}
+
+func newFetchRawRequest(
+	store storage.Storage,
+	idx int,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+	merger *rawMerger,
+) execution.Request {
+	return &fetchRawRequest{
+		store:   store,
+		idx:     idx,
+		query:   query,
+		options: options,
+		merger:  merger,
+	}
+}
+
+func (f *fetchRawRequest) Process(ctx context.Context) error {
+	local := f.store.Type() == storage.TypeLocalDC
+	result, err := fetchRaw(ctx, f.store, f.query, f.options, func(tags models.Tags, datapoints storage.DatapointIter) error {
+		var buffered []ts.Datapoint
+		for datapoints.Next() {
+			buffered = append(buffered, datapoints.Current())
+		}
+
+		if err := datapoints.Err(); err != nil {
+			return err
+		}
+
+		return f.merger.add(f.idx, rawReplica{tags: tags, datapoints: buffered, local: local})
+	})
+
+	f.merger.finish(f.idx, err)
+	f.result = result
+	return err
+}
+
 type writeRequest struct {
 	store storage.Storage
 	query *storage.WriteQuery
@@ -23,15 +23,24 @@ package fanout
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/policy/filter"
 	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/storage/mock"
 	"github.com/m3db/m3coordinator/test"
 	"github.com/m3db/m3coordinator/test/local"
 	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/util/execution"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/m3db/m3db/encoding"
@@ -94,8 +103,8 @@ func setupFanoutWrite(t *testing.T, output bool, errs ...error) storage.Storage
 	ctrl := gomock.NewController(t)
 	store1, session1 := local.NewStorageAndSession(ctrl)
 	store2, session2 := local.NewStorageAndSession(ctrl)
-	session1.EXPECT().Write(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[0])
-	session2.EXPECT().Write(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[len(errs)-1])
+	session1.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[0])
+	session2.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[len(errs)-1])
 	stores := []storage.Storage{
 		store1, store2,
 	}
@@ -125,6 +134,161 @@ func TestFanoutReadSuccess(t *testing.T) {
 	assert.NoError(t, store.Close())
 }
 
+func TestFanoutFetchRaw(t *testing.T) {
+	store := setupFanoutRead(t, true, &fetchResponse{result: fakeIterator(t)}, &fetchResponse{result: fakeIterator(t)})
+	raw, ok := store.(storage.RawQuerier)
+	require.True(t, ok)
+
+	var seriesTags []models.Tags
+	_, err := raw.FetchRaw(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{},
+		func(tags models.Tags, datapoints storage.DatapointIter) error {
+			seriesTags = append(seriesTags, tags)
+			return nil
+		})
+	require.NoError(t, err)
+	// Both stores return the same series which is merged into one
+	assert.Len(t, seriesTags, 1)
+}
+
+func TestFanoutFetchRawError(t *testing.T) {
+	store := setupFanoutRead(t, true)
+	_, err := store.(storage.RawQuerier).FetchRaw(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{},
+		func(models.Tags, storage.DatapointIter) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestFanoutFetchBlocksError(t *testing.T) {
+	store := setupFanoutRead(t, true)
+	_, err := store.FetchBlocks(context.TODO(), &storage.FetchQuery{Interval: time.Minute}, &storage.FetchOptions{})
+	assert.Error(t, err)
+}
+
+func TestFanoutFetchBlocksSuccess(t *testing.T) {
+	store := setupFanoutRead(t, true, &fetchResponse{result: fakeIterator(t)}, &fetchResponse{result: fakeIterator(t)})
+	now := time.Now()
+	query := &storage.FetchQuery{Start: now.Add(-time.Minute), End: now, Interval: time.Minute}
+	res, err := store.FetchBlocks(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 1)
+
+	block := res.Blocks[0]
+	assert.Equal(t, 2, block.Meta().Bounds.Steps())
+	// Both stores return the same series
+	require.Len(t, block.SeriesMeta(), 1)
+	assert.Equal(t, "id", block.SeriesMeta()[0].Name)
+}
+
+func TestFanoutFetchBlocksGroupsByBounds(t *testing.T) {
+	now := time.Now()
+	newBlock := func(bounds storage.Bounds, name string) storage.Block {
+		block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+			[]storage.SeriesMeta{{Name: name}}, [][]float64{make([]float64, bounds.Steps())})
+		require.NoError(t, err)
+		return block
+	}
+
+	first := storage.Bounds{Start: now, End: now.Add(time.Minute), StepSize: time.Minute}
+	second := storage.Bounds{Start: now.Add(2 * time.Minute), End: now.Add(3 * time.Minute), StepSize: time.Minute}
+	store := mock.NewMockStorage()
+	requests := []execution.Request{
+		&fetchBlocksRequest{store: store, result: storage.BlockResult{Blocks: []storage.Block{newBlock(first, "a"), newBlock(second, "b")}}},
+		&fetchBlocksRequest{store: store, result: storage.BlockResult{Blocks: []storage.Block{newBlock(second, "c")}}},
+	}
+
+	res, err := handleFetchBlocksResponses(requests, PreferLocal)
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 2)
+	assert.Equal(t, []storage.SeriesMeta{{Name: "a"}}, res.Blocks[0].SeriesMeta())
+	assert.True(t, res.Blocks[1].Meta().Bounds.Equals(second))
+	require.Len(t, res.Blocks[1].SeriesMeta(), 2)
+	assert.Equal(t, "b", res.Blocks[1].SeriesMeta()[0].Name)
+	assert.Equal(t, "c", res.Blocks[1].SeriesMeta()[1].Name)
+
+	// Blocks whose steps do not line up cannot be merged
+	misaligned := storage.Bounds{Start: now.Add(30 * time.Second), End: now.Add(90 * time.Second), StepSize: time.Minute}
+	_, err = mergeBlocks([]storeBlock{{block: newBlock(first, "a")}, {block: newBlock(misaligned, "b")}}, PreferLocal)
+	assert.Equal(t, errors.ErrBlockBoundsMismatch, err)
+}
+
+func TestFanoutFetchBlocksMergesOverlappingBounds(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	newBlock := func(start time.Time, values ...float64) storage.Block {
+		bounds := storage.Bounds{Start: start, End: start.Add(time.Duration(len(values)-1) * time.Minute), StepSize: time.Minute}
+		block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+			[]storage.SeriesMeta{{Name: "a", Tags: models.Tags{"a": "b"}}}, [][]float64{values})
+		require.NoError(t, err)
+		return block
+	}
+
+	// The local store splits the range in two blocks while the remote store returns a single one
+	requests := []execution.Request{
+		&fetchBlocksRequest{store: mock.NewMockStorageWithType(storage.TypeLocalDC),
+			result: storage.BlockResult{Blocks: []storage.Block{newBlock(now, 1, 2), newBlock(now.Add(2*time.Minute), 3, math.NaN())}}},
+		&fetchBlocksRequest{store: mock.NewMockStorageWithType(storage.TypeRemoteDC),
+			result: storage.BlockResult{Blocks: []storage.Block{newBlock(now.Add(time.Minute), 20, 30, 40)}}},
+	}
+
+	res, err := handleFetchBlocksResponses(requests, PreferLocal)
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 1)
+
+	block := res.Blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(storage.Bounds{Start: now, End: now.Add(3 * time.Minute), StepSize: time.Minute}))
+	require.Len(t, block.SeriesMeta(), 1)
+	iter := block.SeriesIter()
+	require.True(t, iter.Next())
+	series := iter.Current()
+	values := make([]float64, series.Len())
+	for i := range values {
+		values[i] = series.ValueAt(i)
+	}
+
+	assertValues(t, []float64{1, 2, 3, 40}, values)
+}
+
+// closedStore returns a block which records whether it was closed, or fails
+type closedStore struct {
+	storage.Storage
+	err    error
+	closed bool
+}
+
+func (s *closedStore) FetchBlocks(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (storage.BlockResult, error) {
+	if s.err != nil {
+		return storage.BlockResult{}, s.err
+	}
+
+	bounds := storage.Bounds{Start: query.Start, End: query.End, StepSize: query.Interval}
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, nil, nil)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: []storage.Block{&closedBlock{Block: block, store: s}}}, nil
+}
+
+type closedBlock struct {
+	storage.Block
+	store *closedStore
+}
+
+func (b *closedBlock) Close() error {
+	b.store.closed = true
+	return b.Block.Close()
+}
+
+func TestFanoutFetchBlocksClosesBlocksOnError(t *testing.T) {
+	setup()
+	succeeding := &closedStore{Storage: mock.NewMockStorage()}
+	stores := []storage.Storage{succeeding, &closedStore{Storage: mock.NewMockStorage(), err: fmt.Errorf("unavailable")}}
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	now := time.Now()
+	_, err := store.FetchBlocks(context.TODO(), &storage.FetchQuery{Start: now, End: now, Interval: time.Minute}, &storage.FetchOptions{})
+	require.Error(t, err)
+	assert.True(t, succeeding.closed)
+}
+
 func TestFanoutSearchEmpty(t *testing.T) {
 	store := setupFanoutRead(t, false)
 	res, err := store.FetchTags(context.TODO(), nil, nil)
@@ -164,3 +328,462 @@ func TestFanoutWriteSuccess(t *testing.T) {
 	})
 	assert.NoError(t, err)
 }
+
+// partialStore is a store which either fails, blocks until its context is done or returns a single series
+type partialStore struct {
+	storage.Storage
+	err   error
+	block bool
+}
+
+func newPartialStore(name string, err error) *partialStore {
+	return &partialStore{Storage: mock.NewMockStorageWithName(storage.TypeRemoteDC, name), err: err}
+}
+
+// Name is not promoted from the embedded storage interface, warnings identify the store by it
+func (s *partialStore) Name() string {
+	return s.Storage.(storage.Named).Name()
+}
+
+func (s *partialStore) Fetch(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions) (*storage.FetchResult, error) {
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	values := ts.NewValues(ctx, 1000, 1)
+	return &storage.FetchResult{SeriesList: []*ts.Series{ts.NewSeries(ctx, "foo", time.Now(), values, models.Tags{"a": "b"})}}, nil
+}
+
+func (s *partialStore) FetchTags(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions) (*storage.SearchResults, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &storage.SearchResults{Metrics: models.Metrics{{ID: "foo"}}}, nil
+}
+
+func TestFanoutFetchPartial(t *testing.T) {
+	setup()
+	stores := []storage.Storage{newPartialStore("us", nil), newPartialStore("eu", fmt.Errorf("unavailable"))}
+	store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{PartialResults: true})
+
+	res, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, res.SeriesList, 1)
+	assert.True(t, res.Partial)
+	assert.Equal(t, []storage.Warning{{Store: "eu", Message: "unavailable"}}, res.Warnings)
+	assert.Equal(t, []string{"eu: unavailable"}, storage.WarningStrings(res.Warnings))
+}
+
+func TestFanoutFetchPartialStoreTimeout(t *testing.T) {
+	setup()
+	slow := newPartialStore("slow", nil)
+	slow.block = true
+	stores := []storage.Storage{newPartialStore("us", nil), slow}
+	store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{PartialResults: true, StoreTimeout: 10 * time.Millisecond})
+
+	res, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, res.SeriesList, 1)
+	require.Len(t, res.Warnings, 1)
+	assert.Equal(t, "slow", res.Warnings[0].Store)
+}
+
+func TestFanoutFetchPartialAllFailed(t *testing.T) {
+	setup()
+	stores := []storage.Storage{newPartialStore("us", fmt.Errorf("unavailable")), newPartialStore("eu", fmt.Errorf("unavailable"))}
+	store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{PartialResults: true})
+
+	_, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	assert.Error(t, err)
+	_, err = store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	assert.Error(t, err)
+}
+
+func TestFanoutFetchTagsPartial(t *testing.T) {
+	setup()
+	stores := []storage.Storage{newPartialStore("us", nil), newPartialStore("eu", fmt.Errorf("unavailable"))}
+	store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{PartialResults: true})
+
+	res, err := store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, res.Metrics, 1)
+	assert.Equal(t, []string{"eu: unavailable"}, storage.WarningStrings(res.Warnings))
+}
+
+func TestFanoutFetchTagsUnique(t *testing.T) {
+	setup()
+	stores := []storage.Storage{newPartialStore("us", nil), newPartialStore("eu", nil)}
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	res, err := store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, models.Metrics{{ID: "foo"}}, res.Metrics)
+}
+
+// tagsStore is a store returning the given metrics once every tags store has been searched
+type tagsStore struct {
+	storage.Storage
+	metrics   models.Metrics
+	truncated bool
+	searching *sync.WaitGroup
+}
+
+func (s *tagsStore) FetchTags(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions) (*storage.SearchResults, error) {
+	s.searching.Done()
+	done := make(chan struct{})
+	go func() {
+		s.searching.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return &storage.SearchResults{Metrics: s.metrics, Truncated: s.truncated}, nil
+	case <-time.After(time.Second):
+		return nil, fmt.Errorf("stores were not searched in parallel")
+	}
+}
+
+func newTagsStores(metrics ...models.Metrics) []storage.Storage {
+	searching := &sync.WaitGroup{}
+	searching.Add(len(metrics))
+	stores := make([]storage.Storage, len(metrics))
+	for i, m := range metrics {
+		stores[i] = &tagsStore{Storage: mock.NewMockStorage(), metrics: m, searching: searching}
+	}
+
+	return stores
+}
+
+func TestFanoutFetchTagsParallel(t *testing.T) {
+	setup()
+	stores := newTagsStores(models.Metrics{{ID: "a"}, {ID: "b"}}, models.Metrics{{ID: "b"}, {ID: "c"}})
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	res, err := store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, models.Metrics{{ID: "a"}, {ID: "b"}, {ID: "c"}}, res.Metrics)
+	assert.False(t, res.Truncated)
+}
+
+func TestFanoutFetchTagsLimit(t *testing.T) {
+	setup()
+	stores := newTagsStores(models.Metrics{{ID: "a"}, {ID: "b"}}, models.Metrics{{ID: "b"}, {ID: "c"}})
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	// The limit applies once duplicates are dropped
+	res, err := store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, res.Metrics, 3)
+	assert.False(t, res.Truncated)
+
+	stores = newTagsStores(models.Metrics{{ID: "a"}, {ID: "b"}}, models.Metrics{{ID: "b"}, {ID: "c"}})
+	store = NewStorage(stores, filter.AllowAll, filter.AllowAll)
+	res, err = store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, models.Metrics{{ID: "a"}, {ID: "b"}}, res.Metrics)
+	assert.True(t, res.Truncated)
+}
+
+func TestFanoutFetchTagsTruncatedStore(t *testing.T) {
+	setup()
+	stores := newTagsStores(models.Metrics{{ID: "a"}}, models.Metrics{{ID: "b"}})
+	stores[1].(*tagsStore).truncated = true
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	res, err := store.FetchTags(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, res.Metrics, 2)
+	assert.True(t, res.Truncated)
+}
+
+// seriesStore is a store returning a single series with the given values
+type seriesStore struct {
+	storage.Storage
+	start  time.Time
+	values []float64
+}
+
+func newSeriesStore(sType storage.Type, start time.Time, values ...float64) *seriesStore {
+	return &seriesStore{Storage: mock.NewMockStorageWithType(sType), start: start, values: values}
+}
+
+func (s *seriesStore) Fetch(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions) (*storage.FetchResult, error) {
+	values := ts.NewValues(ctx, 1000, len(s.values))
+	for i, v := range s.values {
+		values.SetValueAt(i, v)
+	}
+
+	return &storage.FetchResult{SeriesList: []*ts.Series{ts.NewSeries(ctx, "foo", s.start, values, models.Tags{"a": "b"})}}, nil
+}
+
+func (s *seriesStore) FetchBlocks(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions) (storage.BlockResult, error) {
+	bounds := storage.Bounds{Start: s.start, End: s.start.Add(time.Duration(len(s.values)-1) * time.Second), StepSize: time.Second}
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+		[]storage.SeriesMeta{{Name: "foo", Tags: models.Tags{"a": "b"}}}, [][]float64{s.values})
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: []storage.Block{block}}, nil
+}
+
+func seriesValues(series *ts.Series) []float64 {
+	values := make([]float64, series.Len())
+	for i := range values {
+		values[i] = series.ValueAt(i)
+	}
+
+	return values
+}
+
+func assertValues(t *testing.T, expected, actual []float64) {
+	require.Len(t, actual, len(expected))
+	for i, v := range expected {
+		if math.IsNaN(v) {
+			assert.True(t, math.IsNaN(actual[i]), "expected NaN at step %d, got %v", i, actual[i])
+			continue
+		}
+
+		assert.Equal(t, v, actual[i], "step %d", i)
+	}
+}
+
+func TestFanoutFetchMergesReplicas(t *testing.T) {
+	setup()
+	nan := math.NaN()
+	now := time.Now()
+	tests := []struct {
+		policy   ConflictPolicy
+		expected []float64
+	}{
+		{PreferLocal, []float64{1, 20, 3, 40}},
+		{PreferNewest, []float64{10, 20, 30, 40}},
+		{Average, []float64{5.5, 20, 16.5, 40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			stores := []storage.Storage{
+				newSeriesStore(storage.TypeRemoteDC, now, 10, 20, 30, 40),
+				newSeriesStore(storage.TypeLocalDC, now, 1, nan, 3, nan),
+			}
+			store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{ConflictPolicy: tt.policy})
+
+			res, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.SeriesList, 1)
+			assert.Equal(t, models.Tags{"a": "b"}, res.SeriesList[0].Tags)
+			assertValues(t, tt.expected, seriesValues(res.SeriesList[0]))
+
+			blocks, err := store.FetchBlocks(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+			require.NoError(t, err)
+			require.Len(t, blocks.Blocks, 1)
+			iter := blocks.Blocks[0].SeriesIter()
+			require.True(t, iter.Next())
+			series := iter.Current()
+			assertValues(t, tt.expected, seriesValues(&series))
+			assert.False(t, iter.Next())
+		})
+	}
+}
+
+func TestFanoutFetchKeepsPreferredUnalignedReplica(t *testing.T) {
+	setup()
+	now := time.Now()
+	stores := []storage.Storage{
+		newSeriesStore(storage.TypeRemoteDC, now, 10, 20),
+		newSeriesStore(storage.TypeLocalDC, now.Add(time.Second), 1, 2),
+	}
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	res, err := store.Fetch(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, res.SeriesList, 1)
+	assert.Equal(t, []float64{1, 2}, seriesValues(res.SeriesList[0]))
+}
+
+// rawStore is a store streaming a single raw series
+type rawStore struct {
+	storage.Storage
+	datapoints []ts.Datapoint
+}
+
+func newRawStore(sType storage.Type, datapoints ...ts.Datapoint) *rawStore {
+	return &rawStore{Storage: mock.NewMockStorageWithType(sType), datapoints: datapoints}
+}
+
+func (s *rawStore) FetchRaw(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	return storage.RawResult{}, fn(models.Tags{"a": "b"}, &datapointIter{datapoints: s.datapoints, idx: -1})
+}
+
+func TestFanoutFetchRawMergesReplicas(t *testing.T) {
+	setup()
+	now := time.Now().Truncate(time.Second)
+	at := func(seconds int, v float64) ts.Datapoint {
+		return ts.Datapoint{Timestamp: now.Add(time.Duration(seconds) * time.Second), Value: v}
+	}
+
+	tests := []struct {
+		policy   ConflictPolicy
+		expected []float64
+	}{
+		{PreferLocal, []float64{1, 20, 3, 40}},
+		{PreferNewest, []float64{10, 20, 30, 40}},
+		{Average, []float64{5.5, 20, 16.5, 40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			stores := []storage.Storage{
+				newRawStore(storage.TypeRemoteDC, at(0, 10), at(1, 20), at(2, 30), at(3, 40)),
+				newRawStore(storage.TypeLocalDC, at(0, 1), at(2, 3)),
+			}
+			store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{ConflictPolicy: tt.policy})
+
+			var (
+				seriesTags []models.Tags
+				merged     []ts.Datapoint
+			)
+			_, err := store.(storage.RawQuerier).FetchRaw(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{},
+				func(tags models.Tags, datapoints storage.DatapointIter) error {
+					seriesTags = append(seriesTags, tags)
+					for datapoints.Next() {
+						merged = append(merged, datapoints.Current())
+					}
+
+					return datapoints.Err()
+				})
+			require.NoError(t, err)
+			assert.Equal(t, []models.Tags{{"a": "b"}}, seriesTags)
+
+			values := make([]float64, len(merged))
+			for i, dp := range merged {
+				assert.Equal(t, now.Add(time.Duration(i)*time.Second), dp.Timestamp)
+				values[i] = dp.Value
+			}
+
+			assertValues(t, tt.expected, values)
+		})
+	}
+}
+
+// streamingStore streams series one after the other, then waits for wait to be closed before finishing
+type streamingStore struct {
+	storage.Storage
+	series []models.Tags
+	wait   <-chan struct{}
+}
+
+func (s *streamingStore) FetchRaw(ctx context.Context, _ *storage.FetchQuery, _ *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	for _, tags := range s.series {
+		if err := fn(tags, &datapointIter{idx: -1}); err != nil {
+			return storage.RawResult{}, err
+		}
+	}
+
+	select {
+	case <-s.wait:
+		return storage.RawResult{}, nil
+	case <-time.After(time.Second):
+		return storage.RawResult{}, fmt.Errorf("series were not streamed before the store finished")
+	}
+}
+
+func TestFanoutFetchRawStreamsCompleteSeries(t *testing.T) {
+	setup()
+	released := make(chan struct{})
+	finished := make(chan struct{})
+	close(finished)
+	stores := []storage.Storage{
+		&streamingStore{Storage: mock.NewMockStorage(), series: []models.Tags{{"a": "1"}}, wait: released},
+		&streamingStore{Storage: mock.NewMockStorage(), series: []models.Tags{{"a": "1"}, {"a": "2"}}, wait: finished},
+	}
+	store := NewStorage(stores, filter.AllowAll, filter.AllowAll)
+
+	var seriesTags []models.Tags
+	_, err := store.(storage.RawQuerier).FetchRaw(context.TODO(), &storage.FetchQuery{}, &storage.FetchOptions{},
+		func(tags models.Tags, _ storage.DatapointIter) error {
+			seriesTags = append(seriesTags, tags)
+			// The series returned by every store is passed on while the first store is still streaming
+			if tags["a"] == "1" {
+				close(released)
+			}
+
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Tags{{"a": "1"}, {"a": "2"}}, seriesTags)
+}
+
+// subPlanStore executes sub plans by returning the count of its series
+type subPlanStore struct {
+	storage.Storage
+	count float64
+	err   error
+}
+
+func (s *subPlanStore) Name() string {
+	if named, ok := s.Storage.(storage.Named); ok {
+		return named.Name()
+	}
+
+	return ""
+}
+
+func (s *subPlanStore) ExecuteSubPlan(
+	_ context.Context, subPlan plan.SubPlan, _ *storage.FetchOptions) (storage.BlockResult, error) {
+	if s.err != nil {
+		return storage.BlockResult{}, s.err
+	}
+
+	bounds := storage.Bounds{Start: subPlan.TimeSpec.Start, End: subPlan.TimeSpec.End, StepSize: subPlan.TimeSpec.Step}
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+		[]storage.SeriesMeta{{Name: "{}", Tags: models.Tags{}}}, [][]float64{{s.count}})
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: []storage.Block{block}}, nil
+}
+
+func TestFanoutExecuteSubPlanCombinesStores(t *testing.T) {
+	setup()
+	now := time.Now().Truncate(time.Minute)
+	subPlan := plan.SubPlan{
+		Fetch:      functions.FetchOp{Name: "up"},
+		Transforms: []parser.Params{functions.AggregationOp{OperatorType: functions.CountType}},
+		TimeSpec:   transform.TimeSpec{Start: now, End: now, Now: now, Step: time.Minute},
+	}
+	stores := []storage.Storage{
+		&subPlanStore{Storage: mock.NewMockStorage(), count: 2},
+		&subPlanStore{Storage: mock.NewMockStorage(), count: 3},
+		&subPlanStore{Storage: mock.NewMockStorageWithName(storage.TypeRemoteDC, "eu"), err: fmt.Errorf("unavailable")},
+	}
+
+	_, ok := NewStorage(stores, filter.AllowAll, filter.AllowAll).(plan.SubPlanExecutor)
+	assert.False(t, ok, "aggregations are only pushed down when enabled")
+
+	store := NewStorageWithOptions(stores, filter.AllowAll, filter.AllowAll, Options{PartialResults: true, PushDownAggregations: true})
+	executor, ok := store.(plan.SubPlanExecutor)
+	require.True(t, ok)
+
+	result, err := executor.ExecuteSubPlan(context.TODO(), subPlan, &storage.FetchOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Partial)
+	assert.Equal(t, []string{"eu: unavailable"}, storage.WarningStrings(result.Warnings))
+
+	// The counts of the stores are summed
+	require.Len(t, result.Blocks, 1)
+	iter := result.Blocks[0].SeriesIter()
+	require.True(t, iter.Next())
+	series := iter.Current()
+	assert.Equal(t, 5.0, series.ValueAt(0))
+	assert.False(t, iter.Next())
+}
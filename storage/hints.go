@@ -0,0 +1,161 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/ts"
+)
+
+// preAggregations are the aggregations which give the same result when prometheus recomputes
+// them over series that were already aggregated per group
+var preAggregations = map[string]func(a, b float64) float64{
+	"sum": func(a, b float64) float64 { return a + b },
+	"min": math.Min,
+	"max": math.Max,
+}
+
+// CanDownsample returns true if the hints allow replacing the raw datapoints with a single
+// datapoint per evaluation step. Range selectors need every datapoint within their range so they
+// are never downsampled
+func (h *FetchHints) CanDownsample() bool {
+	return h != nil && h.Step > 0 && h.Range == 0
+}
+
+// Downsample lays datapoints, sorted by time, onto the evaluation steps of the hints. Each step takes the
+// most recent datapoint within the lookback, which is the datapoint prometheus selects at that step.
+// Prometheus subtracts its lookback from the hinted start so the first step is at start + lookback. The
+// lookback of the hints is used when set, the default lookback otherwise
+func (h *FetchHints) Downsample(ctx context.Context, datapoints []ts.Datapoint, defaultLookback time.Duration) (ts.Values, time.Time) {
+	lookback := defaultLookback
+	if h.Lookback > 0 {
+		lookback = h.Lookback
+	}
+
+	start := h.Start.Add(lookback)
+	numSteps := 0
+	if !start.After(h.End) {
+		numSteps = int(h.End.Sub(start)/h.Step) + 1
+	}
+
+	values := ts.NewValues(ctx, int(h.Step/time.Millisecond), numSteps)
+	idx := 0
+	for i := 0; i < numSteps; i++ {
+		stepTime := start.Add(time.Duration(i) * h.Step)
+		for idx < len(datapoints) && !datapoints[idx].Timestamp.After(stepTime) {
+			idx++
+		}
+
+		if idx == 0 {
+			continue
+		}
+
+		dp := datapoints[idx-1]
+		if !dp.Timestamp.Before(stepTime.Add(-lookback)) {
+			values.SetValueAt(i, dp.Value)
+		}
+	}
+
+	return values, start
+}
+
+// PreAggregate combines downsampled series which share the tags kept by the hinted aggregation so that
+// a single series per group is returned. Series are returned unchanged for other functions
+func (h *FetchHints) PreAggregate(ctx context.Context, seriesList []*ts.Series) []*ts.Series {
+	fn, ok := preAggregations[h.Func]
+	if !ok || !h.CanDownsample() {
+		return seriesList
+	}
+
+	type group struct {
+		start  time.Time
+		tags   models.Tags
+		values ts.MutableValues
+	}
+
+	groups := make(map[string]*group)
+	ids := make([]string, 0)
+	for _, series := range seriesList {
+		tags := h.groupTags(series.Tags)
+		id := tags.ID()
+		g, ok := groups[id]
+		if !ok {
+			g = &group{
+				start:  series.StartTime(),
+				tags:   tags,
+				values: ts.NewValues(ctx, series.MillisPerStep(), series.Len()),
+			}
+			groups[id] = g
+			ids = append(ids, id)
+		}
+
+		for i := 0; i < series.Len(); i++ {
+			value := series.ValueAt(i)
+			if math.IsNaN(value) {
+				continue
+			}
+
+			if current := g.values.ValueAt(i); !math.IsNaN(current) {
+				value = fn(current, value)
+			}
+
+			g.values.SetValueAt(i, value)
+		}
+	}
+
+	aggregated := make([]*ts.Series, len(ids))
+	for i, id := range ids {
+		g := groups[id]
+		aggregated[i] = ts.NewSeries(ctx, id, g.start, g.values, g.tags)
+	}
+
+	return aggregated
+}
+
+// groupTags returns the tags kept by the hinted aggregation, without always drops the metric name
+func (h *FetchHints) groupTags(tags models.Tags) models.Tags {
+	if h.By {
+		grouped := make(models.Tags, len(h.Grouping))
+		for _, name := range h.Grouping {
+			if value, ok := tags[name]; ok {
+				grouped[name] = value
+			}
+		}
+
+		return grouped
+	}
+
+	grouped := make(models.Tags, len(tags))
+	for name, value := range tags {
+		grouped[name] = value
+	}
+
+	delete(grouped, models.MetricName)
+	for _, name := range h.Grouping {
+		delete(grouped, name)
+	}
+
+	return grouped
+}
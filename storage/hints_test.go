@@ -0,0 +1,173 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/generated/proto/prompb"
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesValues(series *ts.Series) []float64 {
+	values := make([]float64, series.Len())
+	for i := range values {
+		values[i] = series.ValueAt(i)
+	}
+
+	return values
+}
+
+func TestPromReadQueryToM3Hints(t *testing.T) {
+	query := &prompb.Query{
+		StartTimestampMs: 1000,
+		EndTimestampMs:   61000,
+		Hints: &prompb.ReadHints{
+			StepMs:   15000,
+			Func:     "sum",
+			StartMs:  1000,
+			EndMs:    61000,
+			Grouping: []string{"job"},
+			By:       true,
+		},
+	}
+
+	data, err := query.Marshal()
+	require.NoError(t, err)
+	decoded := &prompb.Query{}
+	require.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, query, decoded)
+
+	fetchQuery, err := PromReadQueryToM3(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, &FetchHints{
+		Start:    TimestampToTime(1000),
+		End:      TimestampToTime(61000),
+		Step:     15 * time.Second,
+		Func:     "sum",
+		Grouping: []string{"job"},
+		By:       true,
+	}, fetchQuery.Hints)
+
+	decoded.Hints = nil
+	fetchQuery, err = PromReadQueryToM3(decoded)
+	require.NoError(t, err)
+	assert.Nil(t, fetchQuery.Hints)
+}
+
+func TestCanDownsample(t *testing.T) {
+	var hints *FetchHints
+	assert.False(t, hints.CanDownsample())
+	assert.False(t, (&FetchHints{}).CanDownsample())
+	assert.False(t, (&FetchHints{Step: time.Minute, Range: 5 * time.Minute, Func: "rate"}).CanDownsample())
+	assert.True(t, (&FetchHints{Step: time.Minute}).CanDownsample())
+}
+
+func TestDownsample(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	hints := &FetchHints{Start: now, End: now.Add(8 * time.Minute), Step: time.Minute}
+	datapoints := []ts.Datapoint{
+		{Timestamp: now.Add(30 * time.Second), Value: 1},
+		{Timestamp: now.Add(2*time.Minute + 30*time.Second), Value: 2},
+		{Timestamp: now.Add(2*time.Minute + 50*time.Second), Value: 3},
+		{Timestamp: now.Add(4 * time.Minute), Value: 4},
+	}
+
+	values, start := hints.Downsample(context.TODO(), datapoints, 2*time.Minute)
+	assert.Equal(t, now.Add(2*time.Minute), start)
+	assert.Equal(t, int(time.Minute/time.Millisecond), values.MillisPerStep())
+	// Steps at 2m to 8m, each takes the latest datapoint no older than two minutes
+	series := ts.NewSeries(context.TODO(), "foo", start, values, nil)
+	nan := math.NaN()
+	expected := []float64{1, 3, 4, 4, 4, nan, nan}
+	actual := seriesValues(series)
+	require.Len(t, actual, len(expected))
+	for i, v := range expected {
+		if math.IsNaN(v) {
+			assert.True(t, math.IsNaN(actual[i]), "expected NaN at step %d", i)
+			continue
+		}
+
+		assert.Equal(t, v, actual[i], "step %d", i)
+	}
+
+	samples := SeriesToPromSamples(series)
+	assert.Len(t, samples, 5)
+
+	// The lookback of the hints takes precedence over the default one
+	hints.Lookback = 2 * time.Minute
+	values, start = hints.Downsample(context.TODO(), datapoints, 5*time.Minute)
+	assert.Equal(t, now.Add(2*time.Minute), start)
+	assert.Equal(t, len(expected), values.Len())
+}
+
+func TestPreAggregate(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now().Truncate(time.Hour)
+	newSeries := func(tags models.Tags, values ...float64) *ts.Series {
+		vals := ts.NewValues(ctx, 60000, len(values))
+		for i, v := range values {
+			vals.SetValueAt(i, v)
+		}
+
+		return ts.NewSeries(ctx, tags.ID(), now, vals, tags)
+	}
+
+	nan := math.NaN()
+	seriesList := []*ts.Series{
+		newSeries(models.Tags{models.MetricName: "up", "job": "a", "instance": "1"}, 1, nan),
+		newSeries(models.Tags{models.MetricName: "up", "job": "a", "instance": "2"}, 2, 3),
+		newSeries(models.Tags{models.MetricName: "up", "job": "b", "instance": "1"}, 5, 6),
+	}
+
+	by := &FetchHints{Step: time.Minute, Func: "sum", Grouping: []string{"job"}, By: true}
+	aggregated := by.PreAggregate(ctx, seriesList)
+	require.Len(t, aggregated, 2)
+	assert.Equal(t, models.Tags{"job": "a"}, aggregated[0].Tags)
+	assert.Equal(t, []float64{3, 3}, seriesValues(aggregated[0]))
+	assert.Equal(t, models.Tags{"job": "b"}, aggregated[1].Tags)
+	assert.Equal(t, []float64{5, 6}, seriesValues(aggregated[1]))
+
+	without := &FetchHints{Step: time.Minute, Func: "max", Grouping: []string{"job"}}
+	aggregated = without.PreAggregate(ctx, seriesList)
+	require.Len(t, aggregated, 2)
+	assert.Equal(t, models.Tags{"instance": "1"}, aggregated[0].Tags)
+	assert.Equal(t, []float64{5, 6}, seriesValues(aggregated[0]))
+	assert.Equal(t, models.Tags{"instance": "2"}, aggregated[1].Tags)
+	assert.Equal(t, []float64{2, 3}, seriesValues(aggregated[1]))
+
+	all := &FetchHints{Step: time.Minute, Func: "min", By: true}
+	aggregated = all.PreAggregate(ctx, seriesList)
+	require.Len(t, aggregated, 1)
+	assert.Equal(t, models.Tags{}, aggregated[0].Tags)
+	assert.Equal(t, []float64{1, 3}, seriesValues(aggregated[0]))
+
+	// Counting pre-aggregated series would change the result
+	count := &FetchHints{Step: time.Minute, Func: "count", By: true}
+	assert.Equal(t, seriesList, count.PreAggregate(ctx, seriesList))
+}
@@ -22,6 +22,7 @@ package storage
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/m3db/m3coordinator/models"
 
@@ -75,29 +76,121 @@ func FetchOptionsToM3Options(fetchOptions *FetchOptions, fetchQuery *FetchQuery)
 // FetchQueryToM3Query converts an m3coordinator fetch query to an M3 query
 func FetchQueryToM3Query(fetchQuery *FetchQuery) (index.Query, error) {
 	matchers := fetchQuery.TagMatchers
-	idxQueries := make([]idx.Query, len(matchers))
-	var err error
-	for i, matcher := range matchers {
-		idxQueries[i], err = matcherToQuery(matcher)
+	idxQueries := make([]idx.Query, 0, len(matchers)+1)
+	onlyNegations := len(matchers) > 0
+	for _, matcher := range matchers {
+		q, negation, err := matcherToQuery(matcher)
 		if err != nil {
 			return index.Query{}, err
 		}
+
+		idxQueries = append(idxQueries, q)
+		onlyNegations = onlyNegations && negation
+	}
+
+	// NB: the index cannot evaluate a conjunction made only of negations, so add a query matching
+	// every series for the negations to exclude from
+	if onlyNegations {
+		all, err := allQuery()
+		if err != nil {
+			return index.Query{}, err
+		}
+
+		idxQueries = append(idxQueries, all)
 	}
 
 	q, err := idx.NewConjunctionQuery(idxQueries...)
 	return index.Query{Query: q}, err
 }
 
-func matcherToQuery(matcher *models.Matcher) (idx.Query, error) {
+// matcherToQuery converts a matcher to an index query, returning true if the query is a negation.
+// Prometheus treats an empty value as an absent label, so matching an empty value matches series
+// without a value for the label and not matching it matches series with any value. Regexp matchers
+// which match an empty value, such as foo=~"bar|" or foo!~".+", match series without the label too.
+func matcherToQuery(matcher *models.Matcher) (idx.Query, bool, error) {
+	name := []byte(matcher.Name)
 	switch matcher.Type {
-	case models.MatchRegexp:
-		return idx.NewRegexpQuery([]byte(matcher.Name), []byte(matcher.Value))
+	case models.MatchEqual, models.MatchNotEqual:
+		negate := matcher.Type == models.MatchNotEqual
+		if matcher.Value == "" {
+			return emptyValueQuery(name, negate)
+		}
+
+		q := idx.NewTermQuery(name, []byte(matcher.Value))
+		if negate {
+			return idx.NewNegationQuery(q), true, nil
+		}
+
+		return q, false, nil
 
-	case models.MatchEqual:
-		return idx.NewTermQuery([]byte(matcher.Name), []byte(matcher.Value)), nil
+	case models.MatchRegexp, models.MatchNotRegexp:
+		negate := matcher.Type == models.MatchNotRegexp
+		if matcher.Value == "" {
+			return emptyValueQuery(name, negate)
+		}
+
+		q, err := idx.NewRegexpQuery(name, []byte(matcher.Value))
+		if err != nil {
+			return idx.Query{}, false, err
+		}
+
+		if negate {
+			q = idx.NewNegationQuery(q)
+		}
+
+		matchesEmpty, err := regexpMatchesEmpty(matcher.Value)
+		if err != nil {
+			return idx.Query{}, false, err
+		}
+
+		if matchesEmpty == negate {
+			return q, negate, nil
+		}
+
+		absent, _, err := emptyValueQuery(name, false)
+		if err != nil {
+			return idx.Query{}, false, err
+		}
+
+		q, err = idx.NewDisjunctionQuery(q, absent)
+		if err != nil {
+			return idx.Query{}, false, err
+		}
+
+		// NB: series without the label are matched by a negation, so the disjunction is one as well
+		return q, true, nil
 
 	default:
-		return idx.Query{}, fmt.Errorf("unsupported query type %v", matcher)
+		return idx.Query{}, false, fmt.Errorf("unsupported query type %v", matcher)
 
 	}
 }
+
+// regexpMatchesEmpty is true when the regexp matches an empty value, Prometheus anchors regexps to the whole value
+func regexpMatchesEmpty(value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + value + ")$")
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(""), nil
+}
+
+// emptyValueQuery matches series without a value for the label, or with any value when negated
+func emptyValueQuery(name []byte, negate bool) (idx.Query, bool, error) {
+	hasValue, err := idx.NewRegexpQuery(name, []byte(".+"))
+	if err != nil {
+		return idx.Query{}, false, err
+	}
+
+	if negate {
+		return hasValue, false, nil
+	}
+
+	return idx.NewNegationQuery(hasValue), true, nil
+}
+
+// allQuery matches every series, series always carry a metric name so any value for it matches them all
+func allQuery() (idx.Query, error) {
+	return idx.NewRegexpQuery([]byte(models.MetricName), []byte(".*"))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/m3db/m3coordinator/models"
+
+	"github.com/m3db/m3db/storage/index"
+	"github.com/m3db/m3ninx/idx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustMatcher(t *testing.T, matchType models.MatchType, name, value string) *models.Matcher {
+	matcher, err := models.NewMatcher(matchType, name, value)
+	require.NoError(t, err)
+	return matcher
+}
+
+func mustRegexp(t *testing.T, name, value string) idx.Query {
+	q, err := idx.NewRegexpQuery([]byte(name), []byte(value))
+	require.NoError(t, err)
+	return q
+}
+
+func mustDisjunction(t *testing.T, queries ...idx.Query) idx.Query {
+	q, err := idx.NewDisjunctionQuery(queries...)
+	require.NoError(t, err)
+	return q
+}
+
+func mustConjunction(t *testing.T, queries ...idx.Query) index.Query {
+	q, err := idx.NewConjunctionQuery(queries...)
+	require.NoError(t, err)
+	return index.Query{Query: q}
+}
+
+func TestFetchQueryToM3Query(t *testing.T) {
+	term := func(name, value string) idx.Query {
+		return idx.NewTermQuery([]byte(name), []byte(value))
+	}
+
+	all := mustRegexp(t, models.MetricName, ".*")
+	absent := func(name string) idx.Query {
+		return idx.NewNegationQuery(mustRegexp(t, name, ".+"))
+	}
+
+	// Negated regexps which do not match every value also match series without the label
+	notHost := mustDisjunction(t, idx.NewNegationQuery(mustRegexp(t, "host", "a.*")), absent("host"))
+	tests := []struct {
+		name     string
+		matchers models.Matchers
+		expected index.Query
+	}{
+		{
+			name:     "equal",
+			matchers: models.Matchers{mustMatcher(t, models.MatchEqual, "job", "test")},
+			expected: mustConjunction(t, term("job", "test")),
+		},
+		{
+			name: "negations with a positive matcher",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchEqual, "job", "test"),
+				mustMatcher(t, models.MatchNotEqual, "env", "prod"),
+				mustMatcher(t, models.MatchNotRegexp, "host", "a.*"),
+			},
+			expected: mustConjunction(t,
+				term("job", "test"),
+				idx.NewNegationQuery(term("env", "prod")),
+				notHost,
+			),
+		},
+		{
+			name: "only negations",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchNotEqual, "job", "test"),
+				mustMatcher(t, models.MatchNotRegexp, "host", "a.*"),
+			},
+			expected: mustConjunction(t,
+				idx.NewNegationQuery(term("job", "test")),
+				notHost,
+				all,
+			),
+		},
+		{
+			name:     "empty value means absent label",
+			matchers: models.Matchers{mustMatcher(t, models.MatchEqual, "job", "")},
+			expected: mustConjunction(t, idx.NewNegationQuery(mustRegexp(t, "job", ".+")), all),
+		},
+		{
+			name: "not empty value means present label",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchNotEqual, "job", ""),
+				mustMatcher(t, models.MatchNotRegexp, "env", ""),
+			},
+			expected: mustConjunction(t, mustRegexp(t, "job", ".+"), mustRegexp(t, "env", ".+")),
+		},
+		{
+			name: "empty regexp with a positive matcher",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchRegexp, "env", ""),
+				mustMatcher(t, models.MatchEqual, "job", "test"),
+			},
+			expected: mustConjunction(t, idx.NewNegationQuery(mustRegexp(t, "env", ".+")), term("job", "test")),
+		},
+		{
+			name: "regexp matching an empty value",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchEqual, "job", "test"),
+				mustMatcher(t, models.MatchRegexp, "env", "prod|"),
+			},
+			expected: mustConjunction(t, term("job", "test"), mustDisjunction(t, mustRegexp(t, "env", "prod|"), absent("env"))),
+		},
+		{
+			name:     "only a regexp matching an empty value",
+			matchers: models.Matchers{mustMatcher(t, models.MatchRegexp, "env", ".*")},
+			expected: mustConjunction(t, mustDisjunction(t, mustRegexp(t, "env", ".*"), absent("env")), all),
+		},
+		{
+			name:     "negated regexp matching every value",
+			matchers: models.Matchers{mustMatcher(t, models.MatchNotRegexp, "env", ".+")},
+			expected: mustConjunction(t, mustDisjunction(t, absent("env"), absent("env")), all),
+		},
+		{
+			name: "regexps not matching an empty value",
+			matchers: models.Matchers{
+				mustMatcher(t, models.MatchRegexp, "env", "prod|dev"),
+				mustMatcher(t, models.MatchNotRegexp, "job", ".*"),
+			},
+			expected: mustConjunction(t, mustRegexp(t, "env", "prod|dev"), idx.NewNegationQuery(mustRegexp(t, "job", ".*"))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := FetchQueryToM3Query(&FetchQuery{TagMatchers: tt.matchers})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q)
+		})
+	}
+}
+
+func TestFetchQueryToM3QueryUnsupported(t *testing.T) {
+	_, err := FetchQueryToM3Query(&FetchQuery{TagMatchers: models.Matchers{{Type: models.MatchType(100), Name: "a", Value: "b"}}})
+	assert.Error(t, err)
+}
@@ -43,6 +43,19 @@ const (
 	TypeMultiDC
 )
 
+func (t Type) String() string {
+	switch t {
+	case TypeLocalDC:
+		return "local"
+	case TypeRemoteDC:
+		return "remote"
+	case TypeMultiDC:
+		return "multi"
+	default:
+		return "unknown"
+	}
+}
+
 // Storage provides an interface for reading and writing to the tsdb
 type Storage interface {
 	Querier
@@ -53,6 +66,12 @@ type Storage interface {
 	Close() error
 }
 
+// Named is implemented by storages with a configured name, filters can route queries to them by name
+type Named interface {
+	// Name is the configured name of the storage
+	Name() string
+}
+
 // Query is an interface for a M3DB query
 type Query interface {
 	fmt.Stringer
@@ -69,6 +88,23 @@ type FetchQuery struct {
 	TagMatchers models.Matchers `json:"matchers"`
 	Start       time.Time       `json:"start"`
 	End         time.Time       `json:"end"`
+	Interval    time.Duration   `json:"interval"`
+	Hints       *FetchHints     `json:"hints,omitempty"`
+}
+
+// FetchHints describes what the caller does with the fetched series, allowing storages to
+// downsample or pre-aggregate before returning them
+type FetchHints struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Step     time.Duration `json:"step"`
+	Func     string        `json:"func"`
+	Grouping []string      `json:"grouping"`
+	By       bool          `json:"by"`
+	Range    time.Duration `json:"range"`
+	// Lookback is how far back the caller looks for the datapoint of a step, prometheus does not send
+	// it so it is set from configuration
+	Lookback time.Duration `json:"lookback"`
 }
 
 func (q *FetchQuery) String() string {
@@ -92,6 +128,30 @@ type Querier interface {
 		ctx context.Context, query *FetchQuery, options *FetchOptions) (BlockResult, error)
 }
 
+// RawQuerier is implemented by storages which can stream the raw datapoints of each series as it is read,
+// so that callers never hold the full result in memory
+type RawQuerier interface {
+	// FetchRaw calls fn with every series matching the query, datapoints are only valid during the call
+	FetchRaw(
+		ctx context.Context, query *FetchQuery, options *FetchOptions, fn RawSeriesFn) (RawResult, error)
+}
+
+// RawResult is the result of streaming raw series, the series themselves are passed to the callback
+type RawResult struct {
+	// Warnings are the errors of the stores left out of the result
+	Warnings []Warning
+}
+
+// RawSeriesFn is called with the tags and datapoints of a single series
+type RawSeriesFn func(tags models.Tags, datapoints DatapointIter) error
+
+// DatapointIter iterates over the datapoints of a series in time order
+type DatapointIter interface {
+	Next() bool
+	Current() ts.Datapoint
+	Err() error
+}
+
 // WriteQuery represents the input timeseries that is written to M3DB
 type WriteQuery struct {
 	Raw        string
@@ -114,6 +174,21 @@ type Appender interface {
 // SearchResults is the result from a search
 type SearchResults struct {
 	Metrics models.Metrics
+	// Truncated is set when more metrics matched the search than were returned
+	Truncated bool
+	// Warnings are the errors of the stores left out of the results
+	Warnings []Warning
+}
+
+// ApplyLimit drops the metrics beyond the limit and marks the results truncated, a limit of zero
+// or less keeps every metric
+func (r *SearchResults) ApplyLimit(limit int) {
+	if limit <= 0 || len(r.Metrics) <= limit {
+		return
+	}
+
+	r.Metrics = r.Metrics[:limit]
+	r.Truncated = true
 }
 
 // FetchResult provides a fetch result and meta information
@@ -121,6 +196,10 @@ type FetchResult struct {
 	SeriesList []*ts.Series // The aggregated list of results across all underlying storage calls
 	LocalOnly  bool
 	HasNext    bool
+	// Partial is set when some stores failed and were left out of the result
+	Partial bool
+	// Warnings are the errors of the stores left out of the result
+	Warnings []Warning
 }
 
 // QueryResult is the result from a query
@@ -132,4 +211,8 @@ type QueryResult struct {
 // BlockResult is the result from a block query
 type BlockResult struct {
 	Blocks []Block
+	// Partial is set when some stores failed and were left out of the result
+	Partial bool
+	// Warnings are the errors of the stores left out of the result
+	Warnings []Warning
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package local
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3metrics/policy"
+)
+
+// NamespaceType is whether a namespace holds raw or downsampled datapoints
+type NamespaceType int
+
+const (
+	// UnaggregatedNamespace holds raw datapoints, writes go to it
+	UnaggregatedNamespace NamespaceType = iota
+	// AggregatedNamespace holds datapoints downsampled to its resolution
+	AggregatedNamespace
+)
+
+var validNamespaceTypes = []NamespaceType{UnaggregatedNamespace, AggregatedNamespace}
+
+func (t NamespaceType) String() string {
+	switch t {
+	case UnaggregatedNamespace:
+		return "unaggregated"
+	case AggregatedNamespace:
+		return "aggregated"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a namespace type from its name, namespaces are unaggregated by default
+func (t *NamespaceType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		*t = UnaggregatedNamespace
+		return nil
+	}
+
+	for _, valid := range validNamespaceTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid namespace type %s, valid types are: %v", str, validNamespaceTypes)
+}
+
+// Namespace is an M3DB namespace and the storage policy of the data it holds
+type Namespace struct {
+	Name          string
+	StoragePolicy policy.StoragePolicy
+	Type          NamespaceType
+}
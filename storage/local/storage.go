@@ -22,15 +22,20 @@ package local
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/m3db/m3coordinator/errors"
 	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/policy/resolver"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/ts/m3db"
 	"github.com/m3db/m3coordinator/util/execution"
 
 	"github.com/m3db/m3db/client"
+	"github.com/m3db/m3db/encoding"
+	"github.com/m3db/m3metrics/policy"
 	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 )
@@ -40,14 +45,210 @@ const (
 )
 
 type localStorage struct {
-	session       client.Session
+	session    client.Session
+	namespaces map[policy.StoragePolicy]ident.ID
+	// writeNamespace is the unaggregated namespace, all writes go to it
+	writeNamespace ident.ID
+	resolver       resolver.PolicyResolver
+}
+
+// NewStorage creates a new local Storage instance for a single unaggregated namespace retaining data forever.
+func NewStorage(session client.Session, namespace string, resolution time.Duration) (storage.Storage, error) {
+	sp := policy.NewStoragePolicy(resolution, xtime.Millisecond, 0)
+	namespaces := []Namespace{{Name: namespace, StoragePolicy: sp, Type: UnaggregatedNamespace}}
+	return NewMultiNamespaceStorage(session, namespaces, resolver.NewRangeResolver([]policy.StoragePolicy{sp}, resolver.RangeResolverOptions{}))
+}
+
+// NewMultiNamespaceStorage creates a new local Storage instance reading from several namespaces, the resolver
+// picks the namespace serving each part of a query from the storage policies of the namespaces. Writes go to
+// the only unaggregated namespace.
+func NewMultiNamespaceStorage(session client.Session, namespaces []Namespace, policyResolver resolver.PolicyResolver) (storage.Storage, error) {
+	if len(namespaces) == 0 {
+		return nil, errors.ErrNoNamespaces
+	}
+
+	s := &localStorage{
+		session:    session,
+		namespaces: make(map[policy.StoragePolicy]ident.ID, len(namespaces)),
+		resolver:   policyResolver,
+	}
+
+	var unaggregated string
+	for _, ns := range namespaces {
+		if _, ok := s.namespaces[ns.StoragePolicy]; ok {
+			return nil, fmt.Errorf("duplicate storage policy %s for namespace %s", ns.StoragePolicy, ns.Name)
+		}
+
+		id := ident.StringID(ns.Name)
+		s.namespaces[ns.StoragePolicy] = id
+		if ns.Type != UnaggregatedNamespace {
+			continue
+		}
+
+		if s.writeNamespace != nil {
+			return nil, fmt.Errorf("namespaces %s and %s are both unaggregated", unaggregated, ns.Name)
+		}
+
+		s.writeNamespace = id
+		unaggregated = ns.Name
+	}
+
+	if s.writeNamespace == nil {
+		return nil, errors.ErrNoUnaggregatedNamespace
+	}
+
+	return s, nil
+}
+
+// fetchRange is the part of a query served by a single namespace
+type fetchRange struct {
 	namespace     ident.ID
-	millisPerStep int64
+	storagePolicy policy.StoragePolicy
+	query         *storage.FetchQuery
+}
+
+// resolve splits the query into the ranges of the namespaces serving it
+func (s *localStorage) resolve(ctx context.Context, query *storage.FetchQuery) ([]fetchRange, error) {
+	requests, err := s.resolver.Resolve(ctx, query.TagMatchers, query.Start, query.End)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []fetchRange
+	for _, request := range requests {
+		for _, r := range request.Ranges {
+			namespace, ok := s.namespaces[r.StoragePolicy]
+			if !ok {
+				return nil, fmt.Errorf("no namespace for storage policy %s", r.StoragePolicy)
+			}
+
+			rangeQuery := *query
+			rangeQuery.Start = r.Start
+			rangeQuery.End = r.End
+			ranges = append(ranges, fetchRange{
+				namespace:     namespace,
+				storagePolicy: r.StoragePolicy,
+				query:         &rangeQuery,
+			})
+		}
+	}
+
+	return ranges, nil
 }
 
-// NewStorage creates a new local Storage instance.
-func NewStorage(session client.Session, namespace string, resolution time.Duration) storage.Storage {
-	return &localStorage{session: session, namespace: ident.StringID(namespace), millisPerStep: stepFromResolution(resolution)}
+// fetch fetches every range of the query from its namespace in parallel, the result must be closed by the caller
+func (s *localStorage) fetch(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*fetchResult, error) {
+	ranges, err := s.resolve(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fetchResult{ranges: ranges, iters: make([]encoding.SeriesIterators, len(ranges))}
+	requests := make([]execution.Request, len(ranges))
+	for i := range ranges {
+		requests[i] = &fetchRequest{store: s, options: options, result: result, idx: i}
+	}
+
+	if err := execution.ExecuteParallel(ctx, requests); err != nil {
+		result.close()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// fetchRequest fetches a single range, its iterators are stored at the index of the range
+type fetchRequest struct {
+	store   *localStorage
+	options *storage.FetchOptions
+	result  *fetchResult
+	idx     int
+}
+
+func (r *fetchRequest) Process(ctx context.Context) error {
+	rng := r.result.ranges[r.idx]
+	m3query, err := storage.FetchQueryToM3Query(rng.query)
+	if err != nil {
+		return err
+	}
+
+	opts := storage.FetchOptionsToM3Options(r.options, rng.query)
+	// TODO (nikunj): Handle second return param
+	iters, _, err := r.store.session.FetchTagged(rng.namespace, m3query, opts)
+	if err != nil {
+		return err
+	}
+
+	r.result.iters[r.idx] = iters
+	return nil
+}
+
+// fetchResult holds the series iterators of every range of a query
+type fetchResult struct {
+	ranges []fetchRange
+	iters  []encoding.SeriesIterators
+}
+
+func (r *fetchResult) close() {
+	for _, iters := range r.iters {
+		if iters != nil {
+			iters.Close()
+		}
+	}
+}
+
+// resolution is the coarsest resolution of the ranges, stitched series are consolidated to it when the
+// query has no interval
+func (r *fetchResult) resolution() time.Duration {
+	var resolution time.Duration
+	for _, rng := range r.ranges {
+		if window := rng.storagePolicy.Resolution().Window; window > resolution {
+			resolution = window
+		}
+	}
+
+	return resolution
+}
+
+// namespaceSeries is the part of a series held by a single namespace
+type namespaceSeries struct {
+	namespace ident.ID
+	iter      encoding.SeriesIterator
+}
+
+// stitchedSeries is a series stitched across the namespaces holding it, parts are in time order
+type stitchedSeries struct {
+	id    ident.ID
+	parts []namespaceSeries
+}
+
+// stitch groups the iterators of every range by series, series keep the order they are first seen in
+func (r *fetchResult) stitch() []*stitchedSeries {
+	var (
+		stitched []*stitchedSeries
+		byID     = make(map[string]*stitchedSeries)
+	)
+
+	for i, iters := range r.iters {
+		if iters == nil {
+			continue
+		}
+
+		namespace := r.ranges[i].namespace
+		for _, iter := range iters.Iters() {
+			id := iter.ID()
+			series, ok := byID[id.String()]
+			if !ok {
+				series = &stitchedSeries{id: id}
+				byID[id.String()] = series
+				stitched = append(stitched, series)
+			}
+
+			series.parts = append(series.parts, namespaceSeries{namespace: namespace, iter: iter})
+		}
+	}
+
+	return stitched
 }
 
 func (s *localStorage) Fetch(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.FetchResult, error) {
@@ -60,42 +261,52 @@ func (s *localStorage) Fetch(ctx context.Context, query *storage.FetchQuery, opt
 	default:
 	}
 
-	m3query, err := storage.FetchQueryToM3Query(query)
+	fetched, err := s.fetch(ctx, query, options)
 	if err != nil {
 		return nil, err
 	}
 
-	opts := storage.FetchOptionsToM3Options(options, query)
-	// TODO (nikunj): Handle second return param
-	iters, _, err := s.session.FetchTagged(s.namespace, m3query, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	defer iters.Close()
-
-	seriesList := make([]*ts.Series, iters.Len())
-	for i, iter := range iters.Iters() {
-		metric, err := storage.FromM3IdentToMetric(s.namespace, iter.ID(), iter.Tags())
+	defer fetched.close()
+
+	// Only fetch a datapoint per step when prometheus hints at how the series are evaluated
+	hints := query.Hints
+	downsample := hints.CanDownsample()
+	resolution := fetched.resolution()
+	stitched := fetched.stitch()
+	seriesList := make([]*ts.Series, len(stitched))
+	for i, series := range stitched {
+		first := series.parts[0]
+		metric, err := storage.FromM3IdentToMetric(first.namespace, series.id, first.iter.Tags())
 		if err != nil {
 			return nil, err
 		}
 
 		result := make([]ts.Datapoint, 0, initRawFetchAllocSize)
-		for iter.Next() {
-			dp, _, _ := iter.Current()
-			result = append(result, ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value})
+		for _, part := range series.parts {
+			iter := part.iter
+			for iter.Next() {
+				dp, _, _ := iter.Current()
+				result = append(result, ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value})
+			}
+
+			if err := iter.Err(); err != nil {
+				return nil, err
+			}
 		}
 
-		values := ts.NewValues(ctx, int(s.millisPerStep), len(result))
-
-		// TODO (nikunj): Figure out consolidation here
-		for i, v := range result {
-			values.SetValueAt(i, v.Value)
+		if downsample {
+			// The hints carry the configured lookback of the prometheus servers, which default to this one
+			values, start := hints.Downsample(ctx, result, m3db.DefaultLookbackDuration)
+			seriesList[i] = ts.NewSeries(ctx, metric.ID, start, values, metric.Tags)
+			continue
 		}
 
-		series := ts.NewSeries(ctx, metric.ID, query.Start, values, metric.Tags)
-		seriesList[i] = series
+		values := consolidate(ctx, query, resolution, result)
+		seriesList[i] = ts.NewSeries(ctx, metric.ID, query.Start, values, metric.Tags)
+	}
+
+	if downsample {
+		seriesList = hints.PreAggregate(ctx, seriesList)
 	}
 
 	return &storage.FetchResult{
@@ -103,6 +314,96 @@ func (s *localStorage) Fetch(ctx context.Context, query *storage.FetchQuery, opt
 	}, nil
 }
 
+// consolidate lays datapoints onto the steps of the query interval between the start and end of the query,
+// or onto those of the resolution when the query has no interval. Each step takes its most recent datapoint
+// as prometheus does, it is the only consolidation supported
+func consolidate(ctx context.Context, query *storage.FetchQuery, resolution time.Duration, datapoints []ts.Datapoint) ts.Values {
+	step := resolution
+	if query.Interval > 0 {
+		step = query.Interval
+	}
+
+	millisPerStep := stepFromResolution(step)
+	stepSize := time.Duration(millisPerStep) * time.Millisecond
+	numSteps := 0
+	if stepSize > 0 && query.End.After(query.Start) {
+		numSteps = int((query.End.Sub(query.Start) + stepSize - 1) / stepSize)
+	}
+
+	return ts.Consolidate(ctx, datapoints, query.Start, int(millisPerStep), numSteps, ts.TakeLast, m3db.DefaultLookbackDuration)
+}
+
+func (s *localStorage) FetchRaw(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions, fn storage.RawSeriesFn) (storage.RawResult, error) {
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return storage.RawResult{}, ctx.Err()
+	case <-options.KillChan:
+		return storage.RawResult{}, errors.ErrQueryInterrupted
+	default:
+	}
+
+	fetched, err := s.fetch(ctx, query, options)
+	if err != nil {
+		return storage.RawResult{}, err
+	}
+
+	defer fetched.close()
+
+	// NB: series are still compressed here, each one is only decoded as the callback consumes it
+	for _, series := range fetched.stitch() {
+		if err := ctx.Err(); err != nil {
+			return storage.RawResult{}, err
+		}
+
+		tags, err := storage.FromIdentTagIteratorToTags(series.parts[0].iter.Tags())
+		if err != nil {
+			return storage.RawResult{}, err
+		}
+
+		if err := fn(tags, &datapointIter{parts: series.parts}); err != nil {
+			return storage.RawResult{}, err
+		}
+	}
+
+	return storage.RawResult{}, nil
+}
+
+// datapointIter adapts the M3DB series iterators of a stitched series to a storage datapoint iterator
+type datapointIter struct {
+	parts []namespaceSeries
+	idx   int
+}
+
+func (it *datapointIter) Next() bool {
+	for ; it.idx < len(it.parts); it.idx++ {
+		iter := it.parts[it.idx].iter
+		if iter.Next() {
+			return true
+		}
+
+		// Stay on the failed part so that Err reports it
+		if iter.Err() != nil {
+			return false
+		}
+	}
+
+	return false
+}
+
+func (it *datapointIter) Current() ts.Datapoint {
+	dp, _, _ := it.parts[it.idx].iter.Current()
+	return ts.Datapoint{Timestamp: dp.Timestamp, Value: dp.Value}
+}
+
+func (it *datapointIter) Err() error {
+	if it.idx < len(it.parts) {
+		return it.parts[it.idx].iter.Err()
+	}
+
+	return nil
+}
+
 func (s *localStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
 	// Check if the query was interrupted.
 	select {
@@ -113,31 +414,48 @@ func (s *localStorage) FetchTags(ctx context.Context, query *storage.FetchQuery,
 	default:
 	}
 
-	m3query, err := storage.FetchQueryToM3Query(query)
+	ranges, err := s.resolve(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	opts := storage.FetchOptionsToM3Options(options, query)
-	// TODO (juchan): Handle second return param
-	iter, _, err := s.session.FetchTaggedIDs(s.namespace, m3query, opts)
-	if err != nil {
-		return nil, err
-	}
+	var (
+		result = &storage.SearchResults{}
+		seen   = make(map[string]struct{})
+	)
+	for _, rng := range ranges {
+		m3query, err := storage.FetchQueryToM3Query(rng.query)
+		if err != nil {
+			return nil, err
+		}
 
-	var metrics models.Metrics
-	for iter.Next() {
-		m, err := storage.FromM3IdentToMetric(iter.Current())
+		opts := storage.FetchOptionsToM3Options(options, rng.query)
+		iter, exhaustive, err := s.session.FetchTaggedIDs(rng.namespace, m3query, opts)
 		if err != nil {
 			return nil, err
 		}
 
-		metrics = append(metrics, m)
+		result.Truncated = result.Truncated || !exhaustive
+
+		for iter.Next() {
+			m, err := storage.FromM3IdentToMetric(iter.Current())
+			if err != nil {
+				return nil, err
+			}
+
+			// A series held by several namespaces is only returned once
+			if _, ok := seen[m.ID]; ok {
+				continue
+			}
+
+			seen[m.ID] = struct{}{}
+			result.Metrics = append(result.Metrics, m)
+		}
 	}
 
-	return &storage.SearchResults{
-		Metrics: metrics,
-	}, nil
+	// Each namespace is limited on its own so the limit is applied again across them
+	result.ApplyLimit(options.Limit)
+	return result, nil
 }
 
 func (s *localStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
@@ -158,6 +476,7 @@ func (s *localStorage) Write(ctx context.Context, query *storage.WriteQuery) err
 		annotation: query.Annotation,
 		unit:       query.Unit,
 		id:         id,
+		tags:       query.Tags,
 	}
 
 	requests := make([]execution.Request, len(query.Datapoints))
@@ -173,14 +492,87 @@ func (s *localStorage) Type() storage.Type {
 
 func (s *localStorage) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
-	return storage.BlockResult{}, errors.ErrNotImplemented
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return storage.BlockResult{}, ctx.Err()
+	case <-options.KillChan:
+		return storage.BlockResult{}, errors.ErrQueryInterrupted
+	default:
+	}
+
+	if query.Interval <= 0 {
+		return storage.BlockResult{}, errors.ErrInvalidStepSize
+	}
+
+	bounds := storage.Bounds{
+		Start:    query.Start,
+		End:      query.End,
+		StepSize: query.Interval,
+	}
+
+	// NB: the first step needs datapoints from within the lookback and the last step is inclusive
+	fetchQuery := *query
+	fetchQuery.Start = query.Start.Add(-1 * m3db.DefaultLookbackDuration)
+	fetchQuery.End = query.End.Add(time.Nanosecond)
+	fetched, err := s.fetch(ctx, &fetchQuery, options)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	defer fetched.close()
+
+	// NB: every namespace block spans the whole query so that the blocks align, each iterator only holds
+	// the datapoints of its range and consolidation stitches them together
+	stitched := fetched.stitch()
+	multiNamespaceSeriesList := make([]m3db.MultiNamespaceSeries, len(stitched))
+	for i, series := range stitched {
+		multiNamespaceSeries := make(m3db.MultiNamespaceSeries, len(series.parts))
+		for j, part := range series.parts {
+			multiNamespaceSeries[j] = m3db.SeriesBlocks{
+				ID:        series.id,
+				Namespace: part.namespace.String(),
+				Blocks: []m3db.SeriesBlock{
+					{
+						Start:          fetchQuery.Start,
+						End:            fetchQuery.End,
+						SeriesIterator: part.iter,
+					},
+				},
+			}
+		}
+
+		multiNamespaceSeriesList[i] = multiNamespaceSeries
+	}
+
+	multiSeriesBlocks, err := m3db.SeriesBlockToMultiSeriesBlocks(multiNamespaceSeriesList, nil)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	// Always return a block so that downstream nodes see the bounds even when nothing matched
+	if len(multiSeriesBlocks) == 0 {
+		multiSeriesBlocks = m3db.MultiSeriesBlocks{{Start: fetchQuery.Start, End: fetchQuery.End}}
+	}
+
+	blocks := make([]storage.Block, len(multiSeriesBlocks))
+	for i, multiSeriesBlock := range multiSeriesBlocks {
+		blocks[i], err = multiSeriesBlock.ToStorageBlock(bounds, m3db.DefaultLookbackDuration)
+		if err != nil {
+			return storage.BlockResult{}, err
+		}
+	}
+
+	return storage.BlockResult{Blocks: blocks}, nil
 }
 
 func (w *writeRequest) Process(ctx context.Context) error {
 	common := w.writeRequestCommon
 	store := common.store
 	id := ident.StringID(common.id)
-	return store.session.Write(store.namespace, id, w.timestamp, w.value, common.unit, common.annotation)
+	// NB: each request gets its own iterator since requests are processed in parallel
+	tagIterator := storage.TagsToIdentTagIterator(common.tags)
+	return store.session.WriteTagged(store.writeNamespace, id, tagIterator, w.timestamp, w.value, common.unit, common.annotation)
 }
 
 type writeRequestCommon struct {
@@ -188,6 +580,7 @@ type writeRequestCommon struct {
 	annotation []byte
 	unit       xtime.Unit
 	id         string
+	tags       models.Tags
 }
 
 type writeRequest struct {
@@ -205,7 +598,10 @@ func newWriteRequest(writeRequestCommon *writeRequestCommon, timestamp time.Time
 }
 
 func (s *localStorage) Close() error {
-	s.namespace.Finalize()
+	for _, namespace := range s.namespaces {
+		namespace.Finalize()
+	}
+
 	return nil
 }
 
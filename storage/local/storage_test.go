@@ -26,17 +26,24 @@ import (
 	"time"
 
 	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/mocks"
 	"github.com/m3db/m3coordinator/models"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/test"
 	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/tsdb"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"github.com/m3db/m3db/client"
+	"github.com/m3db/m3db/encoding"
+	m3ts "github.com/m3db/m3db/ts"
+	"github.com/m3db/m3metrics/policy"
+	"github.com/m3db/m3x/ident"
 	xtime "github.com/m3db/m3x/time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setup(ctrl *gomock.Controller) (storage.Storage, *client.MockSession) {
@@ -44,7 +51,10 @@ func setup(ctrl *gomock.Controller) (storage.Storage, *client.MockSession) {
 	logger := logging.WithContext(context.TODO())
 	defer logger.Sync()
 	session := client.NewMockSession(ctrl)
-	storage := NewStorage(session, "metrics", time.Minute)
+	storage, err := NewStorage(session, "metrics", time.Minute)
+	if err != nil {
+		panic(err)
+	}
 	return storage, session
 }
 
@@ -88,7 +98,7 @@ func newWriteQuery() *storage.WriteQuery {
 func setupLocalWrite(t *testing.T) storage.Storage {
 	ctrl := gomock.NewController(t)
 	store, session := setup(ctrl)
-	session.EXPECT().Write(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	return store
 }
 
@@ -119,6 +129,267 @@ func TestLocalRead(t *testing.T) {
 	assert.Equal(t, tags, results.SeriesList[0].Tags)
 }
 
+// setupConsolidation returns a store holding a series with datapoints at 10s, 20s and 150s after start
+func setupConsolidation(ctrl *gomock.Controller, start time.Time) storage.Storage {
+	store, session := setup(ctrl)
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: start.Add(10 * time.Second), Value: 1}, xtime.Second, nil),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: start.Add(20 * time.Second), Value: 2}, xtime.Second, nil),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: start.Add(150 * time.Second), Value: 3}, xtime.Second, nil),
+		iter.EXPECT().Next().Return(false),
+	)
+	iter.EXPECT().Err().Return(nil)
+	iter.EXPECT().ID().Return(ident.StringID("foo"))
+	iter.EXPECT().Tags().Return(test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag()))
+
+	iters := encoding.NewMockSeriesIterators(ctrl)
+	iters.EXPECT().Iters().Return([]encoding.SeriesIterator{iter})
+	iters.EXPECT().Len().Return(1)
+	iters.EXPECT().Close()
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(iters, true, nil)
+	return store
+}
+
+func TestLocalReadConsolidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Now().Truncate(time.Minute)
+	store := setupConsolidation(ctrl, start)
+
+	query := newFetchReq()
+	query.Start = start
+	query.End = start.Add(3 * time.Minute)
+	result, err := store.Fetch(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.SeriesList, 1)
+
+	series := result.SeriesList[0]
+	assert.Equal(t, start, series.StartTime())
+	assert.Equal(t, int(time.Minute/time.Millisecond), series.MillisPerStep())
+	require.Equal(t, 3, series.Len())
+	// The most recent datapoint of a step is kept, the empty step looks back to the last datapoint
+	assert.Equal(t, 2.0, series.ValueAt(0))
+	assert.Equal(t, 2.0, series.ValueAt(1))
+	assert.Equal(t, 3.0, series.ValueAt(2))
+}
+
+func TestLocalReadConsolidatesToInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Now().Truncate(time.Minute)
+	store := setupConsolidation(ctrl, start)
+
+	query := newFetchReq()
+	query.Start = start
+	query.End = start.Add(3 * time.Minute)
+	query.Interval = 90 * time.Second
+	result, err := store.Fetch(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.SeriesList, 1)
+
+	// The steps of the query are used rather than the resolution of the namespace
+	series := result.SeriesList[0]
+	assert.Equal(t, int(90*time.Second/time.Millisecond), series.MillisPerStep())
+	require.Equal(t, 2, series.Len())
+	assert.Equal(t, 2.0, series.ValueAt(0))
+	assert.Equal(t, 3.0, series.ValueAt(1))
+}
+
+// namespaceMatcher matches the namespace of a session call
+type namespaceMatcher string
+
+func (m namespaceMatcher) Matches(x interface{}) bool {
+	id, ok := x.(ident.ID)
+	return ok && id.String() == string(m)
+}
+
+func (m namespaceMatcher) String() string {
+	return "namespace " + string(m)
+}
+
+func newNamespaceIters(ctrl *gomock.Controller, datapoints []m3ts.Datapoint) encoding.SeriesIterators {
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	calls := make([]*gomock.Call, 0, 2*len(datapoints)+1)
+	for _, dp := range datapoints {
+		calls = append(calls,
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(dp, xtime.Second, nil),
+		)
+	}
+
+	calls = append(calls, iter.EXPECT().Next().Return(false))
+	gomock.InOrder(calls...)
+	iter.EXPECT().Err().Return(nil)
+	iter.EXPECT().ID().Return(ident.StringID("foo"))
+	iter.EXPECT().Tags().Return(test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag())).AnyTimes()
+
+	iters := encoding.NewMockSeriesIterators(ctrl)
+	iters.EXPECT().Iters().Return([]encoding.SeriesIterator{iter})
+	iters.EXPECT().Close()
+	return iters
+}
+
+func TestLocalReadStitchesNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	session := client.NewMockSession(ctrl)
+	policyResolver := mocks.NewMockPolicyResolver(ctrl)
+	raw := policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+	aggregated := policy.NewStoragePolicy(time.Minute, xtime.Second, 30*24*time.Hour)
+	store, err := NewMultiNamespaceStorage(session, []Namespace{
+		{Name: "raw", StoragePolicy: raw},
+		{Name: "aggregated", StoragePolicy: aggregated, Type: AggregatedNamespace},
+	}, policyResolver)
+	require.NoError(t, err)
+
+	start := time.Now().Truncate(time.Minute)
+	mid := start.Add(2 * time.Minute)
+	end := start.Add(4 * time.Minute)
+	policyResolver.EXPECT().Resolve(gomock.Any(), gomock.Any(), start, end).Return([]tsdb.FetchRequest{{
+		Ranges: tsdb.FetchRanges{
+			{Range: xtime.Range{Start: start, End: mid}, StoragePolicy: aggregated},
+			{Range: xtime.Range{Start: mid, End: end}, StoragePolicy: raw},
+		},
+	}}, nil)
+
+	session.EXPECT().FetchTagged(namespaceMatcher("aggregated"), gomock.Any(), gomock.Any()).Return(newNamespaceIters(ctrl, []m3ts.Datapoint{
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(time.Minute), Value: 2},
+	}), true, nil)
+	session.EXPECT().FetchTagged(namespaceMatcher("raw"), gomock.Any(), gomock.Any()).Return(newNamespaceIters(ctrl, []m3ts.Datapoint{
+		{Timestamp: mid.Add(10 * time.Second), Value: 3},
+		{Timestamp: mid.Add(time.Minute), Value: 4},
+	}), true, nil)
+
+	query := newFetchReq()
+	query.Start = start
+	query.End = end
+	result, err := store.Fetch(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.SeriesList, 1)
+
+	// Both namespaces make up a single series at the coarsest resolution
+	series := result.SeriesList[0]
+	assert.Equal(t, int(time.Minute/time.Millisecond), series.MillisPerStep())
+	require.Equal(t, 4, series.Len())
+	for i, expected := range []float64{1, 2, 3, 4} {
+		assert.Equal(t, expected, series.ValueAt(i))
+	}
+}
+
+func TestLocalWriteToUnaggregatedNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	session := client.NewMockSession(ctrl)
+	store, err := NewMultiNamespaceStorage(session, []Namespace{
+		{Name: "aggregated", StoragePolicy: policy.NewStoragePolicy(time.Minute, xtime.Second, 30*24*time.Hour), Type: AggregatedNamespace},
+		{Name: "raw", StoragePolicy: policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)},
+	}, mocks.NewMockPolicyResolver(ctrl))
+	require.NoError(t, err)
+
+	session.EXPECT().WriteTagged(namespaceMatcher("raw"), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	require.NoError(t, store.Write(context.TODO(), newWriteQuery()))
+}
+
+func TestLocalInvalidNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	session := client.NewMockSession(ctrl)
+	policyResolver := mocks.NewMockPolicyResolver(ctrl)
+	raw := policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+	aggregated := policy.NewStoragePolicy(time.Minute, xtime.Second, 30*24*time.Hour)
+
+	_, err := NewMultiNamespaceStorage(session, nil, policyResolver)
+	assert.Equal(t, errors.ErrNoNamespaces, err)
+
+	_, err = NewMultiNamespaceStorage(session, []Namespace{
+		{Name: "aggregated", StoragePolicy: aggregated, Type: AggregatedNamespace},
+	}, policyResolver)
+	assert.Equal(t, errors.ErrNoUnaggregatedNamespace, err)
+
+	_, err = NewMultiNamespaceStorage(session, []Namespace{
+		{Name: "raw", StoragePolicy: raw},
+		{Name: "other", StoragePolicy: aggregated},
+	}, policyResolver)
+	assert.Error(t, err)
+
+	_, err = NewMultiNamespaceStorage(session, []Namespace{
+		{Name: "raw", StoragePolicy: raw},
+		{Name: "aggregated", StoragePolicy: raw, Type: AggregatedNamespace},
+	}, policyResolver)
+	assert.Error(t, err)
+}
+
+func TestLocalFetchBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store, session := setup(ctrl)
+	start := time.Now().Truncate(time.Minute)
+
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: start.Add(-30 * time.Second), Value: 1}, xtime.Second, nil),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: start.Add(90 * time.Second), Value: 2}, xtime.Second, nil),
+		iter.EXPECT().Next().Return(false),
+	)
+	iter.EXPECT().Err().Return(nil)
+	iter.EXPECT().ID().Return(ident.StringID("foo"))
+	iter.EXPECT().Tags().Return(test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag()))
+
+	iters := encoding.NewMockSeriesIterators(ctrl)
+	iters.EXPECT().Iters().Return([]encoding.SeriesIterator{iter})
+	iters.EXPECT().Len().Return(1)
+	iters.EXPECT().Close()
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(iters, true, nil)
+
+	query := newFetchReq()
+	query.Start = start
+	query.End = start.Add(2 * time.Minute)
+	query.Interval = time.Minute
+	result, err := store.FetchBlocks(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Blocks, 1)
+
+	block := result.Blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(storage.Bounds{Start: query.Start, End: query.End, StepSize: time.Minute}))
+	seriesIter := block.SeriesIter()
+	require.True(t, seriesIter.Next())
+	series := seriesIter.Current()
+	assert.Equal(t, "foo", series.Name())
+	assert.Equal(t, models.Tags{"foo": "bar"}, series.Tags)
+	require.Equal(t, 3, series.Len())
+	// The first datapoint stays visible until the second one arrives
+	assert.Equal(t, 1.0, series.ValueAt(0))
+	assert.Equal(t, 1.0, series.ValueAt(1))
+	assert.Equal(t, 2.0, series.ValueAt(2))
+	assert.False(t, seriesIter.Next())
+}
+
+func TestLocalFetchBlocksEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store, session := setup(ctrl)
+	iters := encoding.NewMockSeriesIterators(ctrl)
+	iters.EXPECT().Iters().Return(nil)
+	iters.EXPECT().Len().Return(0)
+	iters.EXPECT().Close()
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).Return(iters, true, nil)
+
+	query := newFetchReq()
+	query.Interval = time.Minute
+	result, err := store.FetchBlocks(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Blocks, 1)
+	assert.Empty(t, result.Blocks[0].SeriesMeta())
+	assert.Equal(t, 11, result.Blocks[0].Meta().Bounds.Steps())
+}
+
+func TestLocalFetchBlocksInvalidStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store, _ := setup(ctrl)
+	_, err := store.FetchBlocks(context.TODO(), newFetchReq(), &storage.FetchOptions{})
+	assert.Equal(t, errors.ErrInvalidStepSize, err)
+}
+
 func setupLocalSearch(t *testing.T) storage.Storage {
 	ctrl := gomock.NewController(t)
 	store, session := setup(ctrl)
@@ -132,3 +403,23 @@ func TestLocalSearchExpectedFail(t *testing.T) {
 	_, err := store.FetchTags(context.TODO(), searchReq, &storage.FetchOptions{Limit: 100})
 	assert.Error(t, err)
 }
+
+func TestLocalSearchTruncated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store, session := setup(ctrl)
+	iter := client.NewMockTaggedIDsIterator(ctrl)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(ident.StringID("metrics"), ident.StringID("a"), test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag())),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(ident.StringID("metrics"), ident.StringID("b"), test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag())),
+		iter.EXPECT().Next().Return(false),
+	)
+	session.EXPECT().FetchTaggedIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(iter, true, nil)
+
+	res, err := store.FetchTags(context.TODO(), newFetchReq(), &storage.FetchOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Metrics, 1)
+	assert.Equal(t, "a", res.Metrics[0].ID)
+	assert.True(t, res.Truncated)
+}
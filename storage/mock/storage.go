@@ -28,6 +28,7 @@ import (
 
 type mockStorage struct {
 	sType storage.Type
+	name  string
 }
 
 // NewMockStorage creates a new mock Storage instance.
@@ -40,6 +41,15 @@ func NewMockStorageWithType(sType storage.Type) storage.Storage {
 	return &mockStorage{sType: sType}
 }
 
+// NewMockStorageWithName creates a new mock Storage instance with a name.
+func NewMockStorageWithName(sType storage.Type, name string) storage.Storage {
+	return &mockStorage{sType: sType, name: name}
+}
+
+func (s *mockStorage) Name() string {
+	return s.name
+}
+
 func (s *mockStorage) Fetch(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.FetchResult, error) {
 	return nil, nil
 }
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3coordinator/ts"
+)
+
+// RawBlock is a block of a range selector, it keeps the raw datapoints of each series so that functions
+// over ranges are evaluated on them rather than on step values
+type RawBlock interface {
+	Block
+	// Range is how far back from each step the range selector reaches
+	Range() time.Duration
+	// Datapoints returns the datapoints of each series in time order, in the order of the series metadata
+	Datapoints() []ts.Datapoints
+}
+
+type rawBlock struct {
+	Block
+	rangeDuration time.Duration
+	datapoints    []ts.Datapoints
+}
+
+// NewRawBlock creates a block from the raw datapoints of each series. Datapoints must be in time order and
+// reach back a range before the start of the bounds, the value of each step is the last datapoint in its range.
+func NewRawBlock(meta BlockMetadata, seriesMeta []SeriesMeta, datapoints []ts.Datapoints, rangeDuration time.Duration) (Block, error) {
+	if len(seriesMeta) != len(datapoints) {
+		return nil, fmt.Errorf("series metadata and datapoints mismatch, %d - %d", len(seriesMeta), len(datapoints))
+	}
+
+	steps := meta.Bounds.Steps()
+	values := make([][]float64, len(datapoints))
+	for i, dps := range datapoints {
+		values[i] = make([]float64, steps)
+		RangeWindows(dps, meta.Bounds, rangeDuration, func(idx int, window ts.Datapoints) {
+			values[i][idx] = math.NaN()
+			if len(window) > 0 {
+				values[i][idx] = window[len(window)-1].Value
+			}
+		})
+	}
+
+	block, err := NewSeriesBlock(meta, seriesMeta, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rawBlock{Block: block, rangeDuration: rangeDuration, datapoints: datapoints}, nil
+}
+
+func (b *rawBlock) Range() time.Duration {
+	return b.rangeDuration
+}
+
+func (b *rawBlock) Datapoints() []ts.Datapoints {
+	return b.datapoints
+}
+
+// RangeWindows calls fn with the datapoints in [t - rangeDuration, t] for each step t of the bounds, the
+// datapoints must be in time order
+func RangeWindows(datapoints ts.Datapoints, bounds Bounds, rangeDuration time.Duration, fn func(idx int, window ts.Datapoints)) {
+	start, end := 0, 0
+	for idx := 0; idx < bounds.Steps(); idx++ {
+		t := bounds.TimeForIndex(idx)
+		rangeStart := t.Add(-rangeDuration)
+		for end < len(datapoints) && !datapoints[end].Timestamp.After(t) {
+			end++
+		}
+
+		for start < end && datapoints[start].Timestamp.Before(rangeStart) {
+			start++
+		}
+
+		fn(idx, datapoints[start:end])
+	}
+}
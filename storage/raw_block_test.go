@@ -0,0 +1,88 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRawBlock(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	dps := ts.Datapoints{
+		{Timestamp: now.Add(-90 * time.Second), Value: 1},
+		{Timestamp: now.Add(-30 * time.Second), Value: 2},
+		{Timestamp: now.Add(2 * time.Minute), Value: 3},
+	}
+
+	bounds := Bounds{Start: now, End: now.Add(3 * time.Minute), StepSize: time.Minute}
+	seriesMeta := []SeriesMeta{{Name: "foo", Tags: models.Tags{"a": "b"}}}
+	block, err := NewRawBlock(BlockMetadata{Bounds: bounds}, seriesMeta, []ts.Datapoints{dps}, time.Minute)
+	require.NoError(t, err)
+
+	raw, ok := block.(RawBlock)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, raw.Range())
+	assert.Equal(t, []ts.Datapoints{dps}, raw.Datapoints())
+	assert.Equal(t, seriesMeta, block.SeriesMeta())
+
+	// Each step takes the last datapoint of its range
+	series := block.SeriesIter()
+	require.True(t, series.Next())
+	current := series.Current()
+	require.Equal(t, 4, current.Len())
+	assert.Equal(t, 2.0, current.ValueAt(0))
+	assert.True(t, math.IsNaN(current.ValueAt(1)))
+	assert.Equal(t, 3.0, current.ValueAt(2))
+	assert.Equal(t, 3.0, current.ValueAt(3))
+
+	_, err = NewRawBlock(BlockMetadata{Bounds: bounds}, seriesMeta, nil, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestRangeWindows(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	var dps ts.Datapoints
+	for i := 0; i < 6; i++ {
+		dps = append(dps, &ts.Datapoint{Timestamp: now.Add(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+
+	bounds := Bounds{Start: now.Add(2 * time.Minute), End: now.Add(5 * time.Minute), StepSize: 3 * time.Minute}
+	var windows [][]float64
+	RangeWindows(dps, bounds, 2*time.Minute, func(idx int, window ts.Datapoints) {
+		values := make([]float64, len(window))
+		for i, dp := range window {
+			values[i] = dp.Value
+		}
+
+		windows = append(windows, values)
+	})
+
+	// Both ends of the range are included
+	assert.Equal(t, [][]float64{{0, 1, 2}, {3, 4, 5}}, windows)
+}
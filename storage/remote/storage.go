@@ -22,34 +22,64 @@ package remote
 
 import (
 	"context"
+	"time"
 
-	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/tsdb/remote"
 )
 
+// Options are the options of a remote storage
+type Options struct {
+	// Name identifies the remote for filters
+	Name string
+	// FetchTimeout bounds every read from the remote, zero leaves reads bounded by the query alone
+	FetchTimeout time.Duration
+	// WriteTimeout bounds every write to the remote, zero leaves writes bounded by the request alone
+	WriteTimeout time.Duration
+}
+
 type remoteStorage struct {
 	client remote.Client
+	opts   Options
 }
 
 // NewStorage creates a new remote Storage instance.
-func NewStorage(c remote.Client) storage.Storage {
-	return &remoteStorage{client: c}
+func NewStorage(c remote.Client, opts Options) storage.Storage {
+	return &remoteStorage{client: c, opts: opts}
+}
+
+// withTimeout bounds the context by the timeout when it is set
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
 
 func (s *remoteStorage) Fetch(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.FetchResult, error) {
+	ctx, cancel := withTimeout(ctx, s.opts.FetchTimeout)
+	defer cancel()
 	return s.client.Fetch(ctx, query, options)
 }
 
 func (s *remoteStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
-	// todo (braskin): implement remote FetchTags
-	return nil, errors.ErrNotImplemented
+	ctx, cancel := withTimeout(ctx, s.opts.FetchTimeout)
+	defer cancel()
+	return s.client.FetchTags(ctx, query, options)
 }
 
 func (s *remoteStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
+	ctx, cancel := withTimeout(ctx, s.opts.WriteTimeout)
+	defer cancel()
 	return s.client.Write(ctx, query)
 }
 
+func (s *remoteStorage) Name() string {
+	return s.opts.Name
+}
+
 func (s *remoteStorage) Type() storage.Type {
 	return storage.TypeRemoteDC
 }
@@ -60,5 +90,15 @@ func (s *remoteStorage) Close() error {
 
 func (s *remoteStorage) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
-	return storage.BlockResult{}, errors.ErrNotImplemented
+	ctx, cancel := withTimeout(ctx, s.opts.FetchTimeout)
+	defer cancel()
+	return s.client.FetchBlocks(ctx, query, options)
+}
+
+// ExecuteSubPlan executes the sub plan on the remote so that only its result is sent back
+func (s *remoteStorage) ExecuteSubPlan(
+	ctx context.Context, subPlan plan.SubPlan, options *storage.FetchOptions) (storage.BlockResult, error) {
+	ctx, cancel := withTimeout(ctx, s.opts.FetchTimeout)
+	defer cancel()
+	return s.client.ExecuteSubPlan(ctx, subPlan, options)
 }
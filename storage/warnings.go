@@ -0,0 +1,97 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Warning is the error of a store which was left out of a partial result
+type Warning struct {
+	// Store identifies the store, by name when it has one
+	Store   string
+	Message string
+}
+
+// String returns the warning on a single line, warnings are sent to clients in headers
+func (w Warning) String() string {
+	return singleLine(fmt.Sprintf("%s: %s", w.Store, w.Message))
+}
+
+// NewWarning creates a warning for the error of a store. Warnings are sent to clients so the message only
+// says how the store failed, the error itself may carry internal details such as addresses
+func NewWarning(store Storage, err error) Warning {
+	name := store.Type().String()
+	if named, ok := store.(Named); ok && named.Name() != "" {
+		name = named.Name()
+	}
+
+	return Warning{Store: name, Message: warningMessage(err)}
+}
+
+func warningMessage(err error) string {
+	switch err {
+	case context.DeadlineExceeded:
+		return "timed out"
+	case context.Canceled:
+		return "canceled"
+	default:
+		return "unavailable"
+	}
+}
+
+// singleLine replaces the control characters of a string, such as newlines, with spaces
+func singleLine(str string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+
+		return r
+	}, str)
+}
+
+// WarningStrings returns the distinct warnings in the order they were first reported
+func WarningStrings(warnings []Warning) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	var (
+		strs = make([]string, 0, len(warnings))
+		seen = make(map[string]struct{}, len(warnings))
+	)
+
+	for _, warning := range warnings {
+		str := warning.String()
+		if _, ok := seen[str]; ok {
+			continue
+		}
+
+		seen[str] = struct{}{}
+		strs = append(strs, str)
+	}
+
+	return strs
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// warningStore is a store with a type and an optional name
+type warningStore struct {
+	Storage
+	storeType Type
+	name      string
+}
+
+func (s *warningStore) Type() Type {
+	return s.storeType
+}
+
+func (s *warningStore) Name() string {
+	return s.name
+}
+
+func TestNewWarning(t *testing.T) {
+	remote := &warningStore{storeType: TypeRemoteDC}
+	assert.Equal(t, Warning{Store: "remote", Message: "timed out"}, NewWarning(remote, context.DeadlineExceeded))
+	assert.Equal(t, Warning{Store: "remote", Message: "canceled"}, NewWarning(remote, context.Canceled))
+
+	// Errors may carry internal details, they are not sent to clients
+	named := &warningStore{storeType: TypeRemoteDC, name: "eu"}
+	assert.Equal(t, Warning{Store: "eu", Message: "unavailable"}, NewWarning(named, fmt.Errorf("dial tcp 10.0.0.1:9000")))
+}
+
+func TestWarningStrings(t *testing.T) {
+	assert.Nil(t, WarningStrings(nil))
+
+	warnings := []Warning{
+		{Store: "eu", Message: "unavailable"},
+		{Store: "us\r\nX-Injected: true", Message: "timed out"},
+		{Store: "eu", Message: "unavailable"},
+	}
+	assert.Equal(t, []string{"eu: unavailable", "us  X-Injected: true: timed out"}, WarningStrings(warnings))
+}
@@ -14,6 +14,11 @@ import (
 // NewStorageAndSession generates a new local storage and mock session
 func NewStorageAndSession(ctrl *gomock.Controller) (storage.Storage, *client.MockSession) {
 	session := client.NewMockSession(ctrl)
-	storage := local.NewStorage(session, "metrics", time.Minute)
+	storage, err := local.NewStorage(session, "metrics", time.Minute)
+	if err != nil {
+		// A single unaggregated namespace is always valid
+		panic(err)
+	}
+
 	return storage, session
 }
@@ -0,0 +1,224 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package local
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/ts"
+	"github.com/m3db/m3coordinator/util/logging"
+
+	"github.com/m3db/m3db/encoding"
+	"github.com/m3db/m3db/storage/index"
+	m3ts "github.com/m3db/m3db/ts"
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/idx"
+	m3ninxindex "github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/search/executor"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writtenSeries is a series as seen by M3DB through WriteTagged
+type writtenSeries struct {
+	id         string
+	tags       models.Tags
+	datapoints []m3ts.Datapoint
+}
+
+// recorder captures tagged writes so that they can be served back on reads
+type recorder struct {
+	sync.Mutex
+	series map[string]*writtenSeries
+	order  []string
+}
+
+func newRecorder() *recorder {
+	return &recorder{series: make(map[string]*writtenSeries)}
+}
+
+func (r *recorder) writeTagged(_, id ident.ID, tagIter ident.TagIterator, t time.Time, value float64, _ xtime.Unit, _ []byte) {
+	tags, err := storage.FromIdentTagIteratorToTags(tagIter)
+	if err != nil {
+		panic(err)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	s, ok := r.series[id.String()]
+	if !ok {
+		s = &writtenSeries{id: id.String(), tags: tags}
+		r.series[s.id] = s
+		r.order = append(r.order, s.id)
+	}
+
+	s.datapoints = append(s.datapoints, m3ts.Datapoint{Timestamp: t, Value: value})
+}
+
+// query returns the written series matching an index query, the query is executed against an in memory
+// index segment holding the written series as M3DB would
+func (r *recorder) query(t *testing.T, q index.Query) []*writtenSeries {
+	r.Lock()
+	defer r.Unlock()
+	segment, err := mem.NewSegment(0, mem.NewOptions())
+	require.NoError(t, err)
+	for _, id := range r.order {
+		s := r.series[id]
+		d := doc.Document{ID: []byte(s.id)}
+		for name, value := range s.tags {
+			d.Fields = append(d.Fields, doc.Field{Name: []byte(name), Value: []byte(value)})
+		}
+
+		_, err := segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	reader, err := segment.Reader()
+	require.NoError(t, err)
+	exec := executor.NewExecutor([]m3ninxindex.Reader{reader})
+	defer exec.Close()
+
+	iter, err := exec.Execute(q.Query.SearchQuery())
+	require.NoError(t, err)
+	var matched []*writtenSeries
+	for iter.Next() {
+		matched = append(matched, r.series[string(iter.Current().ID)])
+	}
+
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	return matched
+}
+
+func newSeriesIterator(ctrl *gomock.Controller, s *writtenSeries) encoding.SeriesIterator {
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	calls := make([]*gomock.Call, 0, 2*len(s.datapoints)+1)
+	for _, dp := range s.datapoints {
+		calls = append(calls,
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(dp, xtime.Millisecond, nil),
+		)
+	}
+
+	calls = append(calls, iter.EXPECT().Next().Return(false))
+	gomock.InOrder(calls...)
+	iter.EXPECT().Err().Return(nil)
+	iter.EXPECT().ID().Return(ident.StringID(s.id))
+	iter.EXPECT().Tags().Return(storage.TagsToIdentTagIterator(s.tags))
+	iter.EXPECT().Close()
+	return iter
+}
+
+func mustNewMatcher(t *testing.T, mType models.MatchType, name, value string) *models.Matcher {
+	m, err := models.NewMatcher(mType, name, value)
+	require.NoError(t, err)
+	return m
+}
+
+func TestWriteThenReadWithMatchers(t *testing.T) {
+	logging.InitWithCores(nil)
+	ctrl := gomock.NewController(t)
+	store, session := NewStorageAndSession(ctrl)
+
+	rec := newRecorder()
+	session.EXPECT().
+		WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(rec.writeTagged).
+		Return(nil).
+		AnyTimes()
+
+	now := time.Now().Truncate(time.Minute)
+	writes := []*storage.WriteQuery{
+		{
+			Tags: models.Tags{"__name__": "http_requests_total", "job": "api", "method": "GET"},
+			Datapoints: ts.Datapoints{
+				{Timestamp: now.Add(-2 * time.Minute), Value: 1},
+				{Timestamp: now.Add(-time.Minute), Value: 2},
+			},
+			Unit: xtime.Millisecond,
+		},
+		{
+			Tags: models.Tags{"__name__": "http_requests_total", "job": "web", "method": "POST"},
+			Datapoints: ts.Datapoints{
+				{Timestamp: now.Add(-time.Minute), Value: 3},
+			},
+			Unit: xtime.Millisecond,
+		},
+	}
+
+	for _, w := range writes {
+		require.NoError(t, store.Write(context.TODO(), w))
+	}
+
+	query := &storage.FetchQuery{
+		TagMatchers: models.Matchers{
+			mustNewMatcher(t, models.MatchEqual, "__name__", "http_requests_total"),
+			mustNewMatcher(t, models.MatchRegexp, "job", "ap.*"),
+		},
+		Start: now.Add(-10 * time.Minute),
+		End:   now,
+	}
+
+	// The matchers must reach M3DB as an index query, built anew for each use since executing a
+	// query caches state in its compiled regexps
+	indexQuery := func() index.Query {
+		re, err := idx.NewRegexpQuery([]byte("job"), []byte("ap.*"))
+		require.NoError(t, err)
+		q, err := idx.NewConjunctionQuery(idx.NewTermQuery([]byte("__name__"), []byte("http_requests_total")), re)
+		require.NoError(t, err)
+		return index.Query{Query: q}
+	}
+
+	matched := rec.query(t, indexQuery())
+	require.Len(t, matched, 1, "only the api series should match")
+	assert.Equal(t, writes[0].Tags.ID(), matched[0].id, "written id must be derived from the tags")
+	assert.Equal(t, writes[0].Tags, matched[0].tags, "tags must be persisted with the write")
+
+	iters := make([]encoding.SeriesIterator, len(matched))
+	for i, s := range matched {
+		iters[i] = newSeriesIterator(ctrl, s)
+	}
+
+	session.EXPECT().
+		FetchTagged(gomock.Any(), indexQuery(), gomock.Any()).
+		Do(func(_ ident.ID, _ index.Query, opts index.QueryOptions) {
+			assert.Equal(t, query.Start, opts.StartInclusive)
+			assert.Equal(t, query.End, opts.EndExclusive)
+		}).
+		Return(encoding.NewSeriesIterators(iters, nil), true, nil)
+
+	result, err := store.Fetch(context.TODO(), query, &storage.FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.SeriesList, 1)
+	series := result.SeriesList[0]
+	assert.Equal(t, writes[0].Tags, series.Tags)
+	assert.Equal(t, writes[0].Tags.ID(), series.Name())
+}
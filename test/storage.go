@@ -82,6 +82,7 @@ func NewMockSeriesIters(ctrl *gomock.Controller, tags ident.Tag) encoding.Series
 	mockIter.EXPECT().Next().Return(false)
 	mockIter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: time.Now(), Value: 10}, xtime.Millisecond, nil)
 	mockIter.EXPECT().Current().Return(m3ts.Datapoint{Timestamp: time.Now(), Value: 10}, xtime.Millisecond, nil)
+	mockIter.EXPECT().Err().Return(nil)
 	mockIter.EXPECT().ID().Return(ident.StringID("foo"))
 	mockIter.EXPECT().Tags().Return(GenerateSingleSampleTagIterator(ctrl, tags))
 
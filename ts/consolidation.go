@@ -0,0 +1,84 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ts
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// ConsolidationFunc consolidates the values of the datapoints which fall into the same step, values
+// are never empty
+type ConsolidationFunc func(values []float64) float64
+
+// TakeLast takes the most recent value of a step
+func TakeLast(values []float64) float64 {
+	return values[len(values)-1]
+}
+
+// Consolidate places datapoints, sorted by time, into the step which starts at or before their timestamp
+// and consolidates the values of each step with fn. Steps without datapoints take the value of the most
+// recent earlier datapoint which is no older than the lookback, a zero lookback leaves them empty
+func Consolidate(
+	ctx context.Context,
+	datapoints []Datapoint,
+	start time.Time,
+	millisPerStep, numSteps int,
+	fn ConsolidationFunc,
+	lookback time.Duration,
+) MutableValues {
+	values := NewValues(ctx, millisPerStep, numSteps)
+	stepSize := time.Duration(millisPerStep) * time.Millisecond
+	var (
+		bucket []float64
+		last   *Datapoint
+		idx    int
+	)
+
+	for i := 0; i < numSteps; i++ {
+		stepStart := start.Add(time.Duration(i) * stepSize)
+		stepEnd := stepStart.Add(stepSize)
+		bucket = bucket[:0]
+		for ; idx < len(datapoints) && datapoints[idx].Timestamp.Before(stepEnd); idx++ {
+			dp := &datapoints[idx]
+			if math.IsNaN(dp.Value) {
+				continue
+			}
+
+			last = dp
+			if !dp.Timestamp.Before(stepStart) {
+				bucket = append(bucket, dp.Value)
+			}
+		}
+
+		if len(bucket) > 0 {
+			values.SetValueAt(i, fn(bucket))
+			continue
+		}
+
+		if last != nil && lookback > 0 && !last.Timestamp.Before(stepStart.Add(-lookback)) {
+			values.SetValueAt(i, last.Value)
+		}
+	}
+
+	return values
+}
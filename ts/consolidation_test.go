@@ -0,0 +1,77 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ts
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consolidatedValues(t *testing.T, fn ConsolidationFunc, lookback time.Duration) []float64 {
+	start := time.Now().Truncate(time.Hour)
+	datapoints := []Datapoint{
+		// Before the first step so it is never consolidated
+		{Timestamp: start.Add(-30 * time.Second), Value: 1},
+		{Timestamp: start.Add(10 * time.Second), Value: 2},
+		{Timestamp: start.Add(50 * time.Second), Value: 4},
+		{Timestamp: start.Add(61 * time.Second), Value: math.NaN()},
+		{Timestamp: start.Add(3*time.Minute + 30*time.Second), Value: 6},
+	}
+
+	values := Consolidate(context.TODO(), datapoints, start, 60000, 6, fn, lookback)
+	require.Equal(t, 6, values.Len())
+	assert.Equal(t, 60000, values.MillisPerStep())
+	result := make([]float64, values.Len())
+	for i := range result {
+		result[i] = values.ValueAt(i)
+	}
+
+	return result
+}
+
+func assertValues(t *testing.T, expected, actual []float64) {
+	require.Len(t, actual, len(expected))
+	for i, v := range expected {
+		if math.IsNaN(v) {
+			assert.True(t, math.IsNaN(actual[i]), "expected NaN at step %d, got %v", i, actual[i])
+			continue
+		}
+
+		assert.Equal(t, v, actual[i], "step %d", i)
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	nan := math.NaN()
+	assertValues(t, []float64{4, nan, nan, 6, nan, nan}, consolidatedValues(t, TakeLast, 0))
+}
+
+func TestConsolidateLookback(t *testing.T) {
+	nan := math.NaN()
+	// Empty steps take the most recent datapoint within the lookback of the step start
+	assertValues(t, []float64{4, 4, 4, 6, 6, 6}, consolidatedValues(t, TakeLast, 2*time.Minute))
+	assertValues(t, []float64{4, 4, nan, 6, 6, nan}, consolidatedValues(t, TakeLast, time.Minute))
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3db
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+
+	"github.com/m3db/m3db/encoding"
+	"github.com/m3db/m3x/ident"
+)
+
+// DefaultLookbackDuration is how far back a step looks for a value, it matches the Prometheus staleness period
+const DefaultLookbackDuration = 5 * time.Minute
+
+var errNoSeriesIterators = errors.New("no series iterators for consolidated block")
+
+// ToStorageBlock decodes every series in the block into the steps of the bounds, each step takes the
+// most recent value across namespaces which is no older than the lookback
+func (m MultiSeriesBlock) ToStorageBlock(bounds storage.Bounds, lookback time.Duration) (storage.Block, error) {
+	seriesMeta := make([]storage.SeriesMeta, len(m.Blocks))
+	values := make([][]float64, len(m.Blocks))
+	for i, block := range m.Blocks {
+		meta, vals, err := block.consolidate(bounds, lookback)
+		if err != nil {
+			return nil, err
+		}
+
+		seriesMeta[i] = meta
+		values[i] = vals
+	}
+
+	return storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds}, seriesMeta, values)
+}
+
+func (c ConsolidatedSeriesBlock) consolidate(bounds storage.Bounds, lookback time.Duration) (storage.SeriesMeta, []float64, error) {
+	var (
+		meta      storage.SeriesMeta
+		foundMeta bool
+		steps     = bounds.Steps()
+		values    = make([]float64, steps)
+		lastSeen  = make([]time.Time, steps)
+	)
+
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	for _, nsBlock := range c.ConsolidatedNSBlocks {
+		if nsBlock.SeriesIterators == nil {
+			continue
+		}
+
+		for _, iter := range nsBlock.SeriesIterators.Iters() {
+			if !foundMeta {
+				m, err := seriesMeta(nsBlock.ID, iter)
+				if err != nil {
+					return meta, nil, err
+				}
+
+				meta = m
+				foundMeta = true
+			}
+
+			if err := consolidateIter(iter, bounds, lookback, values, lastSeen); err != nil {
+				return meta, nil, err
+			}
+		}
+	}
+
+	if !foundMeta {
+		return meta, nil, errNoSeriesIterators
+	}
+
+	return meta, values, nil
+}
+
+func seriesMeta(id ident.ID, iter encoding.SeriesIterator) (storage.SeriesMeta, error) {
+	meta := storage.SeriesMeta{Name: id.String(), Tags: models.Tags{}}
+	tagIter := iter.Tags()
+	if tagIter == nil {
+		return meta, nil
+	}
+
+	tags, err := storage.FromIdentTagIteratorToTags(tagIter)
+	if err != nil {
+		return meta, err
+	}
+
+	meta.Tags = tags
+	return meta, nil
+}
+
+// consolidateIter sets each step which is within the lookback of a datapoint, later datapoints take precedence
+func consolidateIter(iter encoding.SeriesIterator, bounds storage.Bounds, lookback time.Duration, values []float64, lastSeen []time.Time) error {
+	steps := len(values)
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		// First step at or after the datapoint
+		idx := 0
+		if dp.Timestamp.After(bounds.Start) {
+			idx = int((dp.Timestamp.Sub(bounds.Start) + bounds.StepSize - 1) / bounds.StepSize)
+		}
+
+		for ; idx < steps; idx++ {
+			stepTime := bounds.TimeForIndex(idx)
+			if !stepTime.Before(dp.Timestamp.Add(lookback)) {
+				break
+			}
+
+			if dp.Timestamp.Before(lastSeen[idx]) {
+				continue
+			}
+
+			values[idx] = dp.Value
+			lastSeen[idx] = dp.Timestamp
+		}
+	}
+
+	return iter.Err()
+}
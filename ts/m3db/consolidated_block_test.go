@@ -0,0 +1,147 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3db
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/storage"
+	"github.com/m3db/m3coordinator/test"
+
+	"github.com/m3db/m3db/encoding"
+	m3ts "github.com/m3db/m3db/ts"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDatapointsIter(ctrl *gomock.Controller, withTags bool, datapoints ...m3ts.Datapoint) encoding.SeriesIterators {
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	calls := make([]*gomock.Call, 0, 2*len(datapoints)+1)
+	for _, dp := range datapoints {
+		calls = append(calls,
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(dp, xtime.Second, nil),
+		)
+	}
+
+	calls = append(calls, iter.EXPECT().Next().Return(false))
+	gomock.InOrder(calls...)
+	iter.EXPECT().Err().Return(nil)
+	if withTags {
+		iter.EXPECT().Tags().Return(test.GenerateSingleSampleTagIterator(ctrl, test.GenerateTag()))
+	}
+
+	return encoding.NewSeriesIterators([]encoding.SeriesIterator{iter}, nil)
+}
+
+func TestToStorageBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Minute)
+	bounds := storage.Bounds{Start: now, End: now.Add(3 * time.Minute), StepSize: time.Minute}
+
+	block := MultiSeriesBlock{
+		Start: now,
+		End:   now.Add(3 * time.Minute),
+		Blocks: ConsolidatedSeriesBlocks{
+			{
+				ConsolidatedNSBlocks: []ConsolidatedNSBlock{
+					{
+						ID:        ident.StringID("foo"),
+						Namespace: "raw",
+						SeriesIterators: newDatapointsIter(ctrl, true,
+							m3ts.Datapoint{Timestamp: now, Value: 1},
+							m3ts.Datapoint{Timestamp: now.Add(2 * time.Minute), Value: 3},
+						),
+					},
+					{
+						ID:        ident.StringID("foo"),
+						Namespace: "aggregated",
+						SeriesIterators: newDatapointsIter(ctrl, false,
+							m3ts.Datapoint{Timestamp: now.Add(30 * time.Second), Value: 2},
+						),
+					},
+				},
+			},
+		},
+	}
+
+	converted, err := block.ToStorageBlock(bounds, 90*time.Second)
+	require.NoError(t, err)
+	assert.True(t, bounds.Equals(converted.Meta().Bounds))
+	assert.Equal(t, []storage.SeriesMeta{{Name: "foo", Tags: models.Tags{"foo": "bar"}}}, converted.SeriesMeta())
+
+	expected := []float64{1, 2, 3, 3}
+	iter := converted.StepIter()
+	for i, v := range expected {
+		require.True(t, iter.Next())
+		step := iter.Current()
+		assert.Equal(t, bounds.TimeForIndex(i), step.Time())
+		assert.Equal(t, []float64{v}, step.Values())
+	}
+
+	assert.False(t, iter.Next())
+}
+
+func TestToStorageBlockLookback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Minute)
+	bounds := storage.Bounds{Start: now, End: now.Add(2 * time.Minute), StepSize: time.Minute}
+	block := MultiSeriesBlock{
+		Blocks: ConsolidatedSeriesBlocks{
+			{
+				ConsolidatedNSBlocks: []ConsolidatedNSBlock{
+					{
+						ID:              ident.StringID("foo"),
+						SeriesIterators: newDatapointsIter(ctrl, true, m3ts.Datapoint{Timestamp: now.Add(-10 * time.Second), Value: 1}),
+					},
+				},
+			},
+		},
+	}
+
+	converted, err := block.ToStorageBlock(bounds, time.Minute)
+	require.NoError(t, err)
+	iter := converted.SeriesIter()
+	require.True(t, iter.Next())
+	series := iter.Current()
+	require.Equal(t, 3, series.Len())
+	assert.Equal(t, 1.0, series.ValueAt(0))
+	assert.True(t, math.IsNaN(series.ValueAt(1)))
+	assert.True(t, math.IsNaN(series.ValueAt(2)))
+}
+
+func TestToStorageBlockWithoutIterators(t *testing.T) {
+	now := time.Now()
+	bounds := storage.Bounds{Start: now, End: now, StepSize: time.Minute}
+	block := MultiSeriesBlock{
+		Blocks: ConsolidatedSeriesBlocks{{}},
+	}
+
+	_, err := block.ToStorageBlock(bounds, time.Minute)
+	assert.Error(t, err)
+}
@@ -22,21 +22,25 @@ package remote
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 
 	"github.com/m3db/m3coordinator/errors"
 	"github.com/m3db/m3coordinator/generated/proto/rpc"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
 	"github.com/m3db/m3coordinator/util/logging"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // Client is an interface
 type Client interface {
 	storage.Querier
 	storage.Appender
+	plan.SubPlanExecutor
 	Close() error
 }
 
@@ -47,12 +51,21 @@ type grpcClient struct {
 
 // NewGrpcClient creates grpc client
 func NewGrpcClient(addresses []string, additionalDialOpts ...grpc.DialOption) (Client, error) {
+	return newGrpcClient(addresses, grpc.WithInsecure(), additionalDialOpts)
+}
+
+// NewTLSGrpcClient creates grpc client which connects over TLS
+func NewTLSGrpcClient(addresses []string, tlsConfig *tls.Config, additionalDialOpts ...grpc.DialOption) (Client, error) {
+	return newGrpcClient(addresses, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), additionalDialOpts)
+}
+
+func newGrpcClient(addresses []string, transportOpt grpc.DialOption, additionalDialOpts []grpc.DialOption) (Client, error) {
 	if len(addresses) == 0 {
 		return nil, errors.ErrNoClientAddresses
 	}
 	resolver := newStaticResolver(addresses)
 	balancer := grpc.RoundRobin(resolver)
-	dialOptions := []grpc.DialOption{grpc.WithBalancer(balancer), grpc.WithInsecure()}
+	dialOptions := []grpc.DialOption{grpc.WithBalancer(balancer), transportOpt}
 	dialOptions = append(dialOptions, additionalDialOpts...)
 
 	cc, err := grpc.Dial("", dialOptions...)
@@ -100,8 +113,39 @@ func (c *grpcClient) Fetch(ctx context.Context, query *storage.FetchQuery, optio
 	return &storage.FetchResult{LocalOnly: false, SeriesList: tsSeries}, nil
 }
 
+// FetchTags searches remote client storage, reading every page of metrics streamed back
 func (c *grpcClient) FetchTags(ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (*storage.SearchResults, error) {
-	return nil, nil
+	id := logging.ReadContextID(ctx)
+	fetchClient, err := c.client.FetchTags(ctx, EncodeFetchTagsMessage(query, options.Limit, id))
+	if err != nil {
+		return nil, err
+	}
+
+	defer fetchClient.CloseSend()
+
+	result := &storage.SearchResults{}
+	for {
+		select {
+		// If query is killed during gRPC streaming, close the channel
+		case <-options.KillChan:
+			return nil, errors.ErrQueryInterrupted
+		default:
+		}
+		page, err := fetchClient.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result.Metrics = append(result.Metrics, DecodeFetchTagsResult(page)...)
+		result.Truncated = result.Truncated || page.GetTruncated()
+	}
+
+	// The remote applies the limit as well, it is enforced again so no more metrics than asked for are returned
+	result.ApplyLimit(options.Limit)
+	return result, nil
 }
 
 // Write writes to remote client storage
@@ -128,9 +172,101 @@ func (c *grpcClient) Write(ctx context.Context, query *storage.WriteQuery) error
 	return err
 }
 
+// FetchBlocks reads step aligned blocks from remote client storage
 func (c *grpcClient) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
-	return storage.BlockResult{}, errors.ErrNotImplemented
+	if query.Interval <= 0 {
+		return storage.BlockResult{}, errors.ErrInvalidStepSize
+	}
+
+	id := logging.ReadContextID(ctx)
+	fetchClient, err := c.client.FetchBlocks(ctx, EncodeFetchMessage(query, id))
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	defer fetchClient.CloseSend()
+
+	// The remote store aligns its blocks to the query bounds, blocks with other bounds cannot be combined
+	// with the blocks of the other stores
+	bounds := storage.Bounds{
+		Start:    query.Start,
+		End:      query.End,
+		StepSize: query.Interval,
+	}
+
+	var blocks []storage.Block
+	for {
+		select {
+		// If query is killed during gRPC streaming, close the channel
+		case <-options.KillChan:
+			return storage.BlockResult{}, errors.ErrQueryInterrupted
+		default:
+		}
+		result, err := fetchClient.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return storage.BlockResult{}, err
+		}
+
+		block, err := DecodeBlock(result)
+		if err != nil {
+			return storage.BlockResult{}, err
+		}
+
+		if !block.Meta().Bounds.Equals(bounds) {
+			return storage.BlockResult{}, errors.ErrRemoteBlockBounds
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return storage.BlockResult{Blocks: blocks}, nil
+}
+
+// ExecuteSubPlan executes a sub plan on the remote coordinator and reads back the resulting blocks
+func (c *grpcClient) ExecuteSubPlan(
+	ctx context.Context, subPlan plan.SubPlan, options *storage.FetchOptions) (storage.BlockResult, error) {
+	id := logging.ReadContextID(ctx)
+	message, err := EncodeSubPlanMessage(subPlan, id)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	executeClient, err := c.client.ExecuteSubPlan(ctx, message)
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	defer executeClient.CloseSend()
+
+	var blocks []storage.Block
+	for {
+		select {
+		// If query is killed during gRPC streaming, close the channel
+		case <-options.KillChan:
+			return storage.BlockResult{}, errors.ErrQueryInterrupted
+		default:
+		}
+		result, err := executeClient.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return storage.BlockResult{}, err
+		}
+
+		block, err := DecodeBlock(result)
+		if err != nil {
+			return storage.BlockResult{}, err
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return storage.BlockResult{Blocks: blocks}, nil
 }
 
 // Close closes the underlying connection
@@ -22,10 +22,16 @@ package remote
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
 	"github.com/m3db/m3coordinator/generated/proto/rpc"
 	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
 
@@ -79,8 +85,13 @@ func decodeTs(ctx context.Context, r *rpc.Series) *ts.Series {
 	}
 
 	start, tags := toTime(r.GetStartTime()), models.Tags(r.GetTags())
+	name := r.GetName()
+	if name == "" {
+		// Fall back to the canonical ID so that series are always identifiable
+		name = tags.ID()
+	}
 
-	series := ts.NewSeries(ctx, r.GetName(), start, values, tags)
+	series := ts.NewSeries(ctx, name, start, values, tags)
 	series.Specification = r.GetSpecification()
 	return series
 }
@@ -99,6 +110,7 @@ func encodeFetchQuery(query *storage.FetchQuery) *rpc.FetchQuery {
 		Start:       fromTime(query.Start),
 		End:         fromTime(query.End),
 		TagMatchers: encodeTagMatchers(query.TagMatchers),
+		Interval:    int64(query.Interval),
 	}
 }
 
@@ -140,6 +152,7 @@ func decodeFetchQuery(query *rpc.FetchQuery) (*storage.FetchQuery, error) {
 		TagMatchers: tags,
 		Start:       toTime(query.Start),
 		End:         toTime(query.End),
+		Interval:    time.Duration(query.Interval),
 	}, nil
 }
 
@@ -156,6 +169,227 @@ func decodeTagMatchers(rpcMatchers []*rpc.Matcher) (models.Matchers, error) {
 	return models.Matchers(matchers), nil
 }
 
+// EncodeFetchTagsMessage encodes a search query and its limit into rpc FetchTagsMessage
+func EncodeFetchTagsMessage(query *storage.FetchQuery, limit int, queryID string) *rpc.FetchTagsMessage {
+	return &rpc.FetchTagsMessage{
+		Query: encodeFetchQuery(query),
+		Options: &rpc.FetchTagsOptions{
+			Id:    queryID,
+			Limit: int64(limit),
+		},
+	}
+}
+
+// DecodeFetchTagsMessage decodes rpc fetch tags message to search query, limit and query id
+func DecodeFetchTagsMessage(message *rpc.FetchTagsMessage) (*storage.FetchQuery, int, string, error) {
+	query, err := decodeFetchQuery(message.GetQuery())
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	options := message.GetOptions()
+	return query, int(options.GetLimit()), options.GetId(), nil
+}
+
+// EncodeFetchTagsResult encodes a page of search results to rpc result
+func EncodeFetchTagsResult(metrics models.Metrics, truncated bool) *rpc.FetchTagsResult {
+	rpcMetrics := make([]*rpc.Metric, len(metrics))
+	for i, metric := range metrics {
+		rpcMetrics[i] = &rpc.Metric{
+			Id:        metric.ID,
+			Namespace: metric.Namespace,
+			Tags:      metric.Tags,
+		}
+	}
+
+	return &rpc.FetchTagsResult{Metrics: rpcMetrics, Truncated: truncated}
+}
+
+// DecodeFetchTagsResult decodes a page of search results from a GRPC-compatible type.
+func DecodeFetchTagsResult(result *rpc.FetchTagsResult) models.Metrics {
+	metrics := make(models.Metrics, len(result.GetMetrics()))
+	for i, metric := range result.GetMetrics() {
+		metrics[i] = &models.Metric{
+			ID:        metric.GetId(),
+			Namespace: metric.GetNamespace(),
+			Tags:      metric.GetTags(),
+		}
+	}
+
+	return metrics
+}
+
+// EncodeSubPlanMessage encodes a sub plan and its query id into rpc SubPlanMessage, only temporal functions
+// and combinable aggregations can be pushed down
+func EncodeSubPlanMessage(subPlan plan.SubPlan, queryID string) (*rpc.SubPlanMessage, error) {
+	if err := subPlan.Validate(); err != nil {
+		return nil, err
+	}
+
+	transforms := make([]*rpc.Transform, len(subPlan.Transforms))
+	for i, op := range subPlan.Transforms {
+		rpcTransform, err := encodeTransform(op)
+		if err != nil {
+			return nil, err
+		}
+
+		transforms[i] = rpcTransform
+	}
+
+	timeSpec := subPlan.TimeSpec
+	return &rpc.SubPlanMessage{
+		Plan: &rpc.SubPlan{
+			Start: fromTime(timeSpec.Start),
+			End:   fromTime(timeSpec.End),
+			Now:   fromTime(timeSpec.Now),
+			Step:  int64(timeSpec.Step),
+			Fetch: &rpc.FetchOp{
+				Name:     subPlan.Fetch.Name,
+				Range:    int64(subPlan.Fetch.Range),
+				Offset:   int64(subPlan.Fetch.Offset),
+				Matchers: encodeTagMatchers(subPlan.Fetch.Matchers),
+			},
+			Transforms: transforms,
+		},
+		Options: encodeFetchOptions(queryID),
+	}, nil
+}
+
+func encodeTransform(op parser.Params) (*rpc.Transform, error) {
+	switch o := op.(type) {
+	case functions.TemporalOp:
+		return &rpc.Transform{
+			Temporal: &rpc.TemporalTransform{
+				Type:      o.OperatorType,
+				Duration:  int64(o.Duration),
+				Parameter: o.Parameter,
+			},
+		}, nil
+	case functions.AggregationOp:
+		return &rpc.Transform{
+			Aggregation: &rpc.AggregationTransform{
+				Type:            o.OperatorType,
+				MatchingTags:    o.Params.MatchingTags,
+				Without:         o.Params.Without,
+				Parameter:       o.Params.Parameter,
+				StringParameter: o.Params.StringParameter,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("transform %s cannot be pushed down", op.OpType())
+	}
+}
+
+// DecodeSubPlanMessage decodes rpc sub plan message to a sub plan and query id
+func DecodeSubPlanMessage(message *rpc.SubPlanMessage) (plan.SubPlan, string, error) {
+	rpcPlan := message.GetPlan()
+	rpcFetch := rpcPlan.GetFetch()
+	matchers, err := decodeTagMatchers(rpcFetch.GetMatchers())
+	if err != nil {
+		return plan.SubPlan{}, "", err
+	}
+
+	transforms := make([]parser.Params, len(rpcPlan.GetTransforms()))
+	for i, rpcTransform := range rpcPlan.GetTransforms() {
+		op, err := decodeTransform(rpcTransform)
+		if err != nil {
+			return plan.SubPlan{}, "", err
+		}
+
+		transforms[i] = op
+	}
+
+	return plan.SubPlan{
+		Fetch: functions.FetchOp{
+			Name:     rpcFetch.GetName(),
+			Range:    time.Duration(rpcFetch.GetRange()),
+			Offset:   time.Duration(rpcFetch.GetOffset()),
+			Matchers: matchers,
+		},
+		Transforms: transforms,
+		TimeSpec: transform.TimeSpec{
+			Start: toTime(rpcPlan.GetStart()),
+			End:   toTime(rpcPlan.GetEnd()),
+			Now:   toTime(rpcPlan.GetNow()),
+			Step:  time.Duration(rpcPlan.GetStep()),
+		},
+	}, message.GetOptions().GetId(), nil
+}
+
+func decodeTransform(rpcTransform *rpc.Transform) (parser.Params, error) {
+	temporal, aggregation := rpcTransform.GetTemporal(), rpcTransform.GetAggregation()
+	switch {
+	case temporal != nil && aggregation == nil:
+		return functions.TemporalOp{
+			OperatorType: temporal.GetType(),
+			Duration:     time.Duration(temporal.GetDuration()),
+			Parameter:    temporal.GetParameter(),
+		}, nil
+	case aggregation != nil && temporal == nil:
+		return functions.AggregationOp{
+			OperatorType: aggregation.GetType(),
+			Params: functions.AggregationParams{
+				MatchingTags:    aggregation.GetMatchingTags(),
+				Without:         aggregation.GetWithout(),
+				Parameter:       aggregation.GetParameter(),
+				StringParameter: aggregation.GetStringParameter(),
+			},
+		}, nil
+	default:
+		return nil, errors.ErrInvalidTransform
+	}
+}
+
+// EncodeBlock encodes a step aligned block to rpc Block along with its bounds, values keep their full precision
+func EncodeBlock(block storage.Block) *rpc.Block {
+	meta := block.Meta()
+	series := make([]*rpc.BlockSeries, 0, len(block.SeriesMeta()))
+	iter := block.SeriesIter()
+	for iter.Next() {
+		s := iter.Current()
+		values := make([]float64, s.Len())
+		for i := range values {
+			values[i] = s.ValueAt(i)
+		}
+
+		series = append(series, &rpc.BlockSeries{
+			Name:   s.Name(),
+			Tags:   s.Tags,
+			Values: values,
+		})
+	}
+
+	return &rpc.Block{
+		Start:    fromTime(meta.Bounds.Start),
+		End:      fromTime(meta.Bounds.End),
+		StepSize: int64(meta.Bounds.StepSize),
+		Tags:     meta.Tags,
+		Series:   series,
+	}
+}
+
+// DecodeBlock decodes a step aligned block from a GRPC-compatible type.
+func DecodeBlock(rpcBlock *rpc.Block) (storage.Block, error) {
+	meta := storage.BlockMetadata{
+		Bounds: storage.Bounds{
+			Start:    toTime(rpcBlock.GetStart()),
+			End:      toTime(rpcBlock.GetEnd()),
+			StepSize: time.Duration(rpcBlock.GetStepSize()),
+		},
+		Tags: rpcBlock.GetTags(),
+	}
+
+	rpcSeries := rpcBlock.GetSeries()
+	seriesMeta := make([]storage.SeriesMeta, len(rpcSeries))
+	values := make([][]float64, len(rpcSeries))
+	for i, series := range rpcSeries {
+		seriesMeta[i] = storage.SeriesMeta{Name: series.GetName(), Tags: series.GetTags()}
+		values[i] = series.GetValues()
+	}
+
+	return storage.NewSeriesBlock(meta, seriesMeta, values)
+}
+
 // EncodeWriteMessage encodes write query and write options into rpc WriteMessage
 func EncodeWriteMessage(query *storage.WriteQuery, queryID string) *rpc.WriteMessage {
 	return &rpc.WriteMessage{
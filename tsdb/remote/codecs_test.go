@@ -22,11 +22,17 @@ package remote
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
+	m3err "github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
 	"github.com/m3db/m3coordinator/generated/proto/rpc"
 	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
 
@@ -128,9 +134,20 @@ func TestDecodeFetchResult(t *testing.T) {
 	assert.Equal(t, rpcSeries, revert.GetSeries())
 }
 
+func TestDecodeFetchResultWithoutName(t *testing.T) {
+	rpcSeries, _, _ := createRPCSeries(t)
+	rpcSeries[0].Name = ""
+
+	tsSeries := DecodeFetchResult(context.Background(), rpcSeries)
+	require.Len(t, tsSeries, 2)
+	assert.Equal(t, models.Tags(tags0).ID(), tsSeries[0].Name())
+	assert.Equal(t, name1, tsSeries[1].Name())
+}
+
 func readQueriesAreEqual(t *testing.T, this, other *storage.FetchQuery) {
 	assert.True(t, this.Start.Equal(other.Start))
 	assert.True(t, this.End.Equal(other.End))
+	assert.Equal(t, this.Interval, other.Interval)
 	assert.Equal(t, len(this.TagMatchers), len(other.TagMatchers))
 	assert.Equal(t, 2, len(other.TagMatchers))
 	for i, matcher := range this.TagMatchers {
@@ -152,6 +169,7 @@ func createStorageFetchQuery(t *testing.T) (*storage.FetchQuery, time.Time, time
 		TagMatchers: matchers,
 		Start:       start,
 		End:         end,
+		Interval:    time.Minute,
 	}, start, end
 }
 
@@ -162,6 +180,7 @@ func TestEncodeFetchMessage(t *testing.T) {
 	require.NotNil(t, grpcQ)
 	assert.Equal(t, fromTime(start), grpcQ.GetQuery().GetStart())
 	assert.Equal(t, fromTime(end), grpcQ.GetQuery().GetEnd())
+	assert.Equal(t, int64(time.Minute), grpcQ.GetQuery().GetInterval())
 	mRPC := grpcQ.GetQuery().GetTagMatchers()
 	assert.Equal(t, 2, len(mRPC))
 	assert.Equal(t, name0, mRPC[0].GetName())
@@ -186,6 +205,146 @@ func TestEncodeDecodeFetchQuery(t *testing.T) {
 	assert.Equal(t, gq, gqr)
 }
 
+func TestEncodeDecodeFetchTagsMessage(t *testing.T) {
+	rQ, _, _ := createStorageFetchQuery(t)
+	message := EncodeFetchTagsMessage(rQ, 10, id)
+	assert.Equal(t, int64(10), message.GetOptions().GetLimit())
+
+	reverted, limit, decodeID, err := DecodeFetchTagsMessage(message)
+	require.NoError(t, err)
+	assert.Equal(t, 10, limit)
+	assert.Equal(t, id, decodeID)
+	readQueriesAreEqual(t, rQ, reverted)
+}
+
+func TestEncodeDecodeFetchTagsResult(t *testing.T) {
+	metrics := models.Metrics{
+		{ID: name0, Namespace: spec0, Tags: tags0},
+		{ID: name1, Namespace: spec1, Tags: tags1},
+	}
+
+	encoded := EncodeFetchTagsResult(metrics, true)
+	assert.True(t, encoded.GetTruncated())
+	require.Len(t, encoded.GetMetrics(), 2)
+	assert.Equal(t, name0, encoded.GetMetrics()[0].GetId())
+
+	assert.Equal(t, metrics, DecodeFetchTagsResult(encoded))
+}
+
+func createSubPlan(t *testing.T) plan.SubPlan {
+	rQ, start, end := createStorageFetchQuery(t)
+	return plan.SubPlan{
+		Fetch: functions.FetchOp{Name: name0, Range: time.Minute, Offset: time.Hour, Matchers: rQ.TagMatchers},
+		Transforms: []parser.Params{
+			functions.TemporalOp{OperatorType: functions.QuantileOverTimeType, Duration: time.Minute, Parameter: 0.9},
+			functions.AggregationOp{
+				OperatorType: functions.TopKType,
+				Params:       functions.AggregationParams{MatchingTags: []string{"a"}, Without: true, Parameter: 2},
+			},
+		},
+		TimeSpec: transform.TimeSpec{Start: start, End: end, Now: end, Step: time.Minute},
+	}
+}
+
+func TestEncodeDecodeSubPlanMessage(t *testing.T) {
+	subPlan := createSubPlan(t)
+	message, err := EncodeSubPlanMessage(subPlan, id)
+	require.NoError(t, err)
+	require.Len(t, message.GetPlan().GetTransforms(), 2)
+	assert.Equal(t, 0.9, message.GetPlan().GetTransforms()[0].GetTemporal().GetParameter())
+	assert.Nil(t, message.GetPlan().GetTransforms()[0].GetAggregation())
+
+	decoded, decodeID, err := DecodeSubPlanMessage(message)
+	require.NoError(t, err)
+	assert.Equal(t, id, decodeID)
+	assert.Equal(t, subPlan.Transforms, decoded.Transforms)
+	assert.Equal(t, subPlan.Fetch.Name, decoded.Fetch.Name)
+	assert.Equal(t, subPlan.Fetch.Range, decoded.Fetch.Range)
+	assert.Equal(t, subPlan.Fetch.Offset, decoded.Fetch.Offset)
+	readQueriesAreEqual(t, &storage.FetchQuery{TagMatchers: subPlan.Fetch.Matchers},
+		&storage.FetchQuery{TagMatchers: decoded.Fetch.Matchers})
+	assert.True(t, subPlan.TimeSpec.Start.Equal(decoded.TimeSpec.Start))
+	assert.True(t, subPlan.TimeSpec.End.Equal(decoded.TimeSpec.End))
+	assert.True(t, subPlan.TimeSpec.Now.Equal(decoded.TimeSpec.Now))
+	assert.Equal(t, subPlan.TimeSpec.Step, decoded.TimeSpec.Step)
+
+	// Encode again
+	encoded, err := EncodeSubPlanMessage(decoded, decodeID)
+	require.NoError(t, err)
+	assert.Equal(t, message, encoded)
+}
+
+func TestEncodeSubPlanMessageUnsupportedTransform(t *testing.T) {
+	subPlan := createSubPlan(t)
+	subPlan.Transforms = append(subPlan.Transforms, functions.BinaryOp{OperatorType: functions.PlusType})
+	_, err := EncodeSubPlanMessage(subPlan, id)
+	assert.Error(t, err)
+}
+
+func TestEncodeSubPlanMessageNonCombinableAggregation(t *testing.T) {
+	subPlan := createSubPlan(t)
+	subPlan.Transforms[1] = functions.AggregationOp{OperatorType: functions.AvgType}
+	_, err := EncodeSubPlanMessage(subPlan, id)
+	assert.Error(t, err)
+}
+
+func TestDecodeSubPlanMessageInvalidTransform(t *testing.T) {
+	message, err := EncodeSubPlanMessage(createSubPlan(t), id)
+	require.NoError(t, err)
+
+	transforms := message.GetPlan().GetTransforms()
+	transforms[0].Aggregation = transforms[1].GetAggregation()
+	_, _, err = DecodeSubPlanMessage(message)
+	assert.Equal(t, m3err.ErrInvalidTransform, err)
+
+	transforms[0] = &rpc.Transform{}
+	_, _, err = DecodeSubPlanMessage(message)
+	assert.Equal(t, m3err.ErrInvalidTransform, err)
+}
+
+func TestEncodeDecodeBlock(t *testing.T) {
+	start, _ := parseTimes(t)
+	bounds := storage.Bounds{Start: start, End: start.Add(2 * time.Minute), StepSize: time.Minute}
+	// None of these values survive a conversion to float32
+	vals := [][]float64{{0.1, math.NaN(), 1e300}, {math.Pi, -math.MaxFloat64, 1 + 1e-12}}
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds, Tags: tags0},
+		[]storage.SeriesMeta{{Name: name0, Tags: tags0}, {Name: name1, Tags: tags1}}, vals)
+	require.NoError(t, err)
+
+	encoded := EncodeBlock(block)
+	require.Len(t, encoded.GetSeries(), 2)
+	assert.Equal(t, vals[1], encoded.GetSeries()[1].GetValues())
+
+	// Go over the wire to make sure values keep their precision once marshalled
+	data, err := encoded.Marshal()
+	require.NoError(t, err)
+	unmarshalled := &rpc.Block{}
+	require.NoError(t, unmarshalled.Unmarshal(data))
+
+	decoded, err := DecodeBlock(unmarshalled)
+	require.NoError(t, err)
+	assert.True(t, decoded.Meta().Bounds.Equals(bounds))
+	assert.Equal(t, models.Tags(tags0), decoded.Meta().Tags)
+	assert.Equal(t, block.SeriesMeta(), decoded.SeriesMeta())
+
+	iter := decoded.SeriesIter()
+	for _, expected := range vals {
+		require.True(t, iter.Next())
+		series := iter.Current()
+		require.Equal(t, len(expected), series.Len())
+		for i, v := range expected {
+			if math.IsNaN(v) {
+				assert.True(t, math.IsNaN(series.ValueAt(i)))
+				continue
+			}
+
+			assert.Equal(t, v, series.ValueAt(i))
+		}
+	}
+
+	assert.False(t, iter.Next())
+}
+
 func createStorageWriteQuery(t *testing.T) (*storage.WriteQuery, ts.Datapoints) {
 	t0, t1 := parseTimes(t)
 	points := []*ts.Datapoint{
@@ -24,6 +24,7 @@ import (
 	"io"
 	"net"
 
+	"github.com/m3db/m3coordinator/executor"
 	"github.com/m3db/m3coordinator/generated/proto/rpc"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/util/logging"
@@ -32,6 +33,9 @@ import (
 	"google.golang.org/grpc"
 )
 
+// fetchTagsPageSize is the most metrics sent in a single FetchTags result
+const fetchTagsPageSize = 1000
+
 type grpcServer struct {
 	storage storage.Storage
 }
@@ -93,6 +97,118 @@ func (s *grpcServer) Fetch(message *rpc.FetchMessage, stream rpc.Query_FetchServ
 	return nil
 }
 
+// FetchBlocks reads step aligned blocks from local storage
+func (s *grpcServer) FetchBlocks(message *rpc.FetchMessage, stream rpc.Query_FetchBlocksServer) error {
+	storeQuery, id, err := DecodeFetchMessage(message)
+	ctx := logging.NewContextWithID(stream.Context(), id)
+	logger := logging.WithContext(ctx)
+
+	if err != nil {
+		logger.Error("unable to decode fetch query", zap.Any("error", err))
+		return err
+	}
+
+	result, err := s.storage.FetchBlocks(ctx, storeQuery, &storage.FetchOptions{})
+	if err != nil {
+		logger.Error("unable to fetch local blocks", zap.Any("error", err))
+		return err
+	}
+
+	defer closeBlocks(logger, result.Blocks)
+	for _, block := range result.Blocks {
+		if err := stream.Send(EncodeBlock(block)); err != nil {
+			logger.Error("unable to send fetch blocks result", zap.Any("error", err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchTags searches local storage and streams the matching metrics in pages, the last page
+// says whether the metrics were truncated by the limit
+func (s *grpcServer) FetchTags(message *rpc.FetchTagsMessage, stream rpc.Query_FetchTagsServer) error {
+	storeQuery, limit, id, err := DecodeFetchTagsMessage(message)
+	ctx := logging.NewContextWithID(stream.Context(), id)
+	logger := logging.WithContext(ctx)
+
+	if err != nil {
+		logger.Error("unable to decode fetch tags query", zap.Any("error", err))
+		return err
+	}
+
+	result, err := s.storage.FetchTags(ctx, storeQuery, &storage.FetchOptions{Limit: limit})
+	if err != nil {
+		logger.Error("unable to fetch local tags", zap.Any("error", err))
+		return err
+	}
+
+	result.ApplyLimit(limit)
+	metrics := result.Metrics
+	for {
+		page := metrics
+		if len(page) > fetchTagsPageSize {
+			page = page[:fetchTagsPageSize]
+		}
+
+		metrics = metrics[len(page):]
+		last := len(metrics) == 0
+		if err := stream.Send(EncodeFetchTagsResult(page, last && result.Truncated)); err != nil {
+			logger.Error("unable to send fetch tags result", zap.Any("error", err))
+			return err
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
+
+// ExecuteSubPlan executes a fetch and the transforms pushed down to it against local storage, then streams
+// the resulting blocks
+func (s *grpcServer) ExecuteSubPlan(message *rpc.SubPlanMessage, stream rpc.Query_ExecuteSubPlanServer) error {
+	subPlan, id, err := DecodeSubPlanMessage(message)
+	ctx := logging.NewContextWithID(stream.Context(), id)
+	logger := logging.WithContext(ctx)
+
+	if err != nil {
+		logger.Error("unable to decode sub plan", zap.Any("error", err))
+		return err
+	}
+
+	// Clients may send transforms whose results could not be combined with those of other datacenters
+	if err := subPlan.Validate(); err != nil {
+		logger.Error("unable to push down sub plan", zap.Any("error", err))
+		return err
+	}
+
+	result, err := executor.ExecuteSubPlan(ctx, s.storage, subPlan, nil)
+	if err != nil {
+		logger.Error("unable to execute sub plan", zap.Any("error", err))
+		return err
+	}
+
+	blocks := result.Blocks
+	defer closeBlocks(logger, blocks)
+	for _, block := range blocks {
+		if err := stream.Send(EncodeBlock(block)); err != nil {
+			logger.Error("unable to send sub plan block", zap.Any("error", err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// closeBlocks closes blocks once they are sent, or failed to be sent
+func closeBlocks(logger *zap.Logger, blocks []storage.Block) {
+	for _, block := range blocks {
+		if err := block.Close(); err != nil {
+			logger.Error("unable to close block", zap.Any("error", err))
+		}
+	}
+}
+
 // Write writes to local storage
 func (s *grpcServer) Write(stream rpc.Query_WriteServer) error {
 	for {
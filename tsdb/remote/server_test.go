@@ -29,7 +29,11 @@ import (
 	"time"
 
 	m3err "github.com/m3db/m3coordinator/errors"
+	"github.com/m3db/m3coordinator/executor/transform"
+	"github.com/m3db/m3coordinator/functions"
 	"github.com/m3db/m3coordinator/models"
+	"github.com/m3db/m3coordinator/parser"
+	"github.com/m3db/m3coordinator/plan"
 	"github.com/m3db/m3coordinator/storage"
 	"github.com/m3db/m3coordinator/ts"
 	"github.com/m3db/m3coordinator/util/logging"
@@ -80,6 +84,7 @@ type mockStorage struct {
 	write       *storage.WriteQuery
 	sleepMillis int
 	numPages    int
+	numMetrics  int
 	mu          sync.Mutex
 }
 
@@ -104,7 +109,14 @@ func (s *mockStorage) Fetch(ctx context.Context, query *storage.FetchQuery, _ *s
 }
 
 func (s *mockStorage) FetchTags(ctx context.Context, query *storage.FetchQuery, _ *storage.FetchOptions) (*storage.SearchResults, error) {
-	return nil, nil
+	readQueriesAreEqual(s.t, s.read, query)
+
+	metrics := make(models.Metrics, s.numMetrics)
+	for i := range metrics {
+		metrics[i] = &models.Metric{ID: fmt.Sprintf("%s%d", name, i), Namespace: "metrics", Tags: tags}
+	}
+
+	return &storage.SearchResults{Metrics: metrics}, nil
 }
 
 func (s *mockStorage) Write(ctx context.Context, query *storage.WriteQuery) error {
@@ -118,7 +130,17 @@ func (s *mockStorage) Type() storage.Type {
 
 func (s *mockStorage) FetchBlocks(
 	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
-	return storage.BlockResult{}, fmt.Errorf("not implemented")
+	readQueriesAreEqual(s.t, s.read, query)
+	assert.Equal(s.t, s.read.Interval, query.Interval)
+
+	bounds := storage.Bounds{Start: query.Start, End: query.End, StepSize: query.Interval}
+	block, err := storage.NewSeriesBlock(storage.BlockMetadata{Bounds: bounds},
+		[]storage.SeriesMeta{{Name: name, Tags: tags}}, [][]float64{values})
+	if err != nil {
+		return storage.BlockResult{}, err
+	}
+
+	return storage.BlockResult{Blocks: []storage.Block{block}}, nil
 }
 
 func (s *mockStorage) Close() error {
@@ -297,6 +319,183 @@ func TestMultipleClientRpc(t *testing.T) {
 	wg.Wait()
 }
 
+func TestRpcFetchBlocks(t *testing.T) {
+	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
+	read.Start = startTime
+	read.End = startTime.Add(3 * time.Minute)
+	read.Interval = time.Minute
+	store := &mockStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+	hosts := []string{host}
+	client, err := NewGrpcClient(hosts, grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		err = client.Close()
+		assert.NoError(t, err)
+	}()
+
+	result, err := client.FetchBlocks(ctx, read, readOpts)
+	require.NoError(t, err)
+	require.Len(t, result.Blocks, 1)
+	block := result.Blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(storage.Bounds{Start: read.Start, End: read.End, StepSize: time.Minute}))
+
+	iter := block.SeriesIter()
+	require.True(t, iter.Next())
+	series := iter.Current()
+	assert.Equal(t, name, series.Name())
+	assert.Equal(t, tags, series.Tags)
+	require.Equal(t, len(values), series.Len())
+	for i, v := range values {
+		assert.Equal(t, v, series.ValueAt(i))
+	}
+
+	assert.False(t, iter.Next())
+}
+
+// shiftedBlocksStorage returns blocks which start a step after the query
+type shiftedBlocksStorage struct {
+	*mockStorage
+}
+
+func (s *shiftedBlocksStorage) FetchBlocks(
+	ctx context.Context, query *storage.FetchQuery, options *storage.FetchOptions) (storage.BlockResult, error) {
+	shifted := *query
+	shifted.Start = query.Start.Add(query.Interval)
+	shifted.End = query.End.Add(query.Interval)
+	s.read = &shifted
+	return s.mockStorage.FetchBlocks(ctx, &shifted, options)
+}
+
+func TestRpcFetchBlocksBoundsMismatch(t *testing.T) {
+	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
+	read.Start = startTime
+	read.End = startTime.Add(3 * time.Minute)
+	read.Interval = time.Minute
+	store := &shiftedBlocksStorage{mockStorage: &mockStorage{t: t, read: read, write: write}}
+	startServer(t, host, store)
+	hosts := []string{host}
+	client, err := NewGrpcClient(hosts, grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		err = client.Close()
+		assert.NoError(t, err)
+	}()
+
+	_, err = client.FetchBlocks(ctx, read, readOpts)
+	assert.Equal(t, m3err.ErrRemoteBlockBounds, err)
+}
+
+func TestRpcFetchTags(t *testing.T) {
+	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
+	numMetrics := 2*fetchTagsPageSize + 1
+	store := &mockStorage{
+		t:          t,
+		read:       read,
+		write:      write,
+		numMetrics: numMetrics,
+	}
+	startServer(t, host, store)
+	hosts := []string{host}
+	client, err := NewGrpcClient(hosts, grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		err = client.Close()
+		assert.NoError(t, err)
+	}()
+
+	// Every page is read back
+	result, err := client.FetchTags(ctx, read, readOpts)
+	require.NoError(t, err)
+	require.Len(t, result.Metrics, numMetrics)
+	assert.False(t, result.Truncated)
+	assert.Equal(t, &models.Metric{ID: name + "0", Namespace: "metrics", Tags: tags}, result.Metrics[0])
+	assert.Equal(t, fmt.Sprintf("%s%d", name, numMetrics-1), result.Metrics[numMetrics-1].ID)
+
+	readOpts.Limit = fetchTagsPageSize + 1
+	result, err = client.FetchTags(ctx, read, readOpts)
+	require.NoError(t, err)
+	assert.Len(t, result.Metrics, fetchTagsPageSize+1)
+	assert.True(t, result.Truncated)
+}
+
+func TestRpcExecuteSubPlan(t *testing.T) {
+	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
+	read.Start = startTime
+	read.End = startTime.Add(3 * time.Minute)
+	read.Interval = time.Minute
+	store := &mockStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+	hosts := []string{host}
+	client, err := NewGrpcClient(hosts, grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		err = client.Close()
+		assert.NoError(t, err)
+	}()
+
+	subPlan := plan.SubPlan{
+		Fetch: functions.FetchOp{Name: name, Matchers: read.TagMatchers},
+		Transforms: []parser.Params{
+			functions.AggregationOp{
+				OperatorType: functions.SumType,
+				Params:       functions.AggregationParams{MatchingTags: []string{"1"}},
+			},
+		},
+		TimeSpec: transform.TimeSpec{Start: read.Start, End: read.End, Now: read.End, Step: read.Interval},
+	}
+
+	result, err := client.ExecuteSubPlan(ctx, subPlan, readOpts)
+	require.NoError(t, err)
+	require.Len(t, result.Blocks, 1)
+	block := result.Blocks[0]
+	assert.True(t, block.Meta().Bounds.Equals(storage.Bounds{Start: read.Start, End: read.End, StepSize: time.Minute}))
+
+	iter := block.SeriesIter()
+	require.True(t, iter.Next())
+	series := iter.Current()
+	assert.Equal(t, "b", series.Tags["1"])
+	require.Equal(t, len(values), series.Len())
+	for i, v := range values {
+		assert.Equal(t, v, series.ValueAt(i))
+	}
+
+	assert.False(t, iter.Next())
+}
+
+func TestRpcExecuteSubPlanError(t *testing.T) {
+	ctx, read, write, readOpts, host := createCtxReadWriteOpts(t)
+	store := &errStorage{
+		t:     t,
+		read:  read,
+		write: write,
+	}
+	startServer(t, host, store)
+	hosts := []string{host}
+	client, err := NewGrpcClient(hosts, grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		err = client.Close()
+		assert.NoError(t, err)
+	}()
+
+	subPlan := plan.SubPlan{
+		Fetch:    functions.FetchOp{Name: name, Matchers: read.TagMatchers},
+		TimeSpec: transform.TimeSpec{Start: read.Start, End: read.End, Now: read.End, Step: time.Minute},
+	}
+
+	_, err = client.ExecuteSubPlan(ctx, subPlan, readOpts)
+	assert.Error(t, err)
+}
+
 type errStorage struct {
 	t     *testing.T
 	read  *storage.FetchQuery